@@ -0,0 +1,229 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// apiVersion is the version of the published JSON API. It only changes when fields are
+// removed or change meaning; new fields may be added without a version change.
+const apiVersion = 1
+
+// APIIndex is the document published at api/index.json
+type APIIndex struct {
+	APIVersion  int              `json:"api_version"`
+	Owner       string           `json:"owner"`
+	LastCrawled time.Time        `json:"last_crawled,omitzero"`
+	Repos       []APIRepoSummary `json:"repos"`
+}
+
+// APIRepoSummary describes one repository in api/index.json. PageURL and APIURL are
+// relative to the root of the generated site
+type APIRepoSummary struct {
+	Name                 string        `json:"name"`
+	RepositoryURL        string        `json:"repository_url"`
+	PageURL              string        `json:"page_url,omitempty"`
+	APIURL               string        `json:"api_url"`
+	DefaultBranch        string        `json:"default_branch"`
+	NeverReleased        bool          `json:"never_released"`
+	BaselineType         string        `json:"baseline_type,omitempty"`
+	BaselineTag          string        `json:"baseline_tag,omitempty"`
+	BaselineTime         time.Time     `json:"baseline_time,omitzero"`
+	NextVersion          string        `json:"next_version,omitempty"`
+	UnreleasedCommits    int           `json:"unreleased_commits"`
+	DependencyUpdates    int           `json:"dependency_updates"`
+	BreakingChanges      int           `json:"breaking_changes"`
+	SecurityFixes        int           `json:"security_fixes"`
+	SecurityFixDays      int           `json:"security_fix_days"`
+	OpenAlerts           int           `json:"open_alerts"`
+	UnreleasedAlertFixes int           `json:"unreleased_alert_fixes"`
+	OpenPullRequests     *int          `json:"open_pull_requests,omitempty"`
+	DaysBehind           int           `json:"days_behind"`
+	DaysSinceRelease     int           `json:"days_since_release"`
+	Archived             bool          `json:"archived"`
+	Deprecated           bool          `json:"deprecated"`
+	Language             string        `json:"language,omitempty"`
+	Topics               []string      `json:"topics"`
+	Stars                int           `json:"stars"`
+	Forks                int           `json:"forks"`
+	OpenIssues           int           `json:"open_issues"`
+	Impact               int           `json:"impact"`
+	License              string        `json:"license,omitempty"`
+	BehindBy             int           `json:"behind_by"`
+	Channels             []ChannelInfo `json:"channels,omitempty"`
+}
+
+// APIRepo is the document published at api/repos/<name>.json
+type APIRepo struct {
+	APIVersion int `json:"api_version"`
+	APIRepoSummary
+	Owner        string            `json:"owner"`
+	Description  string            `json:"description,omitempty"`
+	TotalCommits int               `json:"total_commits,omitempty"`
+	Commits      []APICommit       `json:"commits"`
+	CherryPicked []APICommit       `json:"cherry_picked"`
+	PullRequests []PullRequestInfo `json:"pull_requests"`
+	History      []HistoryPoint    `json:"unreleased_history"`
+}
+
+// APICommit is a single unreleased commit in the JSON API
+type APICommit struct {
+	SHA         string    `json:"sha"`
+	Author      string    `json:"author"`
+	Subject     string    `json:"subject"`
+	Message     string    `json:"message"`
+	Timestamp   time.Time `json:"timestamp"`
+	URL         string    `json:"url"`
+	IsMerge     bool      `json:"is_merge"`
+	Dependency  bool      `json:"dependency"`
+	Breaking    bool      `json:"breaking"`
+	Security    bool      `json:"security"`
+	PullRequest int       `json:"pull_request,omitempty"`
+	Labels      []string  `json:"labels,omitempty"`
+}
+
+// generateAPI publishes the site data as JSON under outputDir/api, with an index of
+// every repository and a detail document per repository.
+func generateAPI(outputDir string, repos []RepositoryData, lastCrawled time.Time) error {
+	apiDir := filepath.Join(outputDir, "api")
+	reposDir := filepath.Join(apiDir, "repos")
+	if err := os.MkdirAll(reposDir, 0755); err != nil {
+		return err
+	}
+
+	index := APIIndex{
+		APIVersion:  apiVersion,
+		LastCrawled: lastCrawled,
+		Repos:       []APIRepoSummary{},
+	}
+	if len(repos) > 0 {
+		index.Owner = repos[0].Owner
+	}
+
+	current := make(map[string]bool)
+	for _, repo := range repos {
+		summary := apiRepoSummary(repo)
+		index.Repos = append(index.Repos, summary)
+
+		if repo.PullRequests == nil {
+			repo.PullRequests = []PullRequestInfo{}
+		}
+		if repo.UnreleasedHistory == nil {
+			repo.UnreleasedHistory = []HistoryPoint{}
+		}
+		detail := APIRepo{
+			APIVersion:     apiVersion,
+			APIRepoSummary: summary,
+			Owner:          repo.Owner,
+			Description:    repo.Description,
+			TotalCommits:   repo.TotalCommits,
+			Commits:        apiCommits(repo.UnreleasedCommits),
+			CherryPicked:   apiCommits(repo.CherryPickedCommits),
+			PullRequests:   repo.PullRequests,
+			History:        repo.UnreleasedHistory,
+		}
+
+		filename := repo.Name + ".json"
+		current[filename] = true
+		if err := writeAPIDocument(filepath.Join(reposDir, filename), detail); err != nil {
+			return fmt.Errorf("failed to write API document for %s: %w", repo.Name, err)
+		}
+	}
+
+	if err := writeAPIDocument(filepath.Join(apiDir, "index.json"), index); err != nil {
+		return fmt.Errorf("failed to write API index: %w", err)
+	}
+
+	// Remove documents for repositories that are no longer part of the site
+	entries, err := os.ReadDir(reposDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".json") && !current[entry.Name()] {
+			if err := os.Remove(filepath.Join(reposDir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func apiRepoSummary(repo RepositoryData) APIRepoSummary {
+	summary := APIRepoSummary{
+		Name:                 repo.Name,
+		RepositoryURL:        repo.RepositoryURL,
+		APIURL:               "api/repos/" + repo.Name + ".json",
+		DefaultBranch:        repo.DefaultBranch,
+		NeverReleased:        repo.NeverReleased,
+		UnreleasedCommits:    len(repo.UnreleasedCommits),
+		DependencyUpdates:    countDependencyUpdates(repo.UnreleasedCommits),
+		BreakingChanges:      countBreakingChanges(repo.UnreleasedCommits),
+		SecurityFixes:        countSecurityFixes(repo.UnreleasedCommits),
+		SecurityFixDays:      securityFixDays(repo.UnreleasedCommits),
+		OpenAlerts:           countAlerts(repo.DependabotAlerts, AlertOpen),
+		UnreleasedAlertFixes: countAlerts(repo.DependabotAlerts, AlertFixed),
+		OpenPullRequests:     repo.OpenPullRequests,
+		Archived:             repo.Archived,
+		Deprecated:           repo.Deprecated,
+		Language:             repo.Language,
+		Topics:               repo.Topics,
+		Stars:                repo.Stars,
+		Forks:                repo.Forks,
+		OpenIssues:           repo.OpenIssues,
+		License:              repo.License,
+		BehindBy:             repo.BehindBy,
+		Channels:             repo.Channels,
+	}
+	if summary.Topics == nil {
+		summary.Topics = []string{}
+	}
+	summary.Impact = impactScore(summary.UnreleasedCommits, repo.Stars, repo.Forks)
+	if repo.NeverReleased {
+		summary.UnreleasedCommits = repo.TotalCommits
+		return summary
+	}
+
+	summary.PageURL = repo.Name + ".html"
+	summary.BaselineType = repo.BaselineType
+	summary.BaselineTag = repo.LatestReleaseTag
+	summary.BaselineTime = repo.LatestReleaseTime
+	summary.NextVersion = suggestNextVersion(repo.LatestReleaseTag, repo.UnreleasedCommits)
+	summary.DaysBehind = repoDaysBehind(repo)
+	summary.DaysSinceRelease, _ = repoAges(repo)
+	return summary
+}
+
+func apiCommits(commits []CommitInfo) []APICommit {
+	result := make([]APICommit, 0, len(commits))
+	for _, c := range commits {
+		result = append(result, APICommit{
+			SHA:         c.SHA,
+			Author:      c.Author,
+			Subject:     commitSubject(c.Message),
+			Message:     c.Message,
+			Timestamp:   c.Timestamp,
+			URL:         c.URL,
+			IsMerge:     c.IsMerge,
+			Dependency:  isDependencyUpdate(c),
+			Breaking:    isBreakingChange(c.Message),
+			Security:    isSecurityFix(c.Message),
+			PullRequest: c.PullRequest,
+			Labels:      c.Labels,
+		})
+	}
+	return result
+}
+
+// writeAPIDocument writes v as indented JSON, leaving the file untouched when unchanged.
+func writeAPIDocument(filename string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileIfChanged(filename, append(data, '\n'))
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"html/template"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// assetNames maps each static asset to the fingerprinted file name it was written as
+var assetNames = map[string]string{}
+
+// assetPath returns the fingerprinted file name for an asset such as style.css, so pages
+// always reference the current content and can be cached indefinitely.
+func assetPath(name string) string {
+	if fingerprinted, ok := assetNames[name]; ok {
+		return fingerprinted
+	}
+	return name
+}
+
+// fingerprintedName inserts a short content hash before the extension, e.g. style.1a2b3c4d.css.
+func fingerprintedName(name string, content []byte) string {
+	sum := sha256.Sum256(content)
+	ext := filepath.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + hex.EncodeToString(sum[:4]) + ext
+}
+
+// writeFingerprintedAsset copies an embedded asset into outputDir under its fingerprinted
+// name, records that name for the templates, and removes older fingerprinted copies.
+func writeFingerprintedAsset(outputDir, name string) error {
+	content, err := readEmbeddedFile(templateFS, "templates/"+name)
+	if err != nil {
+		return err
+	}
+
+	fingerprinted := fingerprintedName(name, content)
+	if err := writeFileIfChanged(filepath.Join(outputDir, fingerprinted), content); err != nil {
+		return err
+	}
+	assetNames[name] = fingerprinted
+
+	ext := filepath.Ext(name)
+	stale := regexp.MustCompile(`^` + regexp.QuoteMeta(strings.TrimSuffix(name, ext)) + `\.[0-9a-f]{8}` + regexp.QuoteMeta(ext) + `$`)
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.Name() != fingerprinted && stale.MatchString(entry.Name()) {
+			if err := os.Remove(filepath.Join(outputDir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// inlinedAssets holds the content of assets embedded directly into pages by -inline-assets
+var inlinedAssets = map[string][]byte{}
+
+// loadInlineAsset reads an asset so pages embed it instead of linking to a separate file.
+func loadInlineAsset(name string) error {
+	content, err := readEmbeddedFile(templateFS, "templates/"+name)
+	if err != nil {
+		return err
+	}
+	inlinedAssets[name] = content
+	return nil
+}
+
+// inlineCSS returns the stylesheet to embed in a <style> element, or "" when assets are linked.
+func inlineCSS(name string) template.CSS {
+	return template.CSS(inlinedAssets[name])
+}
+
+// inlineJS returns the script to embed in a <script> element, or "" when assets are linked.
+func inlineJS(name string) template.JS {
+	return template.JS(inlinedAssets[name])
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// badgeBranch is the branch -badge-prs commits the README change to
+const badgeBranch = "unreleasedcommits-badge"
+
+// defaultBadgeThreshold colors a badge when no color_thresholds are configured for commits
+var defaultBadgeThreshold = ColorThreshold{Green: 0, Yellow: 10}
+
+// Badge colors, matching the heat-map bands
+const (
+	badgeGreen  = "#4c1"
+	badgeYellow = "#dfb317"
+	badgeRed    = "#e05d44"
+	badgeGrey   = "#9f9f9f"
+)
+
+// badgePath returns the path of a repository's badge relative to the output directory.
+func badgePath(repoName string) string {
+	return filepath.Join("badges", repoName+".svg")
+}
+
+// generateBadges writes a badge with the unreleased commit count of each repository and
+// removes the badges of repositories that are no longer part of the site.
+func generateBadges(outputDir string, repos []RepositoryData, thresholds map[string]ColorThreshold) error {
+	badgesDir := filepath.Join(outputDir, "badges")
+	if err := os.MkdirAll(badgesDir, 0755); err != nil {
+		return err
+	}
+
+	current := make(map[string]bool)
+	for _, repo := range repos {
+		message, color := badgeMessage(repo, thresholds)
+		filename := repo.Name + ".svg"
+		current[filename] = true
+		if err := writeFileAtomic(filepath.Join(badgesDir, filename), []byte(badgeSVG("unreleased", message, color))); err != nil {
+			return fmt.Errorf("failed to write badge for %s: %w", repo.Name, err)
+		}
+	}
+
+	entries, err := os.ReadDir(badgesDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".svg") && !current[entry.Name()] {
+			if err := os.Remove(filepath.Join(badgesDir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// badgeMessage returns the text and color of a repository's badge. The color follows
+// the commits threshold in thresholds when configured.
+func badgeMessage(repo RepositoryData, thresholds map[string]ColorThreshold) (string, string) {
+	if repo.NeverReleased {
+		return "never released", badgeGrey
+	}
+	count := len(repo.UnreleasedCommits)
+	if count == 0 {
+		return "none", badgeGreen
+	}
+
+	threshold, ok := thresholds["commits"]
+	if !ok {
+		threshold = defaultBadgeThreshold
+	}
+	message := fmt.Sprintf("%d commits", count)
+	if count == 1 {
+		message = "1 commit"
+	}
+	switch {
+	case count <= threshold.Green:
+		return message, badgeGreen
+	case count <= threshold.Yellow:
+		return message, badgeYellow
+	}
+	return message, badgeRed
+}
+
+// badgeSVG renders a flat badge in the style of shields.io. Text widths are estimated
+// from the character count, which is close enough for the short labels used here.
+func badgeSVG(label, message, color string) string {
+	textWidth := func(s string) int {
+		return int(math.Ceil(float64(len(s))*6.5)) + 10
+	}
+	lw, mw := textWidth(label), textWidth(message)
+	w := lw + mw
+	label, message = html.EscapeString(label), html.EscapeString(message)
+
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">
+<title>%s: %s</title>
+<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>
+<clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>
+<g clip-path="url(#r)"><rect width="%d" height="20" fill="#555"/><rect x="%d" width="%d" height="20" fill="%s"/><rect width="%d" height="20" fill="url(#s)"/></g>
+<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+<text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text><text x="%d" y="14">%s</text>
+<text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text><text x="%d" y="14">%s</text>
+</g>
+</svg>
+`, w, label, message, label, message, w, lw, lw, mw, color, w,
+		lw/2, label, lw/2, label, lw+mw/2, message, lw+mw/2, message)
+}
+
+// badgeMarkdown returns the README markdown for a repository's badge, linking to its
+// page on the site published at siteURL.
+func badgeMarkdown(repo RepositoryData, siteURL string) string {
+	site := strings.TrimSuffix(siteURL, "/") + "/"
+	page := site + repo.Name + ".html"
+	if repo.NeverReleased {
+		page = site
+	}
+	return fmt.Sprintf("[![Unreleased commits](%s%s)](%s)", site, filepath.ToSlash(badgePath(repo.Name)), page)
+}
+
+// addBadge inserts the badge into a README: on the first line when that line already
+// holds badges, otherwise as a new first line.
+func addBadge(readme, badge string) string {
+	first, rest, _ := strings.Cut(readme, "\n")
+	if strings.HasPrefix(strings.TrimSpace(first), "[![") {
+		return strings.TrimRight(first, " \r") + " " + badge + "\n" + rest
+	}
+	return badge + "\n\n" + readme
+}
+
+// runBadgePRs opens a pull request against each repository in data/ that adds its
+// badge to the README. Repositories whose README already links the badge, or that
+// already have the badge branch, are skipped.
+func runBadgePRs(siteURL string, dryRun bool) error {
+	if siteURL == "" {
+		return errors.New("-badge-prs needs site_url in the -config file to link the badges to")
+	}
+
+	ctx := context.Background()
+	client, err := newGitHubClient(ctx)
+	if err != nil {
+		return err
+	}
+	repos, err := loadAllRepositoryData("data")
+	if err != nil {
+		return err
+	}
+
+	opened := 0
+	for _, repo := range repos {
+		url, err := openBadgePR(ctx, client, repo, siteURL, dryRun)
+		switch {
+		case err != nil:
+			fmt.Printf("  ❌ %s: %v\n", repo.Name, err)
+		case url == "":
+			fmt.Printf("  ⏭️  %s: badge already added or proposed\n", repo.Name)
+		default:
+			fmt.Printf("  ✅ %s: %s\n", repo.Name, url)
+			opened++
+		}
+	}
+
+	if dryRun {
+		fmt.Printf("✅ Would open %d pull requests (-dry-run)\n", opened)
+	} else {
+		fmt.Printf("✅ Opened %d pull requests\n", opened)
+	}
+	return nil
+}
+
+// openBadgePR commits the README change to badgeBranch and opens a pull request for it,
+// returning the pull request's URL, or "" when there is nothing to do.
+func openBadgePR(ctx context.Context, client *github.Client, repo RepositoryData, siteURL string, dryRun bool) (string, error) {
+	readme, _, err := client.Repositories.GetReadme(ctx, repo.Owner, repo.Name, &github.RepositoryContentGetOptions{Ref: repo.DefaultBranch})
+	if err != nil {
+		return "", fmt.Errorf("failed to get README: %w", err)
+	}
+	content, err := readme.GetContent()
+	if err != nil {
+		return "", fmt.Errorf("failed to decode README: %w", err)
+	}
+
+	badge := badgeMarkdown(repo, siteURL)
+	site := strings.TrimSuffix(siteURL, "/") + "/"
+	if strings.Contains(content, site+filepath.ToSlash(badgePath(repo.Name))) {
+		return "", nil
+	}
+	if _, resp, err := client.Repositories.GetBranch(ctx, repo.Owner, repo.Name, badgeBranch, 0); err == nil {
+		return "", nil
+	} else if resp == nil || resp.StatusCode != 404 {
+		return "", fmt.Errorf("failed to check for branch %s: %w", badgeBranch, err)
+	}
+	if dryRun {
+		return "(dry run) would add " + badge + " to " + readme.GetPath(), nil
+	}
+
+	head, _, err := client.Repositories.GetBranch(ctx, repo.Owner, repo.Name, repo.DefaultBranch, 1)
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s: %w", repo.DefaultBranch, err)
+	}
+	ref := &github.Reference{
+		Ref:    github.String("refs/heads/" + badgeBranch),
+		Object: &github.GitObject{SHA: head.GetCommit().SHA},
+	}
+	if _, _, err := client.Git.CreateRef(ctx, repo.Owner, repo.Name, ref); err != nil {
+		return "", fmt.Errorf("failed to create branch: %w", err)
+	}
+
+	message := "Add unreleased commits badge"
+	_, _, err = client.Repositories.UpdateFile(ctx, repo.Owner, repo.Name, readme.GetPath(), &github.RepositoryContentFileOptions{
+		Message: github.String(message),
+		Content: []byte(addBadge(content, badge)),
+		SHA:     readme.SHA,
+		Branch:  github.String(badgeBranch),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to commit README: %w", err)
+	}
+
+	pr, _, err := client.PullRequests.Create(ctx, repo.Owner, repo.Name, &github.NewPullRequest{
+		Title: github.String(message),
+		Head:  github.String(badgeBranch),
+		Base:  github.String(repo.DefaultBranch),
+		Body:  github.String(fmt.Sprintf("Adds a badge showing how many commits on `%s` are waiting for a release, linking to the [unreleased commits dashboard](%s).", repo.DefaultBranch, site)),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to open pull request: %w", err)
+	}
+	return pr.GetHTMLURL(), nil
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// Baseline modes control what the default branch is compared against
+const (
+	BaselineRelease = "release"
+	BaselineTag     = "tag"
+	BaselineEither  = "either"
+
+	// BaselineBranch is used when a repository is configured to compare against a release branch
+	BaselineBranch = "branch"
+)
+
+// Baseline is the reference point that unreleased commits are measured from
+type Baseline struct {
+	Type    string
+	TagName string
+	Time    time.Time
+	Release *github.RepositoryRelease
+}
+
+// validBaselineMode reports whether mode is one of the supported -baseline values.
+func validBaselineMode(mode string) bool {
+	switch mode {
+	case BaselineRelease, BaselineTag, BaselineEither, BaselineRegistry, BaselineMarker:
+		return true
+	}
+	return false
+}
+
+// resolveBaseline determines the baseline for a repository according to mode. When
+// pattern is set only releases and tags whose name matches it are considered.
+// It returns nil when the repository has nothing to compare against.
+func resolveBaseline(ctx context.Context, client *github.Client, owner, repo, mode string, pattern *regexp.Regexp) (*Baseline, error) {
+	var fromRelease, fromTag *Baseline
+
+	if mode == BaselineRelease || mode == BaselineEither {
+		var hasRelease bool
+		var rel *github.RepositoryRelease
+		if pattern != nil {
+			var err error
+			hasRelease, rel, err = latestMatchingRelease(ctx, client, owner, repo, pattern)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			hasRelease, rel = checkLatestRelease(ctx, client, owner, repo)
+		}
+		if hasRelease {
+			fromRelease = &Baseline{
+				Type:    BaselineRelease,
+				TagName: rel.GetTagName(),
+				Time:    rel.GetPublishedAt().Time,
+				Release: rel,
+			}
+		}
+	}
+
+	if mode == BaselineTag || mode == BaselineEither {
+		b, err := latestTagBaseline(ctx, client, owner, repo, pattern)
+		if err != nil {
+			return nil, err
+		}
+		fromTag = b
+	}
+
+	switch {
+	case fromRelease == nil:
+		return fromTag, nil
+	case fromTag == nil:
+		return fromRelease, nil
+	case fromTag.TagName == fromRelease.TagName:
+		// Prefer the release when both point at the same tag since it carries more detail
+		return fromRelease, nil
+	case fromTag.Time.After(fromRelease.Time):
+		return fromTag, nil
+	}
+	return fromRelease, nil
+}
+
+// branchBaseline uses the head of a release or maintenance branch as the baseline.
+func branchBaseline(ctx context.Context, client *github.Client, owner, repo, branch string) (*Baseline, error) {
+	b, _, err := client.Repositories.GetBranch(ctx, owner, repo, branch, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get branch %s: %w", branch, err)
+	}
+
+	return &Baseline{
+		Type:    BaselineBranch,
+		TagName: b.GetName(),
+		Time:    b.GetCommit().GetCommit().GetCommitter().GetDate().Time,
+	}, nil
+}
+
+// latestMatchingRelease finds the most recently published stable release whose tag matches pattern.
+func latestMatchingRelease(ctx context.Context, client *github.Client, owner, repo string, pattern *regexp.Regexp) (bool, *github.RepositoryRelease, error) {
+	releases, err := listAllReleases(ctx, client, owner, repo)
+	if err != nil {
+		return false, nil, err
+	}
+
+	var latest *github.RepositoryRelease
+	for _, rel := range releases {
+		if rel.GetDraft() || rel.GetPrerelease() || !pattern.MatchString(rel.GetTagName()) {
+			continue
+		}
+		if latest == nil || rel.GetPublishedAt().After(latest.GetPublishedAt().Time) {
+			latest = rel
+		}
+	}
+	return latest != nil, latest, nil
+}
+
+// latestTagBaseline finds the newest tag in the repository, considering only tags
+// matching pattern when it is set. Tags are ordered by semantic version, preferring
+// stable versions; if no tag parses as a version the first tag returned by the API is used.
+func latestTagBaseline(ctx context.Context, client *github.Client, owner, repo string, pattern *regexp.Regexp) (*Baseline, error) {
+	tags, err := listAllTags(ctx, client, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	if pattern != nil {
+		tags = filterTags(tags, pattern)
+	}
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	latest := newestTag(tags)
+
+	commit, _, err := client.Repositories.GetCommit(ctx, owner, repo, latest.GetCommit().GetSHA(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit for tag %s: %w", latest.GetName(), err)
+	}
+
+	return &Baseline{
+		Type:    BaselineTag,
+		TagName: latest.GetName(),
+		Time:    commit.GetCommit().GetCommitter().GetDate().Time,
+	}, nil
+}
+
+// filterTags returns the tags whose name matches pattern.
+func filterTags(tags []*github.RepositoryTag, pattern *regexp.Regexp) []*github.RepositoryTag {
+	var matching []*github.RepositoryTag
+	for _, tag := range tags {
+		if pattern.MatchString(tag.GetName()) {
+			matching = append(matching, tag)
+		}
+	}
+	return matching
+}
+
+// newestTag picks the highest semantic version tag, preferring stable releases over prereleases.
+func newestTag(tags []*github.RepositoryTag) *github.RepositoryTag {
+	var best *github.RepositoryTag
+	var bestVersion SemVersion
+	for _, tag := range tags {
+		v, ok := parseSemver(tag.GetName())
+		if !ok {
+			continue
+		}
+		if best == nil || betterTagVersion(v, bestVersion) {
+			best = tag
+			bestVersion = v
+		}
+	}
+	if best == nil {
+		return tags[0]
+	}
+	return best
+}
+
+// betterTagVersion reports whether candidate should replace current as the newest tag.
+func betterTagVersion(candidate, current SemVersion) bool {
+	if (candidate.Prerelease == "") != (current.Prerelease == "") {
+		return candidate.Prerelease == ""
+	}
+	return candidate.Compare(current) > 0
+}
+
+func listAllTags(ctx context.Context, client *github.Client, owner, repo string) ([]*github.RepositoryTag, error) {
+	var all []*github.RepositoryTag
+	opt := &github.ListOptions{PerPage: 100}
+
+	for {
+		tags, resp, err := client.Repositories.ListTags(ctx, owner, repo, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, tags...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return all, nil
+}
+
+// baselineDescription describes what a repository is missing when no baseline is found.
+func baselineDescription(mode string) string {
+	switch mode {
+	case BaselineTag:
+		return "tags"
+	case BaselineEither:
+		return "releases or tags"
+	}
+	return "releases"
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// bigQueryAPIURL is the BigQuery REST API endpoint
+const bigQueryAPIURL = "https://bigquery.googleapis.com"
+
+// bigQueryBatchSize is the number of rows sent in each insertAll request, well below
+// the API's limit of 50,000 rows and 10 MB per request
+const bigQueryBatchSize = 500
+
+// BigQueryConfig names the tables that -export bigquery streams into. Rows are appended,
+// so every export adds a snapshot keyed by crawled_at.
+type BigQueryConfig struct {
+	Project      string `json:"project"`
+	Dataset      string `json:"dataset"`
+	ReposTable   string `json:"repos_table,omitempty"`
+	CommitsTable string `json:"commits_table,omitempty"`
+	TokenEnv     string `json:"token_env"`
+	APIURL       string `json:"api_url,omitempty"`
+}
+
+// bigQueryRow is a row of an insertAll request. The insert ID lets BigQuery drop a row
+// sent twice, e.g. when an export is retried.
+type bigQueryRow struct {
+	InsertID string         `json:"insertId"`
+	JSON     map[string]any `json:"json"`
+}
+
+// bigQueryInsertResponse is the part of an insertAll response reporting rejected rows
+type bigQueryInsertResponse struct {
+	InsertErrors []struct {
+		Index  int `json:"index"`
+		Errors []struct {
+			Reason   string `json:"reason"`
+			Location string `json:"location"`
+			Message  string `json:"message"`
+		} `json:"errors"`
+	} `json:"insertErrors"`
+}
+
+// validateBigQuery checks that the BigQuery settings name a dataset and the access
+// token's environment variable.
+func validateBigQuery(b *BigQueryConfig) error {
+	if b == nil {
+		return nil
+	}
+	if b.Project == "" || b.Dataset == "" {
+		return fmt.Errorf("project and dataset are required")
+	}
+	if b.TokenEnv == "" {
+		return fmt.Errorf("token_env is required")
+	}
+	return nil
+}
+
+// exportBigQuery appends a row per repository and a row per unreleased commit in data/
+// to the configured tables, all stamped with the time of the crawl.
+func exportBigQuery(ctx context.Context, b *BigQueryConfig) error {
+	if b == nil {
+		return fmt.Errorf("-export bigquery needs a bigquery section in -config")
+	}
+	token := strings.TrimSpace(os.Getenv(b.TokenEnv))
+	if token == "" {
+		return fmt.Errorf("BigQuery token environment variable %s is not set", b.TokenEnv)
+	}
+
+	repos, err := loadAllRepositoryData("data")
+	if err != nil {
+		return err
+	}
+	crawl, err := loadLastCrawlTimestamp(filepath.Join("data", "timestamp.json"))
+	if err != nil {
+		return fmt.Errorf("failed to read crawl timestamp: %w", err)
+	}
+	crawledAt := crawl.LastCrawled
+
+	var repoRows, commitRows []bigQueryRow
+	for _, repo := range repos {
+		repoRows = append(repoRows, bigQueryRow{
+			InsertID: fmt.Sprintf("%s/%s@%d", repo.Owner, repo.Name, crawledAt.Unix()),
+			JSON:     bigQueryRepoRow(repo, crawledAt),
+		})
+	}
+	for _, row := range exportRows(repos) {
+		values := map[string]any{"crawled_at": bigQueryTimestamp(crawledAt)}
+		for i, col := range exportColumns {
+			values[col.Name] = row[i]
+			if t, ok := row[i].(time.Time); ok {
+				values[col.Name] = bigQueryTimestamp(t)
+			}
+		}
+		commitRows = append(commitRows, bigQueryRow{
+			InsertID: fmt.Sprintf("%s/%s/%s@%d", values["owner"], values["repo"], values["sha"], crawledAt.Unix()),
+			JSON:     values,
+		})
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	tables := []struct {
+		name string
+		rows []bigQueryRow
+	}{
+		{cmp.Or(b.ReposTable, "repositories"), repoRows},
+		{cmp.Or(b.CommitsTable, "unreleased_commits"), commitRows},
+	}
+	for _, table := range tables {
+		for start := 0; start < len(table.rows); start += bigQueryBatchSize {
+			end := min(start+bigQueryBatchSize, len(table.rows))
+			if err := insertBigQueryRows(ctx, client, b, token, table.name, table.rows[start:end]); err != nil {
+				return fmt.Errorf("%s.%s: %w", b.Dataset, table.name, err)
+			}
+		}
+		fmt.Printf("Streamed %d rows into %s.%s.%s\n", len(table.rows), b.Project, b.Dataset, table.name)
+	}
+	return nil
+}
+
+// bigQueryRepoRow returns the repositories table row of a repository.
+func bigQueryRepoRow(repo RepositoryData, crawledAt time.Time) map[string]any {
+	s := apiRepoSummary(repo)
+	row := map[string]any{
+		"crawled_at":         bigQueryTimestamp(crawledAt),
+		"owner":              repo.Owner,
+		"repo":               s.Name,
+		"default_branch":     s.DefaultBranch,
+		"never_released":     s.NeverReleased,
+		"baseline_type":      s.BaselineType,
+		"baseline_tag":       s.BaselineTag,
+		"unreleased_commits": s.UnreleasedCommits,
+		"dependency_updates": s.DependencyUpdates,
+		"breaking_changes":   s.BreakingChanges,
+		"security_fixes":     s.SecurityFixes,
+		"security_fix_days":  s.SecurityFixDays,
+		"open_alerts":        s.OpenAlerts,
+		"open_pull_requests": s.OpenPullRequests,
+		"days_behind":        s.DaysBehind,
+		"days_since_release": s.DaysSinceRelease,
+		"archived":           s.Archived,
+		"language":           s.Language,
+		"stars":              s.Stars,
+		"forks":              s.Forks,
+		"impact":             s.Impact,
+	}
+	if !s.BaselineTime.IsZero() {
+		row["baseline_time"] = bigQueryTimestamp(s.BaselineTime)
+	}
+	return row
+}
+
+// bigQueryTimestamp formats t as a BigQuery TIMESTAMP value.
+func bigQueryTimestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// insertBigQueryRows streams rows into a table with the tabledata.insertAll API, failing
+// if any row is rejected.
+func insertBigQueryRows(ctx context.Context, client *http.Client, b *BigQueryConfig, token, table string, rows []bigQueryRow) error {
+	body, err := json.Marshal(map[string]any{"rows": rows})
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("%s/bigquery/v2/projects/%s/datasets/%s/tables/%s/insertAll",
+		strings.TrimSuffix(cmp.Or(b.APIURL, bigQueryAPIURL), "/"),
+		url.PathEscape(b.Project), url.PathEscape(b.Dataset), url.PathEscape(table))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "unreleasedcommits/"+version)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("%s: %s", resp.Status, apiErr.Error.Message)
+		}
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var result bigQueryInsertResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(result.InsertErrors) > 0 {
+		first := result.InsertErrors[0]
+		reason := "rejected"
+		if len(first.Errors) > 0 {
+			e := first.Errors[0]
+			reason = strings.TrimSpace(e.Location + " " + e.Message)
+		}
+		// The index comes from the response, so it is checked before naming the row
+		if first.Index < 0 || first.Index >= len(rows) {
+			return fmt.Errorf("%d of %d rows rejected, e.g. row %d: %s", len(result.InsertErrors), len(rows), first.Index, reason)
+		}
+		row := rows[first.Index].JSON
+		return fmt.Errorf("%d of %d rows rejected, e.g. %s/%s: %s", len(result.InsertErrors), len(rows), row["owner"], row["repo"], reason)
+	}
+	return nil
+}
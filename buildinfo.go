@@ -0,0 +1,89 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// version is the release of the tool, set when building with
+// -ldflags "-X main.version=v1.2.3"
+var version = "dev"
+
+// BuildInfo describes the build of the tool and the settings that generated the site,
+// written to build.json and shown in the footer
+type BuildInfo struct {
+	Version              string    `json:"version"`
+	Commit               string    `json:"commit,omitempty"`
+	GoVersion            string    `json:"go_version"`
+	ConfigHash           string    `json:"config_hash"`
+	CrawledAt            time.Time `json:"crawled_at,omitzero"`
+	CrawlDurationSeconds float64   `json:"crawl_duration_seconds,omitempty"`
+}
+
+// siteBuild is the build information of the site being generated
+var siteBuild BuildInfo
+
+// currentBuild returns the build information for templates.
+func currentBuild() BuildInfo {
+	return siteBuild
+}
+
+// newBuildInfo describes this binary, with the hash of its settings and when and how
+// long the crawl ran.
+func newBuildInfo(configHash string, crawl TimestampData) BuildInfo {
+	b := BuildInfo{
+		Version:              version,
+		GoVersion:            runtime.Version(),
+		ConfigHash:           configHash,
+		CrawledAt:            crawl.LastCrawled,
+		CrawlDurationSeconds: crawl.DurationSeconds,
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		modified := false
+		for _, s := range info.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				b.Commit = s.Value
+			case "vcs.modified":
+				modified = s.Value == "true"
+			}
+		}
+		if len(b.Commit) > 12 {
+			b.Commit = b.Commit[:12]
+		}
+		if b.Commit != "" && modified {
+			b.Commit += "-dirty"
+		}
+	}
+	return b
+}
+
+// CrawlDuration returns how long the crawl took, rounded to the second.
+func (b BuildInfo) CrawlDuration() time.Duration {
+	return (time.Duration(b.CrawlDurationSeconds * float64(time.Second))).Round(time.Second)
+}
+
+// hashConfig returns a short hash of the effective configuration, so two sites can be
+// compared without publishing the configuration itself.
+func hashConfig(cfg *Config) string {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6])
+}
+
+// generateBuildJSON writes build.json with the build information and when the site was
+// generated.
+func generateBuildJSON(outputDir string, build BuildInfo) error {
+	return writeJSON(filepath.Join(outputDir, "build.json"), struct {
+		BuildInfo
+		GeneratedAt time.Time `json:"generated_at"`
+	}{build, time.Now().UTC()})
+}
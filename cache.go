@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+)
+
+// generateCacheFile is the manifest of page input hashes kept in the output directory
+const generateCacheFile = ".generate-cache.json"
+
+// generateCache records a hash of the inputs used for each repository's pages, so
+// -generate can skip pages whose data, options, templates and build are unchanged
+type generateCache struct {
+	path     string
+	base     string
+	previous map[string]string
+	current  map[string]string
+}
+
+// loadGenerateCache reads the manifest from outputDir. When force is set the previous
+// hashes are ignored so every page is rendered again.
+func loadGenerateCache(outputDir string, opts GenerateOptions, lastUpdated string, force bool) *generateCache {
+	c := &generateCache{
+		path:     filepath.Join(outputDir, generateCacheFile),
+		previous: make(map[string]string),
+		current:  make(map[string]string),
+	}
+
+	// -force only controls whether the previous hashes are used, not what the pages contain
+	opts.Force = false
+	// The config is covered by ConfigHash; its pointer differs on every run
+	templatesDir := opts.TemplatesDir
+	opts.Config = nil
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%d|%+v|%s|", currentSchemaVersion, opts, lastUpdated)
+	hashTemplates(h, templatesDir)
+	hashBuild(h)
+	c.base = hex.EncodeToString(h.Sum(nil))
+
+	if !force {
+		if data, err := os.ReadFile(c.path); err == nil {
+			_ = json.Unmarshal(data, &c.previous)
+		}
+	}
+	return c
+}
+
+// inputHash hashes a page's inputs together with the settings shared by every page.
+func (c *generateCache) inputHash(inputs any) string {
+	data, err := json.Marshal(inputs)
+	if err != nil {
+		return ""
+	}
+	h := sha256.New()
+	h.Write([]byte(c.base))
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// unchanged reports whether key was generated from the same inputs last time and all of
+// its output files still exist. An unchanged entry is carried over to the new manifest.
+func (c *generateCache) unchanged(key, hash string, files ...string) bool {
+	if hash == "" || c.previous[key] != hash {
+		return false
+	}
+	for _, file := range files {
+		if _, err := os.Stat(file); err != nil {
+			return false
+		}
+	}
+	c.current[key] = hash
+	return true
+}
+
+// update records the hash of inputs key was just generated from.
+func (c *generateCache) update(key, hash string) {
+	if hash != "" {
+		c.current[key] = hash
+	}
+}
+
+// save writes the manifest, dropping entries for repositories that no longer exist.
+func (c *generateCache) save() error {
+	data, err := json.MarshalIndent(c.current, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileIfChanged(c.path, append(data, '\n'))
+}
+
+// hashTemplates adds the templates and assets in use to h, from TEMPLATE_PATH when set,
+// followed by the -templates overrides in overrideDir.
+func hashTemplates(h io.Writer, overrideDir string) {
+	if path := os.Getenv("TEMPLATE_PATH"); path != "" {
+		hashDir(h, os.DirFS(path), ".")
+	} else {
+		hashDir(h, templateFS, "templates")
+	}
+	if overrideDir != "" {
+		hashDir(h, os.DirFS(overrideDir), ".")
+	}
+}
+
+// hashDir adds the name and content of every file in dir to h, in name order.
+func hashDir(h io.Writer, fsys fs.FS, dir string) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		content, err := fs.ReadFile(fsys, filepath.ToSlash(filepath.Join(dir, name)))
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(h, "%s|%d|", name, len(content))
+		h.Write(content)
+	}
+}
+
+// hashBuild adds the version and build information of the running binary to h so
+// upgrading invalidates every page. Builds from a modified or unknown source tree
+// share their build information, so the executable's size and modification time are
+// added for those too.
+func hashBuild(h io.Writer) {
+	fmt.Fprintf(h, "%s|%s|", version, runtime.Version())
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		hashExecutableStat(h)
+		return
+	}
+	io.WriteString(h, info.String())
+
+	revision, modified := "", false
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			revision = s.Value
+		case "vcs.modified":
+			modified = s.Value == "true"
+		}
+	}
+	if revision == "" || modified {
+		hashExecutableStat(h)
+	}
+}
+
+// hashExecutableStat adds the size and modification time of the running binary to h.
+func hashExecutableStat(h io.Writer) {
+	path, err := os.Executable()
+	if err != nil {
+		return
+	}
+	if info, err := os.Stat(path); err == nil {
+		fmt.Fprintf(h, "%d|%d|", info.Size(), info.ModTime().UnixNano())
+	}
+}
+
+// writeFileIfChanged writes content to filename unless the file already holds exactly
+// that content, so unchanged pages keep their timestamps and produce no diff.
+func writeFileIfChanged(filename string, content []byte) error {
+	if existing, err := os.ReadFile(filename); err == nil && bytes.Equal(existing, content) {
+		return nil
+	}
+	return writeFileAtomic(filename, content)
+}
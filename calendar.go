@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// staleReleaseDays is how long after a release its repository is considered stale when it
+// still has unreleased commits
+const staleReleaseDays = 90
+
+// calendarEvent is an all-day event of the release calendar
+type calendarEvent struct {
+	UID         string
+	Date        time.Time
+	Summary     string
+	Description string
+	URL         string
+}
+
+// generateCalendar writes releases.ics, an iCalendar feed with an event for every
+// release and, for each repository with unreleased commits, the day its latest release
+// turns staleReleaseDays old.
+func generateCalendar(outputDir string, repos []RepositoryData, lastCrawled time.Time) error {
+	var events []calendarEvent
+	for _, repo := range repos {
+		if repo.NeverReleased {
+			continue
+		}
+
+		for _, rel := range knownReleases(repo) {
+			description := ""
+			if rel.PreviousTag != "" {
+				description = fmt.Sprintf("%d commits since %s", rel.CommitCount, rel.PreviousTag)
+			}
+			events = append(events, calendarEvent{
+				UID:         fmt.Sprintf("%s-%s-release-%s", repo.Owner, repo.Name, rel.TagName),
+				Date:        rel.PublishedAt,
+				Summary:     fmt.Sprintf("%s %s released", repo.Name, rel.TagName),
+				Description: description,
+				URL:         rel.URL,
+			})
+		}
+
+		if len(repo.UnreleasedCommits) > 0 && repo.BaselineType != BaselineBranch && !repo.LatestReleaseTime.IsZero() {
+			events = append(events, calendarEvent{
+				UID:         fmt.Sprintf("%s-%s-stale", repo.Owner, repo.Name),
+				Date:        repo.LatestReleaseTime.AddDate(0, 0, staleReleaseDays),
+				Summary:     fmt.Sprintf("%s: %d days without a release", repo.Name, staleReleaseDays),
+				Description: fmt.Sprintf("%d unreleased commits since %s", len(repo.UnreleasedCommits), repo.LatestReleaseTag),
+				URL:         repo.RepositoryURL + "/compare/" + repo.LatestReleaseTag + "..." + repo.DefaultBranch,
+			})
+		}
+	}
+
+	owner := ""
+	if len(repos) > 0 {
+		owner = repos[0].Owner
+	}
+	stamp := lastCrawled
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+
+	var b strings.Builder
+	writeCalendarLine(&b, "BEGIN:VCALENDAR")
+	writeCalendarLine(&b, "VERSION:2.0")
+	writeCalendarLine(&b, "PRODID:-//UnitVectorY Labs//unreleasedcommits//EN")
+	writeCalendarLine(&b, "CALSCALE:GREGORIAN")
+	writeCalendarLine(&b, "X-WR-CALNAME:"+escapeCalendarText("Releases - "+owner))
+	for _, e := range events {
+		writeCalendarLine(&b, "BEGIN:VEVENT")
+		writeCalendarLine(&b, "UID:"+escapeCalendarText(e.UID)+"@unreleasedcommits")
+		writeCalendarLine(&b, "DTSTAMP:"+stamp.UTC().Format("20060102T150405Z"))
+		writeCalendarLine(&b, "DTSTART;VALUE=DATE:"+e.Date.UTC().Format("20060102"))
+		writeCalendarLine(&b, "DTEND;VALUE=DATE:"+e.Date.UTC().AddDate(0, 0, 1).Format("20060102"))
+		writeCalendarLine(&b, "SUMMARY:"+escapeCalendarText(e.Summary))
+		if e.Description != "" {
+			writeCalendarLine(&b, "DESCRIPTION:"+escapeCalendarText(e.Description))
+		}
+		writeCalendarLine(&b, "URL:"+e.URL)
+		writeCalendarLine(&b, "END:VEVENT")
+	}
+	writeCalendarLine(&b, "END:VCALENDAR")
+
+	return writeFileAtomic(filepath.Join(outputDir, "releases.ics"), []byte(b.String()))
+}
+
+// writeCalendarLine writes an iCalendar content line ending in CRLF, folded so no line
+// is longer than 75 bytes. Continuation lines start with a space, which counts towards
+// the limit, so they carry at most 74 bytes of the line.
+func writeCalendarLine(b *strings.Builder, line string) {
+	limit := 75
+	for len(line) > limit {
+		// Fold on a rune boundary so multi-byte characters are not split
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = 74
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+// escapeCalendarText escapes the characters that are special in iCalendar text values.
+func escapeCalendarText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`, "\r", "").Replace(s)
+}
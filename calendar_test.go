@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestWriteCalendarLine(t *testing.T) {
+	tests := []string{
+		"SUMMARY:short",
+		"SUMMARY:" + strings.Repeat("a", 67),
+		"SUMMARY:" + strings.Repeat("a", 68),
+		"DESCRIPTION:" + strings.Repeat("abcdefghij", 30),
+		"DESCRIPTION:" + strings.Repeat("ü", 100),
+		"DESCRIPTION:" + strings.Repeat("日本語", 40),
+		"DESCRIPTION:x" + strings.Repeat("🚀", 50),
+	}
+	for _, line := range tests {
+		var b strings.Builder
+		writeCalendarLine(&b, line)
+		out := b.String()
+
+		if !strings.HasSuffix(out, "\r\n") {
+			t.Errorf("%q: output does not end in CRLF", line)
+			continue
+		}
+		physical := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+		var unfolded strings.Builder
+		for i, p := range physical {
+			if len(p) > 75 {
+				t.Errorf("%q: line %d is %d bytes, longer than 75", line, i, len(p))
+			}
+			if i > 0 {
+				if !strings.HasPrefix(p, " ") {
+					t.Errorf("%q: continuation line %d does not start with a space", line, i)
+				}
+				p = p[1:]
+			}
+			if !utf8.ValidString(p) {
+				t.Errorf("%q: line %d splits a multi-byte character", line, i)
+			}
+			unfolded.WriteString(p)
+		}
+		if unfolded.String() != line {
+			t.Errorf("unfolding %q gave %q", line, unfolded.String())
+		}
+		if len(line) <= 75 && len(physical) != 1 {
+			t.Errorf("%q: folded a line that fits", line)
+		}
+	}
+
+	// An ASCII line fills every physical line: 75 bytes, then a space and 74 bytes
+	var b strings.Builder
+	writeCalendarLine(&b, strings.Repeat("a", 75+74+10))
+	physical := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+	if len(physical) != 3 || len(physical[0]) != 75 || len(physical[1]) != 75 || len(physical[2]) != 11 {
+		t.Errorf("folded ASCII line lengths are wrong: %q", physical)
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// changesFile records how the most recent crawl changed the data, for changes.html
+const changesFile = "changes.json"
+
+// CrawlChanges is how the most recent crawl changed each repository compared to the
+// crawl before it
+type CrawlChanges struct {
+	CrawledAt         time.Time   `json:"crawled_at"`
+	PreviousCrawledAt time.Time   `json:"previous_crawled_at,omitzero"`
+	Repos             []RepoDelta `json:"repos"`
+	Removed           []RepoDelta `json:"removed,omitempty"`
+}
+
+// crawlChanges builds the changes from the crawl's notification and the snapshot taken
+// before it, adding the repositories whose data files are gone.
+func crawlChanges(n CrawlNotification, before map[string]repoSnapshot, previousCrawl time.Time) CrawlChanges {
+	changes := CrawlChanges{CrawledAt: n.CrawledAt, PreviousCrawledAt: previousCrawl, Repos: n.Repos}
+	for name, prev := range before {
+		if slices.ContainsFunc(n.Repos, func(d RepoDelta) bool { return d.Name == name }) {
+			continue
+		}
+		changes.Removed = append(changes.Removed, RepoDelta{
+			Name:                      name,
+			RepositoryURL:             prev.RepositoryURL,
+			PreviousRelease:           prev.LatestRelease,
+			PreviousUnreleasedCommits: prev.UnreleasedCommits,
+			Delta:                     -prev.UnreleasedCommits,
+		})
+	}
+	slices.SortFunc(changes.Removed, func(a, b RepoDelta) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return changes
+}
+
+// markNewCommits flags the commits that were not among the previous crawl's unreleased
+// commits and returns how many there are.
+func markNewCommits(commits, previous []CommitInfo) int {
+	seen := make(map[string]bool, len(previous))
+	for _, c := range previous {
+		seen[c.SHA] = true
+	}
+	count := 0
+	for i := range commits {
+		commits[i].New = !seen[commits[i].SHA]
+		if commits[i].New {
+			count++
+		}
+	}
+	return count
+}
+
+// countNewCommits returns how many commits are flagged as new since the previous crawl.
+func countNewCommits(commits []CommitInfo) int {
+	count := 0
+	for _, c := range commits {
+		if c.New {
+			count++
+		}
+	}
+	return count
+}
+
+// loadCrawlChanges reads the changes recorded by the most recent crawl. It returns nil
+// when the data directory has none.
+func loadCrawlChanges(dir string) (*CrawlChanges, error) {
+	data, err := os.ReadFile(filepath.Join(dir, changesFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var changes CrawlChanges
+	if err := json.Unmarshal(data, &changes); err != nil {
+		return nil, fmt.Errorf("%s: %w", changesFile, err)
+	}
+	return &changes, nil
+}
+
+// generateChangesPage renders changes.html, grouping the repositories by how they changed.
+// Repositories whose release and unreleased commits are unchanged are left out.
+func generateChangesPage(outputDir string, changes *CrawlChanges, owner, lastUpdated string, opts GenerateOptions) error {
+	tmpl, err := loadTemplates(opts)
+	if err != nil {
+		return fmt.Errorf("failed to parse changes template: %w", err)
+	}
+
+	data := struct {
+		Owner             string
+		CrawledAt         time.Time
+		PreviousCrawledAt time.Time
+		Released          []RepoDelta
+		Grew              []RepoDelta
+		Shrank            []RepoDelta
+		Added             []RepoDelta
+		Removed           []RepoDelta
+		Unchanged         int
+		TotalCommits      int
+		PreviousCommits   int
+		LastUpdated       string
+	}{
+		Owner:             owner,
+		CrawledAt:         changes.CrawledAt,
+		PreviousCrawledAt: changes.PreviousCrawledAt,
+		Removed:           changes.Removed,
+		LastUpdated:       lastUpdated,
+	}
+
+	for _, d := range changes.Repos {
+		data.TotalCommits += d.UnreleasedCommits
+		data.PreviousCommits += d.PreviousUnreleasedCommits
+		switch {
+		case d.New:
+			data.Added = append(data.Added, d)
+		case d.Released:
+			data.Released = append(data.Released, d)
+		case d.Delta > 0:
+			data.Grew = append(data.Grew, d)
+		case d.Delta < 0:
+			data.Shrank = append(data.Shrank, d)
+		default:
+			data.Unchanged++
+		}
+	}
+	for _, d := range changes.Removed {
+		data.PreviousCommits += d.PreviousUnreleasedCommits
+	}
+	// Repos are sorted by delta, largest growth first; list shrinking backlogs by the
+	// largest drop first
+	slices.Reverse(data.Shrank)
+
+	return executePage(tmpl, "changes.html", filepath.Join(outputDir, "changes.html"), data)
+}
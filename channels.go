@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// ChannelConfig is a release channel, such as stable, beta or nightly, whose releases
+// are the tags matching TagPattern
+type ChannelConfig struct {
+	Name       string `json:"name"`
+	TagPattern string `json:"tag_pattern"`
+}
+
+// ChannelInfo is the newest tag of a release channel and how far the default branch
+// has moved past it
+type ChannelInfo struct {
+	Name              string    `json:"name"`
+	Tag               string    `json:"tag,omitempty"`
+	Time              time.Time `json:"time,omitzero"`
+	UnreleasedCommits int       `json:"unreleased_commits"`
+}
+
+// fetchChannels finds the newest tag of each channel and counts the commits on branch
+// since that tag. A channel without a matching tag is recorded without a tag.
+func fetchChannels(ctx context.Context, client *github.Client, owner, repo, branch string, channels []ChannelConfig) ([]ChannelInfo, error) {
+	tags, err := listAllTags(ctx, client, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]ChannelInfo, 0, len(channels))
+	for _, channel := range channels {
+		info := ChannelInfo{Name: channel.Name}
+		// Patterns are checked when the config is loaded
+		matching := filterTags(tags, regexp.MustCompile(channel.TagPattern))
+		if len(matching) > 0 {
+			latest := newestTag(matching)
+			commit, _, err := client.Repositories.GetCommit(ctx, owner, repo, latest.GetCommit().GetSHA(), nil)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get commit for tag %s: %w", latest.GetName(), err)
+			}
+			commits, err := compareAllCommits(ctx, client, owner, repo, latest.GetName(), branch)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compare %s: %w", latest.GetName(), err)
+			}
+			info.Tag = latest.GetName()
+			info.Time = commit.GetCommit().GetCommitter().GetDate().Time
+			info.UnreleasedCommits = len(commits)
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+)
+
+// Calendar heatmap layout, modeled on the GitHub contribution graph
+const (
+	calendarCell    = 11
+	calendarGap     = 3
+	calendarLeft    = 28
+	calendarTop     = 16
+	calendarMaxDays = 371
+)
+
+// calendarColors are the fill colors for zero commits through the busiest days
+var calendarColors = []string{"#ebedf0", "#9be9a8", "#40c463", "#30a14e", "#216e39"}
+
+// commitCalendarSVG renders a calendar heatmap of commit dates as inline SVG. The
+// calendar spans from the week of the oldest commit to the week of the newest commit,
+// limited to roughly the most recent year.
+func commitCalendarSVG(commits []CommitInfo) template.HTML {
+	if len(commits) == 0 {
+		return ""
+	}
+
+	counts := make(map[string]int)
+	var first, last time.Time
+	for _, c := range commits {
+		day := truncateToDay(c.Timestamp)
+		counts[day.Format("2006-01-02")]++
+		if first.IsZero() || day.Before(first) {
+			first = day
+		}
+		if day.After(last) {
+			last = day
+		}
+	}
+
+	if last.Sub(first) > calendarMaxDays*24*time.Hour {
+		first = last.AddDate(0, 0, -calendarMaxDays)
+	}
+
+	// Align the calendar to whole weeks starting on Sunday
+	start := first.AddDate(0, 0, -int(first.Weekday()))
+	end := last.AddDate(0, 0, 6-int(last.Weekday()))
+
+	// Colors are scaled against the busiest day shown, not older days cut off above
+	maxCount := 0
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		maxCount = max(maxCount, counts[day.Format("2006-01-02")])
+	}
+
+	weeks := int(end.Sub(start).Hours()/24)/7 + 1
+	width := calendarLeft + weeks*(calendarCell+calendarGap)
+	height := calendarTop + 7*(calendarCell+calendarGap)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg class="commit-calendar" width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-label="Unreleased commits by day">`, width, height, width, height)
+
+	for i, label := range []string{"Mon", "Wed", "Fri"} {
+		y := calendarTop + (2*i+1)*(calendarCell+calendarGap) + calendarCell - 2
+		fmt.Fprintf(&b, `<text x="0" y="%d" class="calendar-label">%s</text>`, y, label)
+	}
+
+	lastMonth := -1
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		week := int(day.Sub(start).Hours()/24) / 7
+		x := calendarLeft + week*(calendarCell+calendarGap)
+		y := calendarTop + int(day.Weekday())*(calendarCell+calendarGap)
+
+		if day.Weekday() == time.Sunday && int(day.Month()) != lastMonth {
+			lastMonth = int(day.Month())
+			fmt.Fprintf(&b, `<text x="%d" y="%d" class="calendar-label">%s</text>`, x, calendarTop-4, day.Format("Jan"))
+		}
+
+		n := counts[day.Format("2006-01-02")]
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s"><title>%s on %s</title></rect>`,
+			x, y, calendarCell, calendarCell, calendarColors[calendarLevel(n, maxCount)], pluralize(n, "commit"), day.Format("Jan 2, 2006"))
+	}
+
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
+
+// calendarLevel maps a commit count to a color index, scaled against the busiest day.
+func calendarLevel(n, maxCount int) int {
+	if n == 0 || maxCount == 0 {
+		return 0
+	}
+	level := (n*(len(calendarColors)-1) + maxCount - 1) / maxCount
+	if level < 1 {
+		level = 1
+	}
+	return level
+}
+
+// truncateToDay returns midnight UTC of the given time's date.
+func truncateToDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// pluralize formats a count with a singular or plural noun, e.g. "1 commit" or "3 commits".
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// Weekly volume bar chart layout
+const (
+	weeklyBarWidth   = 12
+	weeklyBarGap     = 3
+	weeklyChartLeft  = 24
+	weeklyPlotHeight = 80
+	weeklyChartTop   = 8
+	weeklyAxisHeight = 18
+)
+
+// weeklyCommitsSVG renders a bar chart of commits per ISO week as inline SVG, covering
+// every week from the oldest to the newest commit so quiet weeks show as gaps.
+func weeklyCommitsSVG(commits []CommitInfo) template.HTML {
+	if len(commits) == 0 {
+		return ""
+	}
+
+	counts := make(map[time.Time]int)
+	var first, last time.Time
+	for _, c := range commits {
+		week := isoWeekStart(c.Timestamp)
+		counts[week]++
+		if first.IsZero() || week.Before(first) {
+			first = week
+		}
+		if week.After(last) {
+			last = week
+		}
+	}
+
+	maxCount := 0
+	for _, n := range counts {
+		if n > maxCount {
+			maxCount = n
+		}
+	}
+
+	weeks := int(last.Sub(first).Hours()/24)/7 + 1
+	width := weeklyChartLeft + weeks*(weeklyBarWidth+weeklyBarGap)
+	height := weeklyChartTop + weeklyPlotHeight + weeklyAxisHeight
+	baseline := weeklyChartTop + weeklyPlotHeight
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg class="weekly-chart" width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-label="Unreleased commits per week">`, width, height, width, height)
+	fmt.Fprintf(&b, `<text x="0" y="%d" class="calendar-label">%d</text>`, weeklyChartTop+8, maxCount)
+	fmt.Fprintf(&b, `<text x="0" y="%d" class="calendar-label">0</text>`, baseline)
+	fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%d" class="chart-axis"/>`, weeklyChartLeft-2, baseline, width, baseline)
+
+	for i := 0; i < weeks; i++ {
+		week := first.AddDate(0, 0, 7*i)
+		n := counts[week]
+		x := weeklyChartLeft + i*(weeklyBarWidth+weeklyBarGap)
+		barHeight := n * weeklyPlotHeight / maxCount
+		year, num := week.ISOWeek()
+
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" class="chart-bar"><title>%d-W%02d (week of %s): %s</title></rect>`,
+			x, baseline-barHeight, weeklyBarWidth, barHeight, year, num, week.Format("Jan 2"), pluralize(n, "commit"))
+
+		if i == 0 || i == weeks-1 || (weeks > 8 && i%8 == 0 && weeks-1-i >= 4) {
+			fmt.Fprintf(&b, `<text x="%d" y="%d" class="calendar-label">%s</text>`, x, baseline+weeklyAxisHeight-4, week.Format("Jan 2"))
+		}
+	}
+
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
+
+// isoWeekStart returns midnight UTC on the Monday starting the ISO week containing t.
+func isoWeekStart(t time.Time) time.Time {
+	day := truncateToDay(t)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// Composition bar layout
+const (
+	compositionWidth     = 560
+	compositionBarHeight = 18
+	compositionSwatch    = 10
+	compositionCharWidth = 6
+)
+
+// compositionSVG renders the breakdown of commits by category as a stacked bar with a
+// legend, as inline SVG. Each segment is colored by its category's
+// composition-<category> class.
+func compositionSVG(counts []LabelCount) template.HTML {
+	total := 0
+	for _, c := range counts {
+		total += c.Count
+	}
+	if total == 0 {
+		return ""
+	}
+
+	legendY := compositionBarHeight + 10
+	height := legendY + compositionSwatch + 4
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg class="composition-chart" width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-label="Unreleased commits by type">`, compositionWidth, height, compositionWidth, height)
+
+	x := 0.0
+	for _, c := range counts {
+		width := float64(c.Count) * compositionWidth / float64(total)
+		fmt.Fprintf(&b, `<rect x="%.1f" y="0" width="%.1f" height="%d" class="composition-%s"><title>%s: %s (%.0f%%)</title></rect>`,
+			x, width, compositionBarHeight, c.Name, c.Name, pluralize(c.Count, "commit"), float64(c.Count)*100/float64(total))
+		x += width
+	}
+
+	legendX := 0
+	for _, c := range counts {
+		label := fmt.Sprintf("%s %d", c.Name, c.Count)
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" rx="2" class="composition-%s"/>`, legendX, legendY, compositionSwatch, compositionSwatch, c.Name)
+		fmt.Fprintf(&b, `<text x="%d" y="%d" class="calendar-label">%s</text>`, legendX+compositionSwatch+4, legendY+compositionSwatch-1, label)
+		legendX += compositionSwatch + 4 + len(label)*compositionCharWidth + 12
+	}
+
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
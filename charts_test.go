@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCommitCalendarSVGScale(t *testing.T) {
+	commitsOn := func(day time.Time, n int) []CommitInfo {
+		var commits []CommitInfo
+		for range n {
+			commits = append(commits, CommitInfo{Timestamp: day.Add(time.Hour)})
+		}
+		return commits
+	}
+	recent := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	// The busiest day is more than a year older than the newest commit, so it is not shown
+	var commits []CommitInfo
+	commits = append(commits, commitsOn(recent.AddDate(-2, 0, 0), 50)...)
+	commits = append(commits, commitsOn(recent, 2)...)
+	commits = append(commits, commitsOn(recent.AddDate(0, 0, -3), 1)...)
+	svg := string(commitCalendarSVG(commits))
+
+	if strings.Contains(svg, "50 commits") {
+		t.Error("calendar shows a day outside its one year window")
+	}
+	cell := func(n int, day time.Time) string {
+		return fmt.Sprintf(`fill="%%s"><title>%s on %s</title>`, pluralize(n, "commit"), day.Format("Jan 2, 2006"))
+	}
+	busiest := fmt.Sprintf(cell(2, recent), calendarColors[len(calendarColors)-1])
+	if !strings.Contains(svg, busiest) {
+		t.Errorf("busiest day shown is not drawn in the darkest color, want %s", busiest)
+	}
+	quieter := fmt.Sprintf(cell(1, recent.AddDate(0, 0, -3)), calendarColors[calendarLevel(1, 2)])
+	if !strings.Contains(svg, quieter) {
+		t.Errorf("quieter day is not scaled against the busiest day shown, want %s", quieter)
+	}
+}
+
+func TestCalendarLevel(t *testing.T) {
+	tests := []struct {
+		n, maxCount, want int
+	}{
+		{0, 0, 0},
+		{0, 10, 0},
+		{1, 1, 4},
+		{1, 100, 1},
+		{25, 100, 1},
+		{26, 100, 2},
+		{50, 100, 2},
+		{75, 100, 3},
+		{100, 100, 4},
+	}
+	for _, tt := range tests {
+		if got := calendarLevel(tt.n, tt.maxCount); got != tt.want {
+			t.Errorf("calendarLevel(%d, %d) = %d, want %d", tt.n, tt.maxCount, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// checkpointFile records the progress of a crawl stopped by -max-api-calls, written to
+// the data directory
+const checkpointFile = "crawl-checkpoint.json"
+
+// errAPIBudget is returned for requests beyond the -max-api-calls budget
+var errAPIBudget = errors.New("API call budget exhausted (-max-api-calls)")
+
+// CrawlCheckpoint lists the repositories a stopped crawl has finished, so -resume can
+// continue with the rest
+type CrawlCheckpoint struct {
+	Owner     string    `json:"owner"`
+	StoppedAt time.Time `json:"stopped_at"`
+	Completed []string  `json:"completed"`
+}
+
+// loadCheckpoint reads the checkpoint from dir, returning nil when there is none.
+func loadCheckpoint(dir string) (*CrawlCheckpoint, error) {
+	data, err := os.ReadFile(filepath.Join(dir, checkpointFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cp CrawlCheckpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, err
+	}
+	return &cp, nil
+}
+
+// saveCheckpoint writes the checkpoint to dir.
+func saveCheckpoint(dir string, cp *CrawlCheckpoint) error {
+	return writeJSON(filepath.Join(dir, checkpointFile), cp)
+}
+
+// removeCheckpoint deletes the checkpoint from dir once a crawl has finished.
+func removeCheckpoint(dir string) error {
+	err := os.Remove(filepath.Join(dir, checkpointFile))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// checkRunName is the name of the check run posted by -checks
+const checkRunName = "Unreleased commits"
+
+// checkRunCommits caps the commits listed in a check run's details
+const checkRunCommits = 20
+
+// newGitHubClient returns a client authenticated with the GITHUB_TOKEN environment variable.
+func newGitHubClient(ctx context.Context) (*github.Client, error) {
+	token := strings.TrimSpace(os.Getenv("GITHUB_TOKEN"))
+	if token == "" {
+		return nil, errors.New("GITHUB_TOKEN environment variable is required")
+	}
+	return github.NewClient(githubHTTPClient(ctx, token)), nil
+}
+
+// runChecks posts a check run summarizing the unreleased commits in data/ on the head of
+// each repository's default branch.
+func runChecks(siteURL string) error {
+	ctx := context.Background()
+	client, err := newGitHubClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	repos, err := loadAllRepositoryData("data")
+	if err != nil {
+		return err
+	}
+
+	posted := 0
+	for _, repo := range repos {
+		branch, _, err := client.Repositories.GetBranch(ctx, repo.Owner, repo.Name, repo.DefaultBranch, 1)
+		if err != nil {
+			fmt.Printf("  ❌ %s: failed to get %s: %v\n", repo.Name, repo.DefaultBranch, err)
+			continue
+		}
+
+		opts := checkRunOptions(repo, branch.GetCommit().GetSHA(), siteURL, time.Now())
+		if _, _, err := client.Checks.CreateCheckRun(ctx, repo.Owner, repo.Name, opts); err != nil {
+			fmt.Printf("  ❌ %s: failed to create check run: %v\n", repo.Name, err)
+			continue
+		}
+		fmt.Printf("  ✅ %s: %s\n", repo.Name, opts.GetOutput().GetTitle())
+		posted++
+	}
+
+	fmt.Printf("✅ Posted %d of %d check runs\n", posted, len(repos))
+	return nil
+}
+
+// checkRunOptions builds the completed check run for a repository. It succeeds when
+// everything is released and is neutral otherwise, so it never blocks a merge.
+func checkRunOptions(repo RepositoryData, headSHA, siteURL string, now time.Time) github.CreateCheckRunOptions {
+	count := len(repo.UnreleasedCommits)
+	conclusion := "neutral"
+	if count == 0 && !repo.NeverReleased {
+		conclusion = "success"
+	}
+
+	var title string
+	var summary strings.Builder
+	switch {
+	case repo.NeverReleased:
+		title = "Never released"
+		fmt.Fprintf(&summary, "`%s` has %d commits and no release yet.", repo.DefaultBranch, repo.TotalCommits)
+	case count == 0:
+		title = "Everything is released in " + repo.LatestReleaseTag
+		fmt.Fprintf(&summary, "`%s` has no commits since [%s](%s/releases/tag/%s).", repo.DefaultBranch, repo.LatestReleaseTag, repo.RepositoryURL, repo.LatestReleaseTag)
+	default:
+		title = fmt.Sprintf("%d unreleased commits since %s", count, repo.LatestReleaseTag)
+		days := int(now.Sub(repo.LatestReleaseTime).Hours() / 24)
+		fmt.Fprintf(&summary, "**%d commits** on `%s` are not in [%s](%s/compare/%s...%s), released %d days ago.", count, repo.DefaultBranch, repo.LatestReleaseTag, repo.RepositoryURL, repo.LatestReleaseTag, repo.DefaultBranch, days)
+		if n := countSecurityFixes(repo.UnreleasedCommits); n > 0 {
+			fmt.Fprintf(&summary, "\n\n%d of them are security fixes.", n)
+		}
+		if n := countBreakingChanges(repo.UnreleasedCommits); n > 0 {
+			fmt.Fprintf(&summary, "\n\n%d of them are breaking changes.", n)
+		}
+	}
+
+	var text strings.Builder
+	for i, c := range repo.UnreleasedCommits {
+		if i == checkRunCommits {
+			fmt.Fprintf(&text, "- …and %d more\n", count-checkRunCommits)
+			break
+		}
+		fmt.Fprintf(&text, "- [`%.7s`](%s) %s (%s)\n", c.SHA, c.URL, commitSubject(c.Message), c.Author)
+	}
+
+	opts := github.CreateCheckRunOptions{
+		Name:        checkRunName,
+		HeadSHA:     headSHA,
+		Status:      github.String("completed"),
+		Conclusion:  github.String(conclusion),
+		CompletedAt: &github.Timestamp{Time: now},
+		Output: &github.CheckRunOutput{
+			Title:   github.String(title),
+			Summary: github.String(summary.String()),
+		},
+	}
+	if text.Len() > 0 {
+		opts.Output.Text = github.String(text.String())
+	}
+	if siteURL != "" && !repo.NeverReleased {
+		opts.DetailsURL = github.String(strings.TrimSuffix(siteURL, "/") + "/" + repo.Name + ".html")
+	}
+	return opts
+}
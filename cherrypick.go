@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// cherryPickPattern matches the trailer added by `git cherry-pick -x`
+var cherryPickPattern = regexp.MustCompile(`\(cherry picked from commit ([0-9a-f]{7,40})\)`)
+
+// markCherryPicked flags unreleased commits whose changes already reached the release
+// through a cherry-pick. The release side is the set of commits reachable from the tag
+// but not from the branch. Commits are matched by the `cherry picked from` trailer, or
+// by an identical subject line confirmed with a patch-id comparison.
+func markCherryPicked(ctx context.Context, client *github.Client, owner, repo, tag, branch string, commits []CommitInfo) (int, error) {
+	releaseOnly, err := compareAllCommits(ctx, client, owner, repo, branch, tag)
+	if err != nil {
+		return 0, err
+	}
+	if len(releaseOnly) == 0 {
+		return 0, nil
+	}
+
+	var pickedFrom []string
+	bySubject := make(map[string][]string)
+	for _, c := range releaseOnly {
+		message := c.GetCommit().GetMessage()
+		for _, m := range cherryPickPattern.FindAllStringSubmatch(message, -1) {
+			pickedFrom = append(pickedFrom, m[1])
+		}
+		subject := commitSubject(message)
+		bySubject[subject] = append(bySubject[subject], c.GetSHA())
+	}
+
+	patchIDs := make(map[string]string)
+	patchID := func(sha string) (string, error) {
+		if id, ok := patchIDs[sha]; ok {
+			return id, nil
+		}
+		full, _, err := client.Repositories.GetCommit(ctx, owner, repo, sha, nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to get commit %s: %w", sha, err)
+		}
+		id := computePatchID(full.Files)
+		patchIDs[sha] = id
+		return id, nil
+	}
+
+	marked := 0
+	for i := range commits {
+		c := &commits[i]
+
+		for _, prefix := range pickedFrom {
+			if strings.HasPrefix(c.SHA, prefix) {
+				c.CherryPicked = true
+				break
+			}
+		}
+
+		if !c.CherryPicked && !c.IsMerge {
+			candidates := bySubject[commitSubject(c.Message)]
+			if len(candidates) > 0 {
+				id, err := patchID(c.SHA)
+				if err != nil {
+					return marked, err
+				}
+				for _, candidate := range candidates {
+					candidateID, err := patchID(candidate)
+					if err != nil {
+						return marked, err
+					}
+					if id != "" && id == candidateID {
+						c.CherryPicked = true
+						break
+					}
+				}
+			}
+		}
+
+		if c.CherryPicked {
+			marked++
+		}
+	}
+
+	return marked, nil
+}
+
+// computePatchID hashes the added and removed lines of each changed file, ignoring
+// whitespace and hunk positions, in the spirit of `git patch-id`. Two commits with
+// the same change produce the same ID even when applied on different parents. Files
+// GitHub sends without a patch, such as binary files, are identified by their blob.
+func computePatchID(files []*github.CommitFile) string {
+	if len(files) == 0 {
+		return ""
+	}
+
+	sorted := append([]*github.CommitFile(nil), files...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].GetFilename() < sorted[j].GetFilename()
+	})
+
+	h := sha1.New()
+	for _, f := range sorted {
+		fmt.Fprintf(h, "file %s\n", f.GetFilename())
+		if f.Patch == nil {
+			fmt.Fprintf(h, "blob %s\n", f.GetSHA())
+			continue
+		}
+		for _, line := range strings.Split(f.GetPatch(), "\n") {
+			if strings.HasPrefix(line, "@@") || line == "" || (line[0] != '+' && line[0] != '-') {
+				continue
+			}
+			h.Write([]byte(strings.Map(func(r rune) rune {
+				if unicode.IsSpace(r) {
+					return -1
+				}
+				return r
+			}, line)))
+			h.Write([]byte("\n"))
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// splitCherryPicked separates commits already released through a cherry-pick from
+// those that are still unreleased.
+func splitCherryPicked(commits []CommitInfo) (unreleased, cherryPicked []CommitInfo) {
+	for _, c := range commits {
+		if c.CherryPicked {
+			cherryPicked = append(cherryPicked, c)
+		} else {
+			unreleased = append(unreleased, c)
+		}
+	}
+	return unreleased, cherryPicked
+}
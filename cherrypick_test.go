@@ -0,0 +1,90 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v62/github"
+)
+
+func TestComputePatchID(t *testing.T) {
+	file := func(name, patch string) *github.CommitFile {
+		return &github.CommitFile{Filename: github.String(name), Patch: github.String(patch)}
+	}
+	original := []*github.CommitFile{
+		file("main.go", "@@ -10,7 +10,7 @@ func main() {\n \tflag.Parse()\n-\trun(false)\n+\trun(true)\n \tos.Exit(0)"),
+		file("README.md", "@@ -1,3 +1,4 @@\n # Example\n+A new line\n \n Usage"),
+	}
+	base := computePatchID(original)
+	if base == "" {
+		t.Fatal("computePatchID returned an empty ID")
+	}
+
+	same := map[string][]*github.CommitFile{
+		"files in another order": {original[1], original[0]},
+		"hunks at other positions": {
+			file("main.go", "@@ -52,7 +58,7 @@ func run() {\n \tflag.Parse()\n-\trun(false)\n+\trun(true)\n \tos.Exit(0)"),
+			file("README.md", "@@ -8,3 +9,4 @@\n # Example\n+A new line\n \n Usage"),
+		},
+		"other context lines": {
+			file("main.go", "@@ -10,7 +10,7 @@ func main() {\n \tlog.Println(\"start\")\n-\trun(false)\n+\trun(true)\n \treturn"),
+			file("README.md", "@@ -1,3 +1,4 @@\n # Other title\n+A new line\n Install"),
+		},
+		"whitespace only differences": {
+			file("main.go", "@@ -10,7 +10,7 @@ func main() {\n \tflag.Parse()\n-    run(false)\n+  run( true )\n \tos.Exit(0)"),
+			file("README.md", "@@ -1,3 +1,4 @@\n # Example\n+A  new line  \n \n Usage"),
+		},
+		"no newline marker": {
+			original[0],
+			file("README.md", "@@ -1,3 +1,4 @@\n # Example\n+A new line\n \n Usage\n\\ No newline at end of file"),
+		},
+	}
+	for name, files := range same {
+		if got := computePatchID(files); got != base {
+			t.Errorf("%s: computePatchID = %s, want %s", name, got, base)
+		}
+	}
+
+	different := map[string][]*github.CommitFile{
+		"different added line": {
+			file("main.go", "@@ -10,7 +10,7 @@ func main() {\n \tflag.Parse()\n-\trun(false)\n+\trun(nil)\n \tos.Exit(0)"),
+			original[1],
+		},
+		"different removed line": {
+			file("main.go", "@@ -10,7 +10,7 @@ func main() {\n \tflag.Parse()\n-\tstart(false)\n+\trun(true)\n \tos.Exit(0)"),
+			original[1],
+		},
+		"added line removed instead": {
+			original[0],
+			file("README.md", "@@ -1,4 +1,3 @@\n # Example\n-A new line\n \n Usage"),
+		},
+		"extra hunk": {
+			original[0],
+			file("README.md", "@@ -1,3 +1,4 @@\n # Example\n+A new line\n \n Usage\n@@ -20,2 +21,3 @@\n License\n+MIT"),
+		},
+		"other file name": {
+			file("cmd/main.go", original[0].GetPatch()),
+			original[1],
+		},
+		"missing file":  {original[0]},
+		"binary change": {original[0], {Filename: github.String("README.md"), SHA: github.String("3b18e512dba79e4c8300dd08aeb37f8e728b8dad")}},
+	}
+	for name, files := range different {
+		if got := computePatchID(files); got == base {
+			t.Errorf("%s: computePatchID matched the original change", name)
+		}
+	}
+
+	binary := func(sha string) []*github.CommitFile {
+		return []*github.CommitFile{{Filename: github.String("logo.png"), SHA: github.String(sha)}}
+	}
+	if computePatchID(binary("a1")) == computePatchID(binary("b2")) {
+		t.Error("computePatchID matched binary changes with different content")
+	}
+	if computePatchID(binary("a1")) != computePatchID(binary("a1")) {
+		t.Error("computePatchID did not match binary changes with the same content")
+	}
+
+	if got := computePatchID(nil); got != "" {
+		t.Errorf("computePatchID(nil) = %q, want empty", got)
+	}
+}
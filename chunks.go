@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// commitChunkSize is how many commits a repository page renders inline. Longer lists are
+// written to <repo>/commits/<n>.json in chunks of this size, which the page fetches as it
+// is scrolled, so repositories with thousands of unreleased commits still load quickly.
+const commitChunkSize = 200
+
+// CommitCards is the data of the commit-cards partial: one chunk of a repository's commit
+// list, continuing the date groups of the chunk before it
+type CommitCards struct {
+	Commits        []CommitInfo
+	CollapseMerges bool
+	RepositoryURL  string
+	PreviousGroup  string
+}
+
+// commitChunk is the document fetched for each chunk after the first, holding the chunk's
+// commit cards rendered exactly as they would be inline
+type commitChunk struct {
+	HTML string `json:"html"`
+}
+
+// commitChunks splits the commits listed on a repository page into chunks of commitChunkSize.
+func commitChunks(commits []CommitInfo) [][]CommitInfo {
+	return slices.Collect(slices.Chunk(commits, commitChunkSize))
+}
+
+// commitChunkPath returns the path of a chunk of a repository's commit list relative to
+// the output directory. The first chunk is part of the page, so files are numbered from 1.
+func commitChunkPath(repoName string, n int) string {
+	return filepath.Join(repoName, "commits", fmt.Sprintf("%d.json", n))
+}
+
+// commitChunkPages returns the paths of the chunk files written for a repository page,
+// relative to the output directory.
+func commitChunkPages(repo RepositoryData, maxCommits int) []string {
+	var pages []string
+	for n := 1; n < len(commitChunks(visibleCommits(repo.UnreleasedCommits, maxCommits))); n++ {
+		pages = append(pages, commitChunkPath(repo.Name, n))
+	}
+	return pages
+}
+
+// writeCommitChunks renders every chunk but the first to its JSON file and removes the
+// files of chunks the list no longer has.
+func writeCommitChunks(tmpl *template.Template, outputDir string, repo RepositoryData, chunks [][]CommitInfo, collapseMerges bool) error {
+	dir := filepath.Join(outputDir, repo.Name, "commits")
+	if len(chunks) <= 1 {
+		return os.RemoveAll(dir)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	keep := make(map[string]bool)
+	for n := 1; n < len(chunks); n++ {
+		previous := chunks[n-1]
+		var buf bytes.Buffer
+		err := tmpl.ExecuteTemplate(&buf, "commit-cards", CommitCards{
+			Commits:        chunks[n],
+			CollapseMerges: collapseMerges,
+			RepositoryURL:  repo.RepositoryURL,
+			PreviousGroup:  currentDateGroup(previous[len(previous)-1].Timestamp),
+		})
+		if err != nil {
+			return err
+		}
+		content, err := json.Marshal(commitChunk{HTML: buf.String()})
+		if err != nil {
+			return err
+		}
+		filename := filepath.Join(outputDir, commitChunkPath(repo.Name, n))
+		if err := writeFileIfChanged(filename, content); err != nil {
+			return err
+		}
+		keep[filename] = true
+	}
+
+	existing, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, filename := range existing {
+		if !keep[filename] {
+			if err := os.Remove(filename); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
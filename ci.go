@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// CIStatus is the result of the most recent GitHub Actions run on the default branch
+type CIStatus struct {
+	Workflow string    `json:"workflow"`
+	Status   string    `json:"status"`
+	URL      string    `json:"url"`
+	Time     time.Time `json:"time"`
+}
+
+// fetchCIStatus returns the latest workflow run on branch. Status is the run's
+// conclusion (success, failure, cancelled, ...) once it has completed, or its progress
+// (queued, in_progress) until then. It returns nil when the branch has no runs.
+func fetchCIStatus(ctx context.Context, client *github.Client, owner, repo, branch string) (*CIStatus, error) {
+	runs, _, err := client.Actions.ListRepositoryWorkflowRuns(ctx, owner, repo, &github.ListWorkflowRunsOptions{
+		Branch:              branch,
+		ExcludePullRequests: true,
+		ListOptions:         github.ListOptions{PerPage: 1},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(runs.WorkflowRuns) == 0 {
+		return nil, nil
+	}
+
+	run := runs.WorkflowRuns[0]
+	status := run.GetStatus()
+	if status == "completed" {
+		status = run.GetConclusion()
+	}
+	return &CIStatus{
+		Workflow: run.GetName(),
+		Status:   status,
+		URL:      run.GetHTMLURL(),
+		Time:     run.GetCreatedAt().Time,
+	}, nil
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// dependencyBots are commit authors that only ever produce dependency updates
+var dependencyBots = map[string]bool{
+	"dependabot[bot]":         true,
+	"dependabot-preview[bot]": true,
+	"renovate[bot]":           true,
+	"renovate-bot":            true,
+	"depfu[bot]":              true,
+	"pyup-bot":                true,
+	"snyk-bot":                true,
+}
+
+// dependencyMessagePatterns match the subject lines written by dependency update tools
+var dependencyMessagePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)^(chore|build|fix|ci)\(deps(-dev)?\)`),
+	regexp.MustCompile(`(?i)^bump \S+ from \S+ to \S+`),
+	regexp.MustCompile(`(?i)^bump the .+ group`),
+	regexp.MustCompile(`(?i)^update (dependency|module) \S+ to `),
+	regexp.MustCompile(`(?i)^update \S+ (digest|action) to `),
+	regexp.MustCompile(`(?i)^merge pull request #\d+ from \S+/(dependabot|renovate)/`),
+}
+
+// conventionalCommitPattern matches a conventional commit subject such as
+// `feat(api)!: remove v1 endpoints`, capturing the type and the breaking change marker
+var conventionalCommitPattern = regexp.MustCompile(`^(\w+)(?:\([^)]*\))?(!)?: `)
+
+// breakingChangeFooterPattern matches the footer that marks a conventional commit as breaking
+var breakingChangeFooterPattern = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: `)
+
+// commitCategories are the categories commitCategory sorts commits into, in display order
+var commitCategories = []string{"feat", "fix", "perf", "docs", "chore", "deps", "other"}
+
+// choreTypes are the conventional commit types grouped as chores, since none of them
+// changes what users get
+var choreTypes = map[string]bool{"chore": true, "build": true, "ci": true, "refactor": true, "style": true, "test": true}
+
+// commitCategory returns which of commitCategories a commit falls into: deps for
+// dependency updates, otherwise its conventional commit type with maintenance types
+// grouped as chore, and other for anything else.
+func commitCategory(c CommitInfo) string {
+	if isDependencyUpdate(c) {
+		return "deps"
+	}
+	t := commitType(c.Message)
+	switch {
+	case choreTypes[t]:
+		return "chore"
+	case slices.Contains(commitCategories, t):
+		return t
+	}
+	return "other"
+}
+
+// categoryCounts tallies the commits by commitCategory in display order, leaving out
+// categories without commits.
+func categoryCounts(commits []CommitInfo) []LabelCount {
+	counts := make(map[string]int)
+	for _, c := range commits {
+		counts[commitCategory(c)]++
+	}
+	var result []LabelCount
+	for _, category := range commitCategories {
+		if counts[category] > 0 {
+			result = append(result, LabelCount{Name: category, Count: counts[category]})
+		}
+	}
+	return result
+}
+
+// commitType returns the lower-cased conventional commit type (feat, fix, ...) of a
+// message, or "" when the subject does not follow the convention.
+func commitType(message string) string {
+	m := conventionalCommitPattern.FindStringSubmatch(commitSubject(message))
+	if m == nil {
+		return ""
+	}
+	return strings.ToLower(m[1])
+}
+
+// isBreakingChange reports whether a commit is marked as a breaking change with `!`
+// after its conventional commit type or a `BREAKING CHANGE:` footer.
+func isBreakingChange(message string) bool {
+	if m := conventionalCommitPattern.FindStringSubmatch(commitSubject(message)); m != nil && m[2] != "" {
+		return true
+	}
+	return breakingChangeFooterPattern.MatchString(commitBody(message))
+}
+
+// advisoryPatterns match references to a CVE or GitHub security advisory
+var advisoryPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\bCVE-\d{4}-\d{4,}\b`),
+	regexp.MustCompile(`(?i)\bGHSA(-[23456789cfghjmpqrvwx]{4}){3}\b`),
+}
+
+// securityIssuePatterns match commit messages that describe a security issue. A bare
+// "security" is not enough, since it also names policies, scanners and SECURITY.md
+var securityIssuePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b(vulnerabilit(y|ies)|vulnerable|XSS|CSRF|SSRF|RCE|(sql|command) injection|path traversal|privilege escalation)\b`),
+	regexp.MustCompile(`(?i)\bsecurity[ -](fix|issue|vulnerabilit(y|ies)|patch|bug|hole|flaw|problem)(e?s)?\b`),
+}
+
+// securityFixSubjectPattern matches a fix whose subject mentions security or an exploit,
+// such as "fix(auth): prevent exploitable redirect"
+var securityFixSubjectPattern = regexp.MustCompile(`(?i)^fix(\([^)]*\))?!?:.*\b(security|exploit(able)?)([^-\w]|$)`)
+
+// isSecurityFix reports whether a commit message references a CVE or GHSA ID, or
+// describes a security issue outside of documentation, maintenance and dependency
+// update commits.
+func isSecurityFix(message string) bool {
+	for _, pattern := range advisoryPatterns {
+		if pattern.MatchString(message) {
+			return true
+		}
+	}
+
+	subject := commitSubject(message)
+	if t := commitType(message); t == "docs" || choreTypes[t] {
+		return false
+	}
+	for _, pattern := range dependencyMessagePatterns {
+		if pattern.MatchString(subject) {
+			return false
+		}
+	}
+
+	if securityFixSubjectPattern.MatchString(subject) {
+		return true
+	}
+	for _, pattern := range securityIssuePatterns {
+		if pattern.MatchString(message) {
+			return true
+		}
+	}
+	return false
+}
+
+// countSecurityFixes returns how many of the commits are security fixes.
+func countSecurityFixes(commits []CommitInfo) int {
+	count := 0
+	for _, c := range commits {
+		if isSecurityFix(c.Message) {
+			count++
+		}
+	}
+	return count
+}
+
+// securityFixDays returns how many days the oldest unreleased security fix has been
+// waiting for a release, or 0 when there is none.
+func securityFixDays(commits []CommitInfo) int {
+	var oldest time.Time
+	for _, c := range commits {
+		if isSecurityFix(c.Message) && (oldest.IsZero() || c.Timestamp.Before(oldest)) {
+			oldest = c.Timestamp
+		}
+	}
+	if oldest.IsZero() {
+		return 0
+	}
+	return int(time.Since(oldest).Hours() / 24)
+}
+
+// countBreakingChanges returns how many of the commits are marked as breaking changes.
+func countBreakingChanges(commits []CommitInfo) int {
+	count := 0
+	for _, c := range commits {
+		if isBreakingChange(c.Message) {
+			count++
+		}
+	}
+	return count
+}
+
+// commitSubject returns the first line of a commit message.
+func commitSubject(message string) string {
+	subject, _, _ := strings.Cut(message, "\n")
+	return strings.TrimSpace(subject)
+}
+
+// commitBody returns the commit message after the subject line, with surrounding blank lines removed.
+func commitBody(message string) string {
+	_, body, _ := strings.Cut(message, "\n")
+	return strings.TrimSpace(body)
+}
+
+// isDependencyUpdate reports whether a commit looks like an automated dependency
+// update, based on its author and subject line.
+func isDependencyUpdate(c CommitInfo) bool {
+	if dependencyBots[strings.ToLower(c.Author)] {
+		return true
+	}
+	subject := commitSubject(c.Message)
+	for _, pattern := range dependencyMessagePatterns {
+		if pattern.MatchString(subject) {
+			return true
+		}
+	}
+	return false
+}
+
+// countDependencyUpdates returns how many of the commits are dependency updates.
+func countDependencyUpdates(commits []CommitInfo) int {
+	count := 0
+	for _, c := range commits {
+		if isDependencyUpdate(c) {
+			count++
+		}
+	}
+	return count
+}
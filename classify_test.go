@@ -0,0 +1,35 @@
+package main
+
+import "testing"
+
+func TestIsSecurityFix(t *testing.T) {
+	tests := []struct {
+		message string
+		want    bool
+	}{
+		{"Fix CVE-2024-12345 in the parser", true},
+		{"chore: bump golang.org/x/net for GHSA-qppj-fm5r-hxr3", true},
+		{"Prevent XSS in commit messages", true},
+		{"fix: escape HTML to avoid a vulnerability", true},
+		{"Sanitize paths against path traversal", true},
+		{"fix(auth): close a security hole in session handling", true},
+		{"fix: security issue with token logging", true},
+		{"fix(api): harden security of webhook validation", true},
+		{"fix: reject exploitable redirect targets", true},
+
+		{"Add SECURITY.md", false},
+		{"docs: update security policy", false},
+		{"chore: bump security-scanner action", false},
+		{"docs: describe how to report a vulnerability", false},
+		{"ci: run the security scan nightly", false},
+		{"Bump github.com/securego/gosec from 2.18.0 to 2.19.0", false},
+		{"feat: add a security settings page", false},
+		{"fix: rename security-scanner job", false},
+		{"Refactor commit classification", false},
+	}
+	for _, tt := range tests {
+		if got := isSecurityFix(tt.message); got != tt.want {
+			t.Errorf("isSecurityFix(%q) = %v, want %v", tt.message, got, tt.want)
+		}
+	}
+}
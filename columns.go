@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+)
+
+// IndexColumn is a column that can be shown in the index table. Sort names the row's
+// data attribute the table is sorted by when the header is clicked, if it is sortable
+type IndexColumn struct {
+	Key    string
+	Header string
+	Sort   string
+}
+
+// availableIndexColumns are the columns that can be listed in the index_columns config,
+// each rendered by the partial named "cell-<key>"
+var availableIndexColumns = []IndexColumn{
+	{Key: "name", Header: "Repository", Sort: "name"},
+	{Key: "language", Header: "Language", Sort: "language"},
+	{Key: "license", Header: "License", Sort: "license"},
+	{Key: "latest_release", Header: "Latest Release"},
+	{Key: "release_date", Header: "Release Date", Sort: "release_date"},
+	{Key: "default_branch", Header: "Default Branch", Sort: "default_branch"},
+	{Key: "commits", Header: "Unreleased Commits", Sort: "commits"},
+	{Key: "channels", Header: "Channels"},
+	{Key: "open_prs", Header: "Open PRs", Sort: "open_prs"},
+	{Key: "days_behind", Header: "Days Behind", Sort: "days_behind"},
+	{Key: "days_since_release", Header: "Days Since Release", Sort: "days_since"},
+	{Key: "stars", Header: "Stars", Sort: "stars"},
+	{Key: "forks", Header: "Forks", Sort: "forks"},
+	{Key: "open_issues", Header: "Open Issues", Sort: "open_issues"},
+	{Key: "impact", Header: "Impact", Sort: "impact"},
+	{Key: "ci_status", Header: "CI Status", Sort: "ci_status"},
+	{Key: "publish_lag", Header: "Publish Lag", Sort: "publish_lag"},
+}
+
+// defaultIndexColumns are shown when the config does not set index_columns
+var defaultIndexColumns = []string{"name", "language", "license", "latest_release", "commits", "days_behind", "days_since_release", "impact"}
+
+// validateIndexColumns checks that keys name known columns, each at most once, and
+// include the repository name that links to each repository's page.
+func validateIndexColumns(keys []string) error {
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		if !slices.ContainsFunc(availableIndexColumns, func(c IndexColumn) bool { return c.Key == key }) {
+			return fmt.Errorf("unknown column %q", key)
+		}
+		if seen[key] {
+			return fmt.Errorf("column %q is listed more than once", key)
+		}
+		seen[key] = true
+	}
+	if !seen["name"] {
+		return fmt.Errorf("the name column is required")
+	}
+	return nil
+}
+
+// indexColumns returns the columns named by keys in display order, or the default
+// columns when keys is empty.
+func indexColumns(keys []string) []IndexColumn {
+	if len(keys) == 0 {
+		keys = defaultIndexColumns
+	}
+	columns := make([]IndexColumn, 0, len(keys))
+	for _, key := range keys {
+		i := slices.IndexFunc(availableIndexColumns, func(c IndexColumn) bool { return c.Key == key })
+		if i >= 0 {
+			columns = append(columns, availableIndexColumns[i])
+		}
+	}
+	return columns
+}
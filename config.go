@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// Config is the optional JSON configuration file passed with -config
+type Config struct {
+	TagPattern       string                    `json:"tag_pattern,omitempty"`
+	Theme            string                    `json:"theme,omitempty"`
+	SiteURL          string                    `json:"site_url,omitempty"`
+	IndexColumns     []string                  `json:"index_columns,omitempty"`
+	Channels         []ChannelConfig           `json:"channels,omitempty"`
+	ColorThresholds  map[string]ColorThreshold `json:"color_thresholds,omitempty"`
+	ColorScale       string                    `json:"color_scale,omitempty"`
+	HeatMap          map[string]bool           `json:"heat_map,omitempty"`
+	Webhooks         []WebhookConfig           `json:"webhooks,omitempty"`
+	Teams            *TeamsConfig              `json:"teams,omitempty"`
+	Escalation       *EscalationConfig         `json:"escalation,omitempty"`
+	HistoryRetention HistoryRetention          `json:"history_retention,omitzero"`
+	BigQuery         *BigQueryConfig           `json:"bigquery,omitempty"`
+	Pushgateway      *PushgatewayConfig        `json:"pushgateway,omitempty"`
+	Homebrew         *HomebrewConfig           `json:"homebrew,omitempty"`
+	HTTP             HTTPConfig                `json:"http,omitzero"`
+	Groups           []GroupConfig             `json:"groups,omitempty"`
+	Pinned           []string                  `json:"pinned,omitempty"`
+	Repos            map[string]RepoConfig     `json:"repos,omitempty"`
+}
+
+// RepoConfig holds settings that apply to a single repository
+type RepoConfig struct {
+	Branch        string          `json:"branch,omitempty"`
+	CompareBranch string          `json:"compare_branch,omitempty"`
+	TagPattern    string          `json:"tag_pattern,omitempty"`
+	Channels      []ChannelConfig `json:"channels,omitempty"`
+	Ignore        []string        `json:"ignore,omitempty"`
+	Paths         []string        `json:"paths,omitempty"`
+	Release       string          `json:"release,omitempty"`
+	MarkerFile    string          `json:"marker_file,omitempty"`
+	Image         string          `json:"image,omitempty"`
+	Formula       string          `json:"formula,omitempty"`
+}
+
+// loadConfig reads the configuration file at path. An empty path yields an empty configuration.
+func loadConfig(path string) (*Config, error) {
+	cfg := &Config{}
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// validate checks settings that can be verified without contacting GitHub.
+func (c *Config) validate() error {
+	if _, err := regexp.Compile(c.TagPattern); err != nil {
+		return fmt.Errorf("tag_pattern: %w", err)
+	}
+	if c.Theme != "" && !validTheme(c.Theme) {
+		return fmt.Errorf("theme: unknown theme %q", c.Theme)
+	}
+	if len(c.IndexColumns) > 0 {
+		if err := validateIndexColumns(c.IndexColumns); err != nil {
+			return fmt.Errorf("index_columns: %w", err)
+		}
+	}
+	switch c.ColorScale {
+	case "", ColorScaleLinear, ColorScaleLog:
+	default:
+		return fmt.Errorf("color_scale: unknown scale %q", c.ColorScale)
+	}
+	if err := validateHeatMap(c.HeatMap); err != nil {
+		return fmt.Errorf("heat_map: %w", err)
+	}
+	if err := validateColorThresholds(c.ColorThresholds); err != nil {
+		return fmt.Errorf("color_thresholds: %w", err)
+	}
+	if err := validateChannels(c.Channels); err != nil {
+		return fmt.Errorf("channels: %w", err)
+	}
+	if err := validateWebhooks(c.Webhooks); err != nil {
+		return fmt.Errorf("webhooks: %w", err)
+	}
+	if err := validateTeams(c.Teams); err != nil {
+		return fmt.Errorf("teams: %w", err)
+	}
+	if err := validateEscalation(c.Escalation); err != nil {
+		return fmt.Errorf("escalation: %w", err)
+	}
+	if err := validateHistoryRetention(c.HistoryRetention); err != nil {
+		return fmt.Errorf("history_retention: %w", err)
+	}
+	if err := validateBigQuery(c.BigQuery); err != nil {
+		return fmt.Errorf("bigquery: %w", err)
+	}
+	if err := validatePushgateway(c.Pushgateway); err != nil {
+		return fmt.Errorf("pushgateway: %w", err)
+	}
+	if err := validateHomebrew(c.Homebrew); err != nil {
+		return fmt.Errorf("homebrew: %w", err)
+	}
+	if err := validateGroups(c.Groups); err != nil {
+		return fmt.Errorf("groups: %w", err)
+	}
+	if err := validatePinned(c.Pinned); err != nil {
+		return fmt.Errorf("pinned: %w", err)
+	}
+	if err := validateHTTP(c.HTTP); err != nil {
+		return fmt.Errorf("http: %w", err)
+	}
+	for name, repo := range c.Repos {
+		if err := validateRepoConfig(repo); err != nil {
+			return fmt.Errorf("repos.%s.%w", name, err)
+		}
+	}
+	return nil
+}
+
+// validateChannels checks that every channel has a unique name and a valid, non-empty tag pattern.
+func validateChannels(channels []ChannelConfig) error {
+	seen := make(map[string]bool)
+	for _, channel := range channels {
+		if channel.Name == "" {
+			return fmt.Errorf("channel without a name")
+		}
+		if seen[channel.Name] {
+			return fmt.Errorf("duplicate channel %q", channel.Name)
+		}
+		seen[channel.Name] = true
+		if channel.TagPattern == "" {
+			return fmt.Errorf("%s: tag_pattern is required", channel.Name)
+		}
+		if _, err := regexp.Compile(channel.TagPattern); err != nil {
+			return fmt.Errorf("%s: %w", channel.Name, err)
+		}
+	}
+	return nil
+}
+
+// notifies reports whether anything is notified after a crawl.
+func (c *Config) notifies() bool {
+	return c != nil && (len(c.Webhooks) > 0 || c.Teams != nil || c.Escalation != nil)
+}
+
+// Repo returns the configuration for the named repository, or the zero value if it has none.
+func (c *Config) Repo(name string) RepoConfig {
+	if c == nil {
+		return RepoConfig{}
+	}
+	return c.Repos[name]
+}
+
+// TagPatternFor returns the compiled tag pattern for the named repository, preferring the
+// repository's own pattern over the global one. It returns nil when no pattern applies.
+func (c *Config) TagPatternFor(name string) *regexp.Regexp {
+	if c == nil {
+		return nil
+	}
+	pattern := c.Repo(name).TagPattern
+	if pattern == "" {
+		pattern = c.TagPattern
+	}
+	if pattern == "" {
+		return nil
+	}
+	// Patterns are checked when the config is loaded
+	return regexp.MustCompile(pattern)
+}
+
+// ChannelsFor returns the release channels of the named repository, preferring the
+// repository's own channels over the global ones.
+func (c *Config) ChannelsFor(name string) []ChannelConfig {
+	if c == nil {
+		return nil
+	}
+	if channels := c.Repo(name).Channels; len(channels) > 0 {
+		return channels
+	}
+	return c.Channels
+}
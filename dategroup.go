@@ -0,0 +1,31 @@
+package main
+
+import "time"
+
+// dateGroup returns the heading a commit made at t is listed under on a repository
+// page: "Today" and "Yesterday" for recent commits, otherwise the week starting on the
+// Monday before t, with the year added once it differs from now's.
+func dateGroup(t, now time.Time) string {
+	now = now.In(t.Location())
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch {
+	case !day.Before(today):
+		return "Today"
+	case day.Equal(today.AddDate(0, 0, -1)):
+		return "Yesterday"
+	}
+
+	// time.Weekday counts from Sunday, weeks here start on Monday
+	monday := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	if monday.Year() != now.Year() {
+		return "Week of " + monday.Format("January 2, 2006")
+	}
+	return "Week of " + monday.Format("January 2")
+}
+
+// currentDateGroup returns the date group of t relative to when the site is generated.
+func currentDateGroup(t time.Time) string {
+	return dateGroup(t, time.Now())
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// Dependabot alert states recorded by -dependabot
+const (
+	AlertOpen  = "open"
+	AlertFixed = "fixed"
+)
+
+// DependabotAlertInfo is a Dependabot alert that is still open, or was fixed on the
+// default branch after the latest release
+type DependabotAlertInfo struct {
+	Number     int       `json:"number"`
+	State      string    `json:"state"`
+	Package    string    `json:"package"`
+	Ecosystem  string    `json:"ecosystem,omitempty"`
+	Severity   string    `json:"severity,omitempty"`
+	Summary    string    `json:"summary,omitempty"`
+	AdvisoryID string    `json:"advisory_id,omitempty"`
+	CVE        string    `json:"cve,omitempty"`
+	URL        string    `json:"url"`
+	FixedAt    time.Time `json:"fixed_at,omitzero"`
+}
+
+// fetchDependabotAlerts returns the repository's open alerts and the alerts fixed after
+// releaseTime, whose fix is merged but not yet released.
+func fetchDependabotAlerts(ctx context.Context, client *github.Client, owner, repo string, releaseTime time.Time) ([]DependabotAlertInfo, error) {
+	var alerts []DependabotAlertInfo
+	opt := &github.ListAlertsOptions{
+		State:             github.String(AlertOpen + "," + AlertFixed),
+		ListCursorOptions: github.ListCursorOptions{PerPage: 100},
+	}
+
+	for {
+		page, resp, err := client.Dependabot.ListRepoAlerts(ctx, owner, repo, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, a := range page {
+			info := DependabotAlertInfo{
+				Number:     a.GetNumber(),
+				State:      a.GetState(),
+				Package:    a.GetDependency().GetPackage().GetName(),
+				Ecosystem:  a.GetDependency().GetPackage().GetEcosystem(),
+				Severity:   a.GetSecurityAdvisory().GetSeverity(),
+				Summary:    a.GetSecurityAdvisory().GetSummary(),
+				AdvisoryID: a.GetSecurityAdvisory().GetGHSAID(),
+				CVE:        a.GetSecurityAdvisory().GetCVEID(),
+				URL:        a.GetHTMLURL(),
+				FixedAt:    a.GetFixedAt().Time,
+			}
+			if info.State == AlertFixed && !info.FixedAt.After(releaseTime) {
+				// Already part of the latest release
+				continue
+			}
+			alerts = append(alerts, info)
+		}
+
+		if resp.After == "" {
+			break
+		}
+		opt.ListCursorOptions.After = resp.After
+	}
+
+	return alerts, nil
+}
+
+// countAlerts returns how many of the alerts are in state.
+func countAlerts(alerts []DependabotAlertInfo, state string) int {
+	count := 0
+	for _, a := range alerts {
+		if a.State == state {
+			count++
+		}
+	}
+	return count
+}
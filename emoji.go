@@ -0,0 +1,124 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// emojiShortcode matches :name: style shortcodes
+var emojiShortcode = regexp.MustCompile(`:([a-z0-9_+\-]+):`)
+
+// emojiVerbatim matches the parts of a message that are left as written: code spans,
+// Markdown link targets and URLs
+var emojiVerbatim = regexp.MustCompile("`[^`]*`|\\]\\([^)\\s]*\\)|https?://[^\\s<>\"]+")
+
+// emojiShortcodes maps the gitmoji set and a few other common shortcodes to their emoji
+var emojiShortcodes = map[string]string{
+	"adhesive_bandage":          "🩹",
+	"alembic":                   "⚗️",
+	"alien":                     "👽",
+	"ambulance":                 "🚑",
+	"apple":                     "🍎",
+	"arrow_down":                "⬇️",
+	"arrow_up":                  "⬆️",
+	"art":                       "🎨",
+	"beers":                     "🍻",
+	"bento":                     "🍱",
+	"bookmark":                  "🔖",
+	"boom":                      "💥",
+	"brain":                     "🧠",
+	"bricks":                    "🧱",
+	"bug":                       "🐛",
+	"building_construction":     "🏗️",
+	"bulb":                      "💡",
+	"busts_in_silhouette":       "👥",
+	"card_file_box":             "🗃️",
+	"chart_with_upwards_trend":  "📈",
+	"check":                     "✔️",
+	"checkered_flag":            "🏁",
+	"children_crossing":         "🚸",
+	"clown_face":                "🤡",
+	"coffin":                    "⚰️",
+	"construction":              "🚧",
+	"construction_worker":       "👷",
+	"dizzy":                     "💫",
+	"egg":                       "🥚",
+	"fire":                      "🔥",
+	"globe_with_meridians":      "🌐",
+	"goal_net":                  "🥅",
+	"green_apple":               "🍏",
+	"green_heart":               "💚",
+	"hammer":                    "🔨",
+	"heart":                     "❤️",
+	"heavy_check_mark":          "✔️",
+	"heavy_minus_sign":          "➖",
+	"heavy_plus_sign":           "➕",
+	"iphone":                    "📱",
+	"label":                     "🏷️",
+	"lipstick":                  "💄",
+	"lock":                      "🔒",
+	"loud_sound":                "🔊",
+	"mag":                       "🔍",
+	"memo":                      "📝",
+	"money_with_wings":          "💸",
+	"monocle_face":              "🧐",
+	"mute":                      "🔇",
+	"necktie":                   "👔",
+	"package":                   "📦",
+	"page_facing_up":            "📄",
+	"passport_control":          "🛂",
+	"pencil":                    "📝",
+	"pencil2":                   "✏️",
+	"penguin":                   "🐧",
+	"poop":                      "💩",
+	"pushpin":                   "📌",
+	"recycle":                   "♻️",
+	"rewind":                    "⏪",
+	"rocket":                    "🚀",
+	"rotating_light":            "🚨",
+	"safety_vest":               "🦺",
+	"see_no_evil":               "🙈",
+	"seedling":                  "🌱",
+	"sparkles":                  "✨",
+	"speech_balloon":            "💬",
+	"stethoscope":               "🩺",
+	"tada":                      "🎉",
+	"technologist":              "🧑‍💻",
+	"test_tube":                 "🧪",
+	"thread":                    "🧵",
+	"triangular_flag_on_post":   "🚩",
+	"truck":                     "🚚",
+	"twisted_rightwards_arrows": "🔀",
+	"warning":                   "⚠️",
+	"wastebasket":               "🗑️",
+	"wheel_of_dharma":           "☸️",
+	"white_check_mark":          "✅",
+	"wrench":                    "🔧",
+	"x":                         "❌",
+	"zap":                       "⚡️",
+}
+
+// emojify replaces known emoji shortcodes in text with the emoji they stand for,
+// leaving unknown shortcodes and those inside code spans, link targets and URLs
+// untouched.
+func emojify(text string) string {
+	var out strings.Builder
+	last := 0
+	for _, loc := range emojiVerbatim.FindAllStringIndex(text, -1) {
+		out.WriteString(replaceShortcodes(text[last:loc[0]]))
+		out.WriteString(text[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	out.WriteString(replaceShortcodes(text[last:]))
+	return out.String()
+}
+
+// replaceShortcodes replaces the known emoji shortcodes in text.
+func replaceShortcodes(text string) string {
+	return emojiShortcode.ReplaceAllStringFunc(text, func(code string) string {
+		if emoji, ok := emojiShortcodes[code[1:len(code)-1]]; ok {
+			return emoji
+		}
+		return code
+	})
+}
@@ -0,0 +1,25 @@
+package main
+
+import "testing"
+
+func TestEmojify(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{":sparkles: add a flag", "✨ add a flag"},
+		{"fix :bug: and :memo: docs", "fix 🐛 and 📝 docs"},
+		{":not_an_emoji: stays", ":not_an_emoji: stays"},
+		{"rename `:bug:` to `:sparkles:` :bug:", "rename `:bug:` to `:sparkles:` 🐛"},
+		{"see https://example.com/a:bug:b for :bug:", "see https://example.com/a:bug:b for 🐛"},
+		{"[:bug: tracker](https://example.com/:bug:/list)", "[🐛 tracker](https://example.com/:bug:/list)"},
+		{"[notes](docs/:memo:.md) :memo:", "[notes](docs/:memo:.md) 📝"},
+		{"unclosed ` :bug:", "unclosed ` 🐛"},
+		{"time 10:30:45", "time 10:30:45"},
+	}
+	for _, tt := range tests {
+		if got := emojify(tt.text); got != tt.want {
+			t.Errorf("emojify(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// escalationsFile lists the alerts opened by the last crawl, written to the data
+// directory so they can be resolved once the repository is back within its SLA
+const escalationsFile = "escalations.json"
+
+// Escalation rules, used in alert keys and descriptions
+const (
+	RuleSecurityFix = "security_fix_days"
+	RuleDaysBehind  = "days_behind"
+)
+
+// Alerting API endpoints
+const (
+	pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+	opsgenieAPIURL     = "https://api.opsgenie.com"
+)
+
+// EscalationConfig opens a PagerDuty or Opsgenie alert for each repository that breaches
+// a critical SLA
+type EscalationConfig struct {
+	SecurityFixDays int              `json:"security_fix_days,omitempty"`
+	DaysBehind      int              `json:"days_behind,omitempty"`
+	PagerDuty       *PagerDutyConfig `json:"pagerduty,omitempty"`
+	Opsgenie        *OpsgenieConfig  `json:"opsgenie,omitempty"`
+}
+
+// PagerDutyConfig sends alerts to a PagerDuty service through the Events API v2
+type PagerDutyConfig struct {
+	RoutingKeyEnv string `json:"routing_key_env"`
+}
+
+// OpsgenieConfig sends alerts through the Opsgenie Alert API
+type OpsgenieConfig struct {
+	APIKeyEnv string `json:"api_key_env"`
+	APIURL    string `json:"api_url,omitempty"`
+}
+
+// SLABreach is a repository that breaches an escalation rule
+type SLABreach struct {
+	Key     string
+	Repo    string
+	Summary string
+	URL     string
+}
+
+// EscalationState is the content of escalationsFile
+type EscalationState struct {
+	Open []string `json:"open"`
+}
+
+// validateEscalation checks that escalation has a rule and a destination, each with its
+// credentials' environment variable.
+func validateEscalation(e *EscalationConfig) error {
+	if e == nil {
+		return nil
+	}
+	if e.SecurityFixDays < 0 || e.DaysBehind < 0 {
+		return fmt.Errorf("thresholds cannot be negative")
+	}
+	if e.SecurityFixDays == 0 && e.DaysBehind == 0 {
+		return fmt.Errorf("set security_fix_days or days_behind")
+	}
+	if e.PagerDuty == nil && e.Opsgenie == nil {
+		return fmt.Errorf("set pagerduty or opsgenie")
+	}
+	if e.PagerDuty != nil && e.PagerDuty.RoutingKeyEnv == "" {
+		return fmt.Errorf("pagerduty: routing_key_env is required")
+	}
+	if e.Opsgenie != nil && e.Opsgenie.APIKeyEnv == "" {
+		return fmt.Errorf("opsgenie: api_key_env is required")
+	}
+	return nil
+}
+
+// slaBreaches returns the repositories that breach a rule: an unreleased security fix
+// waiting longer than SecurityFixDays, or a default branch more than DaysBehind days
+// ahead of the latest release.
+func (e *EscalationConfig) slaBreaches(n CrawlNotification) []SLABreach {
+	var breaches []SLABreach
+	for _, r := range n.Repos {
+		if e.SecurityFixDays > 0 && r.SecurityFixDays > e.SecurityFixDays {
+			breaches = append(breaches, SLABreach{
+				Key:     n.Owner + "/" + r.Name + "/" + RuleSecurityFix,
+				Repo:    r.Name,
+				Summary: fmt.Sprintf("%s: security fix unreleased for %d days (SLA %d days)", r.Name, r.SecurityFixDays, e.SecurityFixDays),
+				URL:     r.RepositoryURL,
+			})
+		}
+		if e.DaysBehind > 0 && r.DaysBehind > e.DaysBehind {
+			breaches = append(breaches, SLABreach{
+				Key:     n.Owner + "/" + r.Name + "/" + RuleDaysBehind,
+				Repo:    r.Name,
+				Summary: fmt.Sprintf("%s: %d unreleased commits, %d days behind (SLA %d days)", r.Name, r.UnreleasedCommits, r.DaysBehind, e.DaysBehind),
+				URL:     r.RepositoryURL,
+			})
+		}
+	}
+	return breaches
+}
+
+// escalate opens an alert for every SLA breach and resolves the alerts opened by earlier
+// crawls whose repository is back within its SLA. Alerts are keyed by repository and
+// rule, so a breach that persists across crawls stays a single alert.
+func escalate(ctx context.Context, dir string, e *EscalationConfig, n CrawlNotification) {
+	state, err := loadEscalationState(dir)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to read %s: %v\n", escalationsFile, err)
+	}
+
+	client := &http.Client{Timeout: webhookTimeout}
+	var open []string
+	current := make(map[string]bool)
+	for _, breach := range e.slaBreaches(n) {
+		current[breach.Key] = true
+		if err := e.send(ctx, client, breach, true); err != nil {
+			fmt.Printf("⚠️  Failed to escalate %s: %v\n", breach.Key, err)
+		} else {
+			fmt.Printf("🚨 Escalated %s\n", breach.Summary)
+		}
+		// Kept open even when sending failed, so it is resolved later if it was delivered
+		open = append(open, breach.Key)
+	}
+
+	for _, key := range state.Open {
+		if current[key] {
+			continue
+		}
+		if err := e.send(ctx, client, SLABreach{Key: key}, false); err != nil {
+			fmt.Printf("⚠️  Failed to resolve %s: %v\n", key, err)
+			open = append(open, key)
+			continue
+		}
+		fmt.Printf("✅ Resolved %s\n", key)
+	}
+
+	slices.Sort(open)
+	if err := writeJSON(filepath.Join(dir, escalationsFile), EscalationState{Open: open}); err != nil {
+		fmt.Printf("⚠️  Failed to write %s: %v\n", escalationsFile, err)
+	}
+}
+
+// loadEscalationState reads the alerts opened by the last crawl from dir.
+func loadEscalationState(dir string) (EscalationState, error) {
+	var state EscalationState
+	data, err := os.ReadFile(filepath.Join(dir, escalationsFile))
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+	err = json.Unmarshal(data, &state)
+	return state, err
+}
+
+// send triggers or resolves the alert for a breach with every configured destination.
+func (e *EscalationConfig) send(ctx context.Context, client *http.Client, breach SLABreach, trigger bool) error {
+	var errs []string
+	if e.PagerDuty != nil {
+		if err := sendPagerDuty(ctx, client, e.PagerDuty, breach, trigger); err != nil {
+			errs = append(errs, "pagerduty: "+err.Error())
+		}
+	}
+	if e.Opsgenie != nil {
+		if err := sendOpsgenie(ctx, client, e.Opsgenie, breach, trigger); err != nil {
+			errs = append(errs, "opsgenie: "+err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// sendPagerDuty triggers or resolves a PagerDuty alert whose dedup key is the breach key.
+func sendPagerDuty(ctx context.Context, client *http.Client, cfg *PagerDutyConfig, breach SLABreach, trigger bool) error {
+	key := os.Getenv(cfg.RoutingKeyEnv)
+	if key == "" {
+		return fmt.Errorf("environment variable %s is not set", cfg.RoutingKeyEnv)
+	}
+
+	event := map[string]any{
+		"routing_key":  key,
+		"event_action": "resolve",
+		"dedup_key":    breach.Key,
+	}
+	if trigger {
+		event["event_action"] = "trigger"
+		event["payload"] = map[string]any{
+			"summary":  breach.Summary,
+			"source":   breach.Repo,
+			"severity": "critical",
+			"class":    "release_sla",
+		}
+		event["links"] = []map[string]string{{"href": breach.URL, "text": breach.Repo}}
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, client, pagerDutyEventsURL, body, nil)
+}
+
+// sendOpsgenie creates an Opsgenie alert aliased by the breach key, or closes it.
+func sendOpsgenie(ctx context.Context, client *http.Client, cfg *OpsgenieConfig, breach SLABreach, trigger bool) error {
+	key := os.Getenv(cfg.APIKeyEnv)
+	if key == "" {
+		return fmt.Errorf("environment variable %s is not set", cfg.APIKeyEnv)
+	}
+	base := cfg.APIURL
+	if base == "" {
+		base = opsgenieAPIURL
+	}
+	header := http.Header{}
+	header.Set("Authorization", "GenieKey "+key)
+
+	if !trigger {
+		endpoint := strings.TrimSuffix(base, "/") + "/v2/alerts/" + url.PathEscape(breach.Key) + "/close?identifierType=alias"
+		return postJSON(ctx, client, endpoint, []byte(`{"source":"unreleasedcommits"}`), header)
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"message":  breach.Summary,
+		"alias":    breach.Key,
+		"source":   "unreleasedcommits",
+		"priority": "P1",
+		"details":  map[string]string{"repository": breach.URL},
+	})
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, client, strings.TrimSuffix(base, "/")+"/v2/alerts", body, header)
+}
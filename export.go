@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Export formats for -export
+const (
+	ExportParquet  = "parquet"
+	ExportBigQuery = "bigquery"
+)
+
+// exportColumns are the columns of an exported commit, one row per unreleased commit
+var exportColumns = []ParquetColumn{
+	{Name: "owner", Kind: ParquetString},
+	{Name: "repo", Kind: ParquetString},
+	{Name: "default_branch", Kind: ParquetString},
+	{Name: "baseline_type", Kind: ParquetString},
+	{Name: "baseline_tag", Kind: ParquetString},
+	{Name: "baseline_time", Kind: ParquetTimestamp},
+	{Name: "sha", Kind: ParquetString},
+	{Name: "author", Kind: ParquetString},
+	{Name: "subject", Kind: ParquetString},
+	{Name: "message", Kind: ParquetString},
+	{Name: "timestamp", Kind: ParquetTimestamp},
+	{Name: "url", Kind: ParquetString},
+	{Name: "type", Kind: ParquetString},
+	{Name: "is_merge", Kind: ParquetBool},
+	{Name: "dependency", Kind: ParquetBool},
+	{Name: "breaking", Kind: ParquetBool},
+	{Name: "security", Kind: ParquetBool},
+	{Name: "pull_request", Kind: ParquetInt64},
+	{Name: "labels", Kind: ParquetString},
+}
+
+// exportRows returns a row of exportColumns for every unreleased commit of the repositories.
+func exportRows(repos []RepositoryData) [][]any {
+	var rows [][]any
+	for _, repo := range repos {
+		for _, c := range apiCommits(repo.UnreleasedCommits) {
+			rows = append(rows, []any{
+				repo.Owner,
+				repo.Name,
+				repo.DefaultBranch,
+				repo.BaselineType,
+				repo.LatestReleaseTag,
+				repo.LatestReleaseTime,
+				c.SHA,
+				c.Author,
+				c.Subject,
+				c.Message,
+				c.Timestamp,
+				c.URL,
+				commitType(c.Message),
+				c.IsMerge,
+				c.Dependency,
+				c.Breaking,
+				c.Security,
+				int64(c.PullRequest),
+				strings.Join(c.Labels, ","),
+			})
+		}
+	}
+	return rows
+}
+
+// runExport exports the repositories in data/ in format, writing a Parquet file to path
+// or streaming into the BigQuery tables of cfg.
+func runExport(format, path string, cfg *Config) error {
+	switch format {
+	case ExportParquet:
+	case ExportBigQuery:
+		return exportBigQuery(context.Background(), cfg.BigQuery)
+	default:
+		return fmt.Errorf("invalid -export value %q. Use parquet or bigquery", format)
+	}
+	if path == "" {
+		path = "commits.parquet"
+	}
+
+	repos, err := loadAllRepositoryData("data")
+	if err != nil {
+		return err
+	}
+	rows := exportRows(repos)
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeParquet(file, exportColumns, rows); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported %d commits from %d repositories to %s\n", len(rows), len(repos), path)
+	return nil
+}
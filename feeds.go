@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// AtomFeed is the document published at feeds/<name>.atom
+type AtomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  AtomPerson  `xml:"author"`
+	Link    AtomLink    `xml:"link"`
+	Entries []AtomEntry `xml:"entry"`
+}
+
+// AtomEntry is an unreleased commit or a release in a repository's Atom feed
+type AtomEntry struct {
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  *AtomPerson `xml:"author,omitempty"`
+	Link    AtomLink    `xml:"link"`
+	Content AtomText    `xml:"content"`
+
+	// time orders entries newest first
+	time time.Time
+}
+
+// AtomPerson is the author of an Atom feed or entry
+type AtomPerson struct {
+	Name string `xml:"name"`
+}
+
+// AtomLink links an Atom feed or entry to its page on GitHub
+type AtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+// AtomText is plain text content of an Atom entry
+type AtomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// feedPath returns the path of a repository's Atom feed relative to the output directory.
+func feedPath(repoName string) string {
+	return filepath.Join("feeds", repoName+".atom")
+}
+
+// generateFeeds writes an Atom feed for each repository and removes the feeds of
+// repositories that are no longer part of the site.
+func generateFeeds(outputDir string, repos []RepositoryData, lastCrawled time.Time) error {
+	feedsDir := filepath.Join(outputDir, "feeds")
+	if err := os.MkdirAll(feedsDir, 0755); err != nil {
+		return err
+	}
+
+	current := make(map[string]bool)
+	for _, repo := range repos {
+		content, err := xml.MarshalIndent(repositoryFeed(repo, lastCrawled), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode feed for %s: %w", repo.Name, err)
+		}
+		content = append([]byte(xml.Header), content...)
+		content = append(content, '\n')
+
+		filename := repo.Name + ".atom"
+		current[filename] = true
+		if err := writeFileIfChanged(filepath.Join(feedsDir, filename), content); err != nil {
+			return fmt.Errorf("failed to write feed for %s: %w", repo.Name, err)
+		}
+	}
+
+	entries, err := os.ReadDir(feedsDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".atom") && !current[entry.Name()] {
+			if err := os.Remove(filepath.Join(feedsDir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// repositoryFeed builds the Atom feed of a repository: its unreleased commits and its
+// releases, newest first.
+func repositoryFeed(repo RepositoryData, lastCrawled time.Time) AtomFeed {
+	var entries []AtomEntry
+	for _, c := range repo.UnreleasedCommits {
+		entries = append(entries, AtomEntry{
+			ID:      c.URL,
+			Title:   commitSubject(c.Message),
+			Updated: atomTime(c.Timestamp),
+			Author:  &AtomPerson{Name: c.Author},
+			Link:    AtomLink{Href: c.URL},
+			Content: AtomText{Type: "text", Body: c.Message},
+			time:    c.Timestamp,
+		})
+	}
+
+	for _, rel := range knownReleases(repo) {
+		summary := "Released " + rel.TagName
+		if rel.PreviousTag != "" {
+			summary = fmt.Sprintf("Released %s with %d commits since %s", rel.TagName, rel.CommitCount, rel.PreviousTag)
+		}
+		entries = append(entries, AtomEntry{
+			ID:      rel.URL,
+			Title:   "Release " + rel.TagName,
+			Updated: atomTime(rel.PublishedAt),
+			Link:    AtomLink{Href: rel.URL},
+			Content: AtomText{Type: "text", Body: summary},
+			time:    rel.PublishedAt,
+		})
+	}
+
+	slices.SortStableFunc(entries, func(a, b AtomEntry) int {
+		return b.time.Compare(a.time)
+	})
+
+	// The feed is as new as its newest entry, or the crawl when it has none, so a feed
+	// whose entries have not changed is written the same on every crawl
+	updated := lastCrawled
+	if len(entries) > 0 {
+		updated = entries[0].time
+	}
+
+	return AtomFeed{
+		ID:      repo.RepositoryURL + "#unreleased",
+		Title:   repo.Owner + "/" + repo.Name + " - Unreleased Commits",
+		Updated: atomTime(updated),
+		Author:  AtomPerson{Name: repo.Owner},
+		Link:    AtomLink{Href: repo.RepositoryURL, Rel: "alternate"},
+		Entries: entries,
+	}
+}
+
+// atomTime formats t as an RFC 3339 timestamp in UTC.
+func atomTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// goProxyURL is the Go module proxy queried for published module versions
+const goProxyURL = "https://proxy.golang.org"
+
+// goProxyLagThreshold is how many commits the proxy's @latest may trail the branch tip before being flagged
+const goProxyLagThreshold = 20
+
+// pseudoVersionPattern matches Go pseudo-versions, capturing the abbreviated commit hash
+var pseudoVersionPattern = regexp.MustCompile(`\d{14}-([0-9a-f]{12})(?:\+incompatible)?$`)
+
+// GoModuleInfo describes how the Go module proxy sees a repository's module
+type GoModuleInfo struct {
+	Path          string    `json:"path"`
+	ProxyVersion  string    `json:"proxy_version,omitempty"`
+	ProxyTime     time.Time `json:"proxy_time,omitzero"`
+	Pseudo        bool      `json:"pseudo,omitempty"`
+	CommitsBehind int       `json:"commits_behind"`
+	Lagging       bool      `json:"lagging,omitempty"`
+	// MissingRelease is a release newer than @latest, e.g. because the proxy has not
+	// fetched it yet or the version was retracted
+	MissingRelease string `json:"missing_release,omitempty"`
+}
+
+// goProxyClient is used for all module proxy requests
+var goProxyClient = sync.OnceValue(registryHTTPClient)
+
+// checkGoModule reads the module path from the repository's go.mod and asks the module
+// proxy what @latest resolves to, noting when releaseTag is newer. It returns nil when
+// the repository has no go.mod.
+func checkGoModule(ctx context.Context, client *github.Client, owner, repo, branch, releaseTag string) (*GoModuleInfo, error) {
+	file, _, resp, err := client.Repositories.GetContents(ctx, owner, repo, "go.mod", &github.RepositoryContentGetOptions{Ref: branch})
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, err
+	}
+
+	modulePath := parseModulePath(content)
+	if modulePath == "" {
+		return nil, fmt.Errorf("go.mod has no module directive")
+	}
+
+	info := &GoModuleInfo{Path: modulePath}
+
+	version, versionTime, err := fetchProxyLatest(ctx, modulePath)
+	if err != nil {
+		return nil, err
+	}
+	if version == "" {
+		// The proxy has never seen this module
+		return info, nil
+	}
+
+	info.ProxyVersion = version
+	info.ProxyTime = versionTime
+
+	// Pseudo-versions are resolved to their commit, tagged versions to their tag
+	base := version
+	if m := pseudoVersionPattern.FindStringSubmatch(version); m != nil {
+		info.Pseudo = true
+		base = m[1]
+	}
+
+	comp, _, err := client.Repositories.CompareCommits(ctx, owner, repo, base, branch, &github.ListOptions{PerPage: 1})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare %s...%s: %w", base, branch, err)
+	}
+	info.CommitsBehind = comp.GetAheadBy()
+	info.Lagging = info.CommitsBehind >= goProxyLagThreshold
+
+	proxied, ok1 := parseSemver(version)
+	released, ok2 := parseSemver(releaseTag)
+	if ok1 && ok2 && released.Compare(proxied) > 0 {
+		info.MissingRelease = releaseTag
+		info.Lagging = true
+	}
+
+	return info, nil
+}
+
+// parseModulePath extracts the module path from the contents of a go.mod file.
+func parseModulePath(gomod string) string {
+	scanner := bufio.NewScanner(strings.NewReader(gomod))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if rest, ok := strings.CutPrefix(line, "module"); ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t') {
+			if i := strings.Index(rest, "//"); i >= 0 {
+				rest = rest[:i]
+			}
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
+
+// fetchProxyLatest queries the module proxy's @latest endpoint. An empty version is
+// returned when the proxy does not know the module.
+func fetchProxyLatest(ctx context.Context, modulePath string) (string, time.Time, error) {
+	url := fmt.Sprintf("%s/%s/@latest", goProxyURL, escapeModulePath(modulePath))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	resp, err := goProxyClient().Do(req)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusGone:
+		return "", time.Time{}, nil
+	default:
+		return "", time.Time{}, fmt.Errorf("module proxy returned %s for %s", resp.Status, modulePath)
+	}
+
+	var latest struct {
+		Version string
+		Time    time.Time
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&latest); err != nil {
+		return "", time.Time{}, err
+	}
+	return latest.Version, latest.Time, nil
+}
+
+// escapeModulePath applies the module proxy's case encoding, replacing each
+// uppercase letter with an exclamation mark followed by its lowercase form.
+func escapeModulePath(path string) string {
+	var b strings.Builder
+	for _, r := range path {
+		if r >= 'A' && r <= 'Z' {
+			b.WriteByte('!')
+			b.WriteRune(r + ('a' - 'A'))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"path"
+)
+
+// otherGroupName is the group of repositories that match none of the configured groups
+const otherGroupName = "Other"
+
+// GroupConfig is a named group of repositories on the index, listed by name or glob
+// pattern such as "sdk-*"
+type GroupConfig struct {
+	Name  string   `json:"name"`
+	Repos []string `json:"repos"`
+}
+
+// RepoGroup is a group of rows in the index table with its subtotals
+type RepoGroup struct {
+	Name             string
+	Repos            []SummaryData
+	CommitCount      int
+	ReposWithCommits int
+}
+
+// Subtotal summarizes the group for its header row.
+func (g RepoGroup) Subtotal() string {
+	repos := "1 repository"
+	if len(g.Repos) != 1 {
+		repos = fmt.Sprintf("%d repositories", len(g.Repos))
+	}
+	return fmt.Sprintf("%s, %d with changes, %s", repos, g.ReposWithCommits, pluralize(g.CommitCount, "unreleased commit"))
+}
+
+// validateGroups checks that every group has a unique name and valid patterns.
+func validateGroups(groups []GroupConfig) error {
+	seen := make(map[string]bool)
+	for _, g := range groups {
+		if g.Name == "" {
+			return fmt.Errorf("every group needs a name")
+		}
+		if seen[g.Name] || g.Name == otherGroupName {
+			return fmt.Errorf("group name %q is used more than once", g.Name)
+		}
+		seen[g.Name] = true
+		if len(g.Repos) == 0 {
+			return fmt.Errorf("%s: repos is required", g.Name)
+		}
+		for _, pattern := range g.Repos {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("%s: invalid pattern %q", g.Name, pattern)
+			}
+		}
+	}
+	return nil
+}
+
+// validatePinned checks that every pinned repository is a valid name or pattern.
+func validatePinned(pinned []string) error {
+	for _, pattern := range pinned {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q", pattern)
+		}
+	}
+	return nil
+}
+
+// groupRepos sorts the index rows into the groups, in the configured order. A repository
+// belongs to the first group that lists it; the rest are collected in a trailing "Other"
+// group. Groups without repositories are left out.
+func groupRepos(summaries []SummaryData, groups []GroupConfig, owner string) []RepoGroup {
+	if len(groups) == 0 {
+		return nil
+	}
+
+	result := make([]RepoGroup, len(groups)+1)
+	for i, g := range groups {
+		result[i].Name = g.Name
+	}
+	result[len(groups)].Name = otherGroupName
+
+	for _, s := range summaries {
+		i := len(groups)
+		for j, g := range groups {
+			if matchesAny(g.Repos, owner, s.Name) {
+				i = j
+				break
+			}
+		}
+		group := &result[i]
+		group.Repos = append(group.Repos, s)
+		group.CommitCount += s.CommitCount
+		if s.CommitCount > 0 {
+			group.ReposWithCommits++
+		}
+	}
+
+	nonEmpty := result[:0]
+	for _, g := range result {
+		if len(g.Repos) > 0 {
+			nonEmpty = append(nonEmpty, g)
+		}
+	}
+	return nonEmpty
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGroupRepos(t *testing.T) {
+	summaries := []SummaryData{
+		{Name: "Api-SDK", CommitCount: 3},
+		{Name: "web-app", CommitCount: 0},
+		{Name: "go-sdk", CommitCount: 2},
+		{Name: "docs"},
+		{Name: "Infra"},
+	}
+	groups := []GroupConfig{
+		{Name: "SDKs", Repos: []string{"*-SDK"}},
+		{Name: "Apps", Repos: []string{"Web-*", "ACME/infra"}},
+		{Name: "Empty", Repos: []string{"nothing-*"}},
+	}
+
+	got := groupRepos(summaries, groups, "acme")
+
+	type group struct {
+		name             string
+		repos            []string
+		commits, changed int
+	}
+	want := []group{
+		{"SDKs", []string{"Api-SDK", "go-sdk"}, 5, 2},
+		{"Apps", []string{"web-app", "Infra"}, 0, 0},
+		{"Other", []string{"docs"}, 0, 0},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("groupRepos returned %d groups, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		var names []string
+		for _, s := range got[i].Repos {
+			names = append(names, s.Name)
+		}
+		if got[i].Name != w.name || !slices.Equal(names, w.repos) || got[i].CommitCount != w.commits || got[i].ReposWithCommits != w.changed {
+			t.Errorf("group %d = %s %v (%d commits, %d changed), want %s %v (%d commits, %d changed)",
+				i, got[i].Name, names, got[i].CommitCount, got[i].ReposWithCommits, w.name, w.repos, w.commits, w.changed)
+		}
+	}
+
+	if got := groupRepos(summaries, nil, "acme"); got != nil {
+		t.Errorf("groupRepos without groups = %+v, want nil", got)
+	}
+}
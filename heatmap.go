@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+)
+
+// Color scales for heat-map metrics without absolute thresholds
+const (
+	ColorScaleLinear = "linear"
+	ColorScaleLog    = "log"
+)
+
+// ColorThreshold gives a heat-map metric fixed color bands: green up to Green, yellow
+// up to Yellow, and red above it
+type ColorThreshold struct {
+	Green  int `json:"green"`
+	Yellow int `json:"yellow"`
+}
+
+// colorMetrics are the index columns colored as a heat map
+var colorMetrics = []string{"commits", "days_behind", "days_since_release"}
+
+// validateHeatMap checks that every heat_map setting names a heat-map metric.
+func validateHeatMap(heatMap map[string]bool) error {
+	for name := range heatMap {
+		if !slices.Contains(colorMetrics, name) {
+			return fmt.Errorf("unknown metric %q", name)
+		}
+	}
+	return nil
+}
+
+// validateColorThresholds checks that every threshold names a heat-map metric and that
+// its bands are in order.
+func validateColorThresholds(thresholds map[string]ColorThreshold) error {
+	names := make([]string, 0, len(thresholds))
+	for name := range thresholds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !slices.Contains(colorMetrics, name) {
+			return fmt.Errorf("unknown metric %q", name)
+		}
+		t := thresholds[name]
+		if t.Green < 0 || t.Yellow < t.Green {
+			return fmt.Errorf("%s: need 0 <= green <= yellow", name)
+		}
+	}
+	return nil
+}
+
+// metricColors returns the background and text color of a heat-map cell. With an
+// absolute threshold for metric in cfg's color_thresholds the value falls into the
+// green, yellow or red band; otherwise it is placed between the smallest and largest
+// value across repositories, on a logarithmic scale when the color_scale is
+// ColorScaleLog so that a single outlier does not turn every other repository green.
+// Both colors are empty when the metric's heat map is turned off.
+func metricColors(cfg *Config, metric string, value, min, max int) (string, string) {
+	if enabled, ok := cfg.HeatMap[metric]; ok && !enabled {
+		return "", ""
+	}
+	normalized := 0.0
+	if t, ok := cfg.ColorThresholds[metric]; ok {
+		switch {
+		case value <= t.Green:
+			normalized = 0
+		case value <= t.Yellow:
+			normalized = 0.5
+		default:
+			normalized = 1
+		}
+	} else if max > min {
+		if cfg.ColorScale == ColorScaleLog {
+			normalized = math.Log1p(float64(value-min)) / math.Log1p(float64(max-min))
+		} else {
+			normalized = float64(value-min) / float64(max-min)
+		}
+	}
+	return getColorForValue(normalized), getTextColor(normalized)
+}
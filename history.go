@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// ReleaseInfo represents a single published release in a repository's history
+type ReleaseInfo struct {
+	TagName         string       `json:"tag_name"`
+	Name            string       `json:"name,omitempty"`
+	PublishedAt     time.Time    `json:"published_at"`
+	Prerelease      bool         `json:"prerelease,omitempty"`
+	URL             string       `json:"url"`
+	PreviousTag     string       `json:"previous_tag,omitempty"`
+	CommitCount     int          `json:"commit_count"`
+	FirstCommitTime time.Time    `json:"first_commit_time,omitzero"`
+	Commits         []CommitInfo `json:"commits,omitempty"`
+}
+
+// fetchReleaseHistory lists every published release for a repository, oldest first,
+// along with the commits between each release and the one before it.
+func fetchReleaseHistory(ctx context.Context, client *github.Client, owner, repo string) ([]ReleaseInfo, error) {
+	releases, err := listAllReleases(ctx, client, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var history []ReleaseInfo
+	for _, rel := range releases {
+		if rel.GetDraft() || rel.GetTagName() == "" {
+			continue
+		}
+		history = append(history, ReleaseInfo{
+			TagName:     rel.GetTagName(),
+			Name:        rel.GetName(),
+			PublishedAt: rel.GetPublishedAt().Time,
+			Prerelease:  rel.GetPrerelease(),
+			URL:         rel.GetHTMLURL(),
+		})
+	}
+
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].PublishedAt.Before(history[j].PublishedAt)
+	})
+
+	for i := 1; i < len(history); i++ {
+		prev := history[i-1].TagName
+		commits, err := compareAllCommits(ctx, client, owner, repo, prev, history[i].TagName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compare %s...%s: %w", prev, history[i].TagName, err)
+		}
+		history[i].PreviousTag = prev
+		history[i].CommitCount = len(commits)
+		history[i].Commits = toCommitInfos(commits)
+		// Compare lists commits oldest first, so the first one is the earliest change in the release
+		if len(commits) > 0 {
+			history[i].FirstCommitTime = commits[0].GetCommit().GetAuthor().GetDate().Time
+		}
+	}
+
+	return history, nil
+}
+
+// typicalReleaseDays returns the average number of days, rounded up, between the first
+// commit of a release and the release being published. It returns 0 when the history
+// does not contain enough information to compute it.
+func typicalReleaseDays(history []ReleaseInfo) int {
+	var total time.Duration
+	count := 0
+	for _, rel := range history {
+		if rel.FirstCommitTime.IsZero() || rel.PublishedAt.Before(rel.FirstCommitTime) {
+			continue
+		}
+		total += rel.PublishedAt.Sub(rel.FirstCommitTime)
+		count++
+	}
+	if count == 0 {
+		return 0
+	}
+
+	days := int(math.Ceil((total / time.Duration(count)).Hours() / 24))
+	if days < 1 {
+		days = 1
+	}
+	return days
+}
+
+// knownReleases returns the repository's releases: its release history when it was
+// crawled with -history, otherwise just the latest release. A release branch baseline is
+// not a release.
+func knownReleases(repo RepositoryData) []ReleaseInfo {
+	if len(repo.ReleaseHistory) > 0 || repo.NeverReleased || repo.LatestReleaseTag == "" || repo.BaselineType == BaselineBranch {
+		return repo.ReleaseHistory
+	}
+	return []ReleaseInfo{{
+		TagName:     repo.LatestReleaseTag,
+		PublishedAt: repo.LatestReleaseTime,
+		URL:         repo.RepositoryURL + "/releases/tag/" + repo.LatestReleaseTag,
+	}}
+}
+
+// hasReleaseHistory reports whether any repository was crawled with release history.
+func hasReleaseHistory(repos []RepositoryData) bool {
+	for _, repo := range repos {
+		if len(repo.ReleaseHistory) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func listAllReleases(ctx context.Context, client *github.Client, owner, repo string) ([]*github.RepositoryRelease, error) {
+	var all []*github.RepositoryRelease
+	opt := &github.ListOptions{PerPage: 100}
+
+	for {
+		releases, resp, err := client.Repositories.ListReleases(ctx, owner, repo, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, releases...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return all, nil
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// homebrewFormulaDirs are where a tap keeps its formulae, in the order Homebrew searches them
+var homebrewFormulaDirs = []string{"Formula", "HomebrewFormula", ""}
+
+// formulaVersionPattern matches an explicit version stanza, as goreleaser writes it
+var formulaVersionPattern = regexp.MustCompile(`(?m)^\s*version\s+"([^"]+)"`)
+
+// formulaTagPattern matches the tag of a formula built from a git checkout
+var formulaTagPattern = regexp.MustCompile(`tag:\s*"([^"]+)"`)
+
+// formulaURLPattern matches the version in a GitHub archive or release asset URL
+var formulaURLPattern = regexp.MustCompile(`/(?:archive/(?:refs/tags/)?|releases/download/)(v?\d[\w.+-]*?)(?:\.tar\.gz|\.tgz|\.zip|/)`)
+
+// HomebrewConfig names the Homebrew tap whose formulae are compared with the releases
+type HomebrewConfig struct {
+	Tap string `json:"tap"`
+}
+
+// HomebrewInfo is the version of a repository's formula in the Homebrew tap and how many
+// releases it trails the latest one by
+type HomebrewInfo struct {
+	Tap            string    `json:"tap"`
+	Formula        string    `json:"formula"`
+	Version        string    `json:"version"`
+	Tag            string    `json:"tag,omitempty"`
+	ReleasesBehind int       `json:"releases_behind"`
+	UpdatedAt      time.Time `json:"updated_at,omitzero"`
+}
+
+// FormulaURL returns the formula's file in the tap repository.
+func (h HomebrewInfo) FormulaURL() string {
+	owner, repo := homebrewTapRepo(h.Tap)
+	return fmt.Sprintf("https://github.com/%s/%s/blob/HEAD/%s", owner, repo, h.Formula)
+}
+
+// validateHomebrew checks that the tap is named as owner/name.
+func validateHomebrew(h *HomebrewConfig) error {
+	if h == nil {
+		return nil
+	}
+	owner, name, ok := strings.Cut(h.Tap, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return fmt.Errorf("tap must be owner/name, got %q", h.Tap)
+	}
+	return nil
+}
+
+// homebrewTapRepo returns the GitHub repository of a tap, which by Homebrew's convention
+// is named homebrew-<name> for the tap owner/name.
+func homebrewTapRepo(tap string) (owner, repo string) {
+	owner, repo, _ = strings.Cut(tap, "/")
+	if !strings.HasPrefix(repo, "homebrew-") {
+		repo = "homebrew-" + repo
+	}
+	return owner, repo
+}
+
+// homebrewTap is the formula files of a tap, listed once per crawl
+type homebrewTap struct {
+	name     string
+	owner    string
+	repo     string
+	formulae map[string]string // formula name to file path
+}
+
+// loadHomebrewTap lists the formulae of the configured tap. It returns nil when no tap is
+// configured.
+func loadHomebrewTap(ctx context.Context, client *github.Client, cfg *HomebrewConfig) (*homebrewTap, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	owner, repo := homebrewTapRepo(cfg.Tap)
+	tap := &homebrewTap{name: cfg.Tap, owner: owner, repo: repo, formulae: make(map[string]string)}
+
+	for _, dir := range homebrewFormulaDirs {
+		_, entries, resp, err := client.Repositories.GetContents(ctx, owner, repo, dir, nil)
+		if err != nil {
+			if resp != nil && resp.StatusCode == http.StatusNotFound {
+				continue
+			}
+			return nil, fmt.Errorf("failed to list %s/%s: %w", owner, repo, err)
+		}
+		for _, entry := range entries {
+			name, ok := strings.CutSuffix(entry.GetName(), ".rb")
+			if !ok || entry.GetType() != "file" {
+				continue
+			}
+			// The first directory in Homebrew's search order wins
+			if _, seen := tap.formulae[name]; !seen {
+				tap.formulae[name] = entry.GetPath()
+			}
+		}
+	}
+	return tap, nil
+}
+
+// check reads the version of formula from the tap, when it was last changed, and counts
+// the stable releases of the repository published after it. It returns nil when the tap
+// has no such formula.
+func (t *homebrewTap) check(ctx context.Context, client *github.Client, owner, repo, formula string) (*HomebrewInfo, error) {
+	if t == nil {
+		return nil, nil
+	}
+	file, ok := t.formulae[strings.ToLower(formula)]
+	if !ok {
+		return nil, nil
+	}
+
+	content, _, _, err := client.Repositories.GetContents(ctx, t.owner, t.repo, file, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	text, err := content.GetContent()
+	if err != nil {
+		return nil, err
+	}
+	version := parseFormulaVersion(text)
+	if version == "" {
+		return nil, fmt.Errorf("no version found in %s", path.Base(file))
+	}
+
+	releases, err := listAllReleases(ctx, client, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	var stable []*github.RepositoryRelease
+	for _, rel := range releases {
+		if !rel.GetDraft() && !rel.GetPrerelease() {
+			stable = append(stable, rel)
+		}
+	}
+	sort.Slice(stable, func(i, j int) bool {
+		return stable[i].GetPublishedAt().After(stable[j].GetPublishedAt().Time)
+	})
+
+	commits, _, err := client.Repositories.ListCommits(ctx, t.owner, t.repo, &github.CommitsListOptions{
+		Path:        file,
+		ListOptions: github.ListOptions{PerPage: 1},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits of %s: %w", file, err)
+	}
+
+	info := &HomebrewInfo{Tap: t.name, Formula: file, Version: version}
+	if len(commits) > 0 {
+		info.UpdatedAt = commits[0].GetCommit().GetCommitter().GetDate().Time
+	}
+	for i, rel := range stable {
+		if strings.TrimPrefix(rel.GetTagName(), "v") == strings.TrimPrefix(version, "v") {
+			info.Tag = rel.GetTagName()
+			info.ReleasesBehind = i
+			break
+		}
+	}
+	return info, nil
+}
+
+// parseFormulaVersion returns the version a formula installs: its version stanza, or else
+// the tag or archive URL it downloads.
+func parseFormulaVersion(formula string) string {
+	for _, pattern := range []*regexp.Regexp{formulaVersionPattern, formulaTagPattern, formulaURLPattern} {
+		if m := pattern.FindStringSubmatch(formula); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// brewReleasesBehind returns how many releases the formula trails by, or 0 without one.
+func brewReleasesBehind(h *HomebrewInfo) int {
+	if h == nil {
+		return 0
+	}
+	return h.ReleasesBehind
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// httpTuning configures the HTTP clients of the crawl, set from the config's http section
+var httpTuning HTTPConfig
+
+// HTTPConfig tunes the HTTP clients used for the GitHub API, the Go module proxy and the
+// container and package registries. Zero values keep Go's defaults, which suit most
+// crawls of github.com.
+type HTTPConfig struct {
+	TimeoutSeconds               int `json:"timeout_seconds,omitempty"`
+	DialTimeoutSeconds           int `json:"dial_timeout_seconds,omitempty"`
+	TLSHandshakeTimeoutSeconds   int `json:"tls_handshake_timeout_seconds,omitempty"`
+	ResponseHeaderTimeoutSeconds int `json:"response_header_timeout_seconds,omitempty"`
+	IdleConnTimeoutSeconds       int `json:"idle_conn_timeout_seconds,omitempty"`
+	MaxIdleConns                 int `json:"max_idle_conns,omitempty"`
+	MaxIdleConnsPerHost          int `json:"max_idle_conns_per_host,omitempty"`
+	MaxConnsPerHost              int `json:"max_conns_per_host,omitempty"`
+	MinRequestIntervalMillis     int `json:"min_request_interval_ms,omitempty"`
+}
+
+// validateHTTP checks that no setting is negative.
+func validateHTTP(h HTTPConfig) error {
+	for name, v := range map[string]int{
+		"timeout_seconds":                 h.TimeoutSeconds,
+		"dial_timeout_seconds":            h.DialTimeoutSeconds,
+		"tls_handshake_timeout_seconds":   h.TLSHandshakeTimeoutSeconds,
+		"response_header_timeout_seconds": h.ResponseHeaderTimeoutSeconds,
+		"idle_conn_timeout_seconds":       h.IdleConnTimeoutSeconds,
+		"max_idle_conns":                  h.MaxIdleConns,
+		"max_idle_conns_per_host":         h.MaxIdleConnsPerHost,
+		"max_conns_per_host":              h.MaxConnsPerHost,
+		"min_request_interval_ms":         h.MinRequestIntervalMillis,
+	} {
+		if v < 0 {
+			return fmt.Errorf("%s cannot be negative", name)
+		}
+	}
+	return nil
+}
+
+func seconds(n int) time.Duration {
+	return time.Duration(n) * time.Second
+}
+
+// transport returns an http.Transport with the configured timeouts and connection
+// limits, paced when a minimum request interval is set.
+func (h HTTPConfig) transport() http.RoundTripper {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if h.DialTimeoutSeconds > 0 {
+		dialer := &net.Dialer{Timeout: seconds(h.DialTimeoutSeconds), KeepAlive: 30 * time.Second}
+		t.DialContext = dialer.DialContext
+	}
+	if h.TLSHandshakeTimeoutSeconds > 0 {
+		t.TLSHandshakeTimeout = seconds(h.TLSHandshakeTimeoutSeconds)
+	}
+	if h.ResponseHeaderTimeoutSeconds > 0 {
+		t.ResponseHeaderTimeout = seconds(h.ResponseHeaderTimeoutSeconds)
+	}
+	if h.IdleConnTimeoutSeconds > 0 {
+		t.IdleConnTimeout = seconds(h.IdleConnTimeoutSeconds)
+	}
+	if h.MaxIdleConns > 0 {
+		t.MaxIdleConns = h.MaxIdleConns
+	}
+	if h.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = h.MaxIdleConnsPerHost
+	}
+	if h.MaxConnsPerHost > 0 {
+		t.MaxConnsPerHost = h.MaxConnsPerHost
+	}
+
+	if h.MinRequestIntervalMillis > 0 {
+		return &pacedTransport{
+			base:     t,
+			interval: time.Duration(h.MinRequestIntervalMillis) * time.Millisecond,
+			next:     make(map[string]time.Time),
+		}
+	}
+	return t
+}
+
+// githubHTTPClient returns an HTTP client authenticated with token and tuned by httpTuning.
+func githubHTTPClient(ctx context.Context, token string) *http.Client {
+	base := &http.Client{Transport: httpTuning.transport()}
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
+	client := oauth2.NewClient(context.WithValue(ctx, oauth2.HTTPClient, base), ts)
+	client.Timeout = seconds(httpTuning.TimeoutSeconds)
+	return client
+}
+
+// registryTimeout limits each request to the Go module proxy and the container and
+// package registries when the http section sets no timeout
+const registryTimeout = 30 * time.Second
+
+// registryHTTPClient returns an HTTP client tuned by httpTuning for requests outside the
+// GitHub API. It is built on first use, after the config has been loaded.
+func registryHTTPClient() *http.Client {
+	timeout := registryTimeout
+	if httpTuning.TimeoutSeconds > 0 {
+		timeout = seconds(httpTuning.TimeoutSeconds)
+	}
+	return &http.Client{Transport: httpTuning.transport(), Timeout: timeout}
+}
+
+// pacedTransport is an http.RoundTripper that spaces the requests to each host at least
+// interval apart, to go easy on small GitHub Enterprise instances and stay clear of
+// github.com's secondary rate limits
+type pacedTransport struct {
+	base     http.RoundTripper
+	interval time.Duration
+
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+func (p *pacedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Reserve the next free slot for the host, so concurrent requests queue up in turn
+	p.mu.Lock()
+	now := time.Now()
+	slot := p.next[req.URL.Host]
+	if slot.Before(now) {
+		slot = now
+	}
+	p.next[req.URL.Host] = slot.Add(p.interval)
+	p.mu.Unlock()
+
+	if wait := slot.Sub(now); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+	}
+	return p.base.RoundTrip(req)
+}
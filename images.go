@@ -0,0 +1,242 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultImageRegistry is where a repository's image is looked for unless its image
+// setting names another
+const defaultImageRegistry = "ghcr.io"
+
+// imageClient is used for all container registry requests
+var imageClient = sync.OnceValue(registryHTTPClient)
+
+// ImageInfo is whether the container image published from a repository has a tag for
+// the latest release and for the head of the default branch
+type ImageInfo struct {
+	Image          string    `json:"image"`
+	ReleaseTag     string    `json:"release_tag,omitempty"`
+	ReleasePushed  bool      `json:"release_pushed"`
+	ReleaseCreated time.Time `json:"release_created,omitzero"`
+	HeadTag        string    `json:"head_tag,omitempty"`
+	HeadPushed     bool      `json:"head_pushed"`
+}
+
+// imageReference splits an image such as ghcr.io/owner/name into its registry host and
+// repository path, defaulting to ghcr.io/<owner>/<repo>.
+func imageReference(image, owner, repo string) (host, path string) {
+	if image == "" {
+		return defaultImageRegistry, strings.ToLower(owner + "/" + repo)
+	}
+	host, path, ok := strings.Cut(image, "/")
+	if !ok || !strings.ContainsAny(host, ".:") {
+		// No registry host, e.g. "owner/name"
+		return defaultImageRegistry, strings.ToLower(image)
+	}
+	return host, strings.ToLower(path)
+}
+
+// checkImage looks for tags of the image for the release tag, with or without its "v"
+// prefix, noting when the release image was built, and for headSHA in the sha-<7 characters> form of docker/metadata-action. It
+// returns nil when the registry has no such image.
+func checkImage(ctx context.Context, image, owner, repo, releaseTag, headSHA string) (*ImageInfo, error) {
+	host, path := imageReference(image, owner, repo)
+	reg := &imageRegistry{host: host, path: path}
+
+	exists, err := reg.exists(ctx)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	info := &ImageInfo{Image: host + "/" + path}
+	for _, tag := range []string{releaseTag, strings.TrimPrefix(releaseTag, "v")} {
+		pushed, err := reg.hasTag(ctx, tag)
+		if err != nil {
+			return nil, err
+		}
+		if pushed {
+			info.ReleaseTag, info.ReleasePushed = tag, true
+			if info.ReleaseCreated, err = reg.created(ctx, tag); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	if headSHA != "" {
+		info.HeadTag = "sha-" + headSHA[:min(7, len(headSHA))]
+		info.HeadPushed, err = reg.hasTag(ctx, info.HeadTag)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return info, nil
+}
+
+// imageRegistry reads an image repository through the OCI distribution API, with the
+// anonymous bearer token the registry hands out for public images
+type imageRegistry struct {
+	host  string
+	path  string
+	token string
+}
+
+// exists reports whether the registry knows the image.
+func (r *imageRegistry) exists(ctx context.Context) (bool, error) {
+	status, err := r.request(ctx, http.MethodGet, "/tags/list?n=1")
+	if err != nil {
+		return false, err
+	}
+	return status == http.StatusOK, nil
+}
+
+// hasTag reports whether the image has a manifest for tag.
+func (r *imageRegistry) hasTag(ctx context.Context, tag string) (bool, error) {
+	if tag == "" {
+		return false, nil
+	}
+	status, err := r.request(ctx, http.MethodHead, "/manifests/"+url.PathEscape(tag))
+	if err != nil {
+		return false, err
+	}
+	return status == http.StatusOK, nil
+}
+
+// created returns when the image for tag was built, as recorded in its config. For a
+// multi-platform image the first platform's config is used.
+func (r *imageRegistry) created(ctx context.Context, tag string) (time.Time, error) {
+	var manifest struct {
+		Manifests []struct {
+			Digest string `json:"digest"`
+		} `json:"manifests"`
+		Config struct {
+			Digest string `json:"digest"`
+		} `json:"config"`
+	}
+	if err := r.getJSON(ctx, "/manifests/"+url.PathEscape(tag), &manifest); err != nil {
+		return time.Time{}, err
+	}
+	if len(manifest.Manifests) > 0 {
+		if err := r.getJSON(ctx, "/manifests/"+manifest.Manifests[0].Digest, &manifest); err != nil {
+			return time.Time{}, err
+		}
+	}
+	if manifest.Config.Digest == "" {
+		return time.Time{}, fmt.Errorf("manifest of %s has no config", tag)
+	}
+
+	var config struct {
+		Created time.Time `json:"created"`
+	}
+	if err := r.getJSON(ctx, "/blobs/"+manifest.Config.Digest, &config); err != nil {
+		return time.Time{}, err
+	}
+	return config.Created, nil
+}
+
+// request sends a request for the image and returns its status. Not found and
+// unauthorized (how registries answer for private or unknown images) are returned as
+// statuses rather than errors.
+func (r *imageRegistry) request(ctx context.Context, method, suffix string) (int, error) {
+	resp, err := r.do(ctx, method, suffix)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNotFound, http.StatusUnauthorized, http.StatusForbidden:
+		return resp.StatusCode, nil
+	}
+	return 0, fmt.Errorf("%s returned %s", r.host, resp.Status)
+}
+
+// getJSON decodes the response to a GET request for the image into v.
+func (r *imageRegistry) getJSON(ctx context.Context, suffix string, v any) error {
+	resp, err := r.do(ctx, http.MethodGet, suffix)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", r.host, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// do sends a request for the image, fetching a token when the registry asks for one.
+func (r *imageRegistry) do(ctx context.Context, method, suffix string) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("https://%s/v2/%s%s", r.host, r.path, suffix), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/vnd.oci.image.index.v1+json, application/vnd.oci.image.manifest.v1+json, application/vnd.docker.distribution.manifest.list.v2+json, application/vnd.docker.distribution.manifest.v2+json")
+		if r.token != "" {
+			req.Header.Set("Authorization", "Bearer "+r.token)
+		}
+		resp, err := imageClient().Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusUnauthorized || attempt > 0 {
+			return resp, nil
+		}
+		resp.Body.Close()
+		if err := r.authenticate(ctx, resp.Header.Get("WWW-Authenticate")); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// authenticate fetches an anonymous pull token from the realm of a Bearer challenge.
+func (r *imageRegistry) authenticate(ctx context.Context, challenge string) error {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return fmt.Errorf("%s asks for unsupported %s authentication", r.host, scheme)
+	}
+	values := url.Values{}
+	realm := ""
+	for _, param := range strings.Split(params, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		value = strings.Trim(value, `"`)
+		if key == "realm" {
+			realm = value
+		} else {
+			values.Set(key, value)
+		}
+	}
+	if realm == "" {
+		return fmt.Errorf("%s sent an authentication challenge without a realm", r.host)
+	}
+	values.Set("scope", "repository:"+r.path+":pull")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm+"?"+values.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := imageClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("token request to %s returned %s", realm, resp.Status)
+	}
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return err
+	}
+	r.token = cmp.Or(body.Token, body.AccessToken)
+	return nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// LabelCount is how many unreleased commits came from pull requests with a label, or
+// from an author
+type LabelCount struct {
+	Name  string
+	Count int
+}
+
+// linkPullRequests records the pull request each commit was merged through, with that
+// pull request's labels. It returns how many commits were linked.
+func linkPullRequests(ctx context.Context, client *github.Client, owner, repo string, commits []CommitInfo) (int, error) {
+	linked := 0
+	for i := range commits {
+		pulls, _, err := client.PullRequests.ListPullRequestsWithCommit(ctx, owner, repo, commits[i].SHA, &github.ListOptions{PerPage: 10})
+		if err != nil {
+			return linked, err
+		}
+		if len(pulls) == 0 {
+			continue
+		}
+
+		// Prefer the pull request that merged the commit over others that merely contain it
+		pr := pulls[0]
+		for _, p := range pulls {
+			if p.MergedAt != nil {
+				pr = p
+				break
+			}
+		}
+
+		commits[i].PullRequest = pr.GetNumber()
+		commits[i].Labels = nil
+		for _, label := range pr.Labels {
+			commits[i].Labels = append(commits[i].Labels, label.GetName())
+		}
+		linked++
+	}
+	return linked, nil
+}
+
+// labelCounts tallies the pull request labels of the commits, most common first.
+func labelCounts(commits []CommitInfo) []LabelCount {
+	counts := make(map[string]int)
+	for _, c := range commits {
+		for _, label := range c.Labels {
+			counts[label]++
+		}
+	}
+	return sortedCounts(counts)
+}
+
+// authorCounts tallies the authors of the commits, most prolific first.
+func authorCounts(commits []CommitInfo) []LabelCount {
+	counts := make(map[string]int)
+	for _, c := range commits {
+		counts[c.Author]++
+	}
+	return sortedCounts(counts)
+}
+
+// sortedCounts orders counts by count, then by name.
+func sortedCounts(counts map[string]int) []LabelCount {
+	result := make([]LabelCount, 0, len(counts))
+	for name, count := range counts {
+		result = append(result, LabelCount{Name: name, Count: count})
+	}
+	slices.SortFunc(result, func(a, b LabelCount) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return result
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// OwnerStanding is one owner's aggregate release debt on the leaderboard
+type OwnerStanding struct {
+	Rank                   int
+	Owner                  string
+	Repos                  int
+	ReposWithUnreleased    int
+	UnreleasedCommits      int
+	MedianDaysSinceRelease int
+	NeverReleased          int
+	Current                bool
+}
+
+// loadCompareRepos loads the repositories of every -compare directory.
+func loadCompareRepos(dirs []string) ([]RepositoryData, error) {
+	var all []RepositoryData
+	for _, dir := range dirs {
+		repos, err := loadAllRepositoryData(dir)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", dir, err)
+		}
+		all = append(all, repos...)
+	}
+	return all, nil
+}
+
+// ownerStandings ranks the owners of repos by total unreleased commits, then by median
+// days since release, most release debt first. Current marks the site's own owner.
+func ownerStandings(repos []RepositoryData, current string) []OwnerStanding {
+	byOwner := make(map[string]*OwnerStanding)
+	days := make(map[string][]int)
+	for _, repo := range repos {
+		s := byOwner[repo.Owner]
+		if s == nil {
+			s = &OwnerStanding{Owner: repo.Owner, Current: strings.EqualFold(repo.Owner, current)}
+			byOwner[repo.Owner] = s
+		}
+		s.Repos++
+		if repo.NeverReleased {
+			s.NeverReleased++
+			continue
+		}
+		s.UnreleasedCommits += len(repo.UnreleasedCommits)
+		if len(repo.UnreleasedCommits) > 0 {
+			s.ReposWithUnreleased++
+		}
+		daysSinceRelease, _ := repoAges(repo)
+		days[repo.Owner] = append(days[repo.Owner], daysSinceRelease)
+	}
+
+	standings := make([]OwnerStanding, 0, len(byOwner))
+	for owner, s := range byOwner {
+		if d := days[owner]; len(d) > 0 {
+			slices.Sort(d)
+			s.MedianDaysSinceRelease = d[len(d)/2]
+			if len(d)%2 == 0 {
+				s.MedianDaysSinceRelease = (d[len(d)/2-1] + d[len(d)/2]) / 2
+			}
+		}
+		standings = append(standings, *s)
+	}
+	slices.SortFunc(standings, func(a, b OwnerStanding) int {
+		if c := cmp.Compare(b.UnreleasedCommits, a.UnreleasedCommits); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(b.MedianDaysSinceRelease, a.MedianDaysSinceRelease); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Owner, b.Owner)
+	})
+	for i := range standings {
+		standings[i].Rank = i + 1
+	}
+	return standings
+}
+
+// generateLeaderboardPage writes owners.html, comparing the site's owner with the
+// owners crawled into the -compare directories.
+func generateLeaderboardPage(outputDir string, repos []RepositoryData, lastUpdated string, opts GenerateOptions) error {
+	others, err := loadCompareRepos(opts.CompareDirs)
+	if err != nil {
+		return err
+	}
+
+	owner := ""
+	if len(repos) > 0 {
+		owner = repos[0].Owner
+	}
+	for _, repo := range others {
+		if strings.EqualFold(repo.Owner, owner) {
+			fmt.Printf("Warning: -compare includes %s, the site's own owner; its repositories are counted twice\n", owner)
+			break
+		}
+	}
+
+	tmpl, err := loadTemplates(opts)
+	if err != nil {
+		return fmt.Errorf("failed to parse leaderboard template: %w", err)
+	}
+
+	data := struct {
+		Owner       string
+		Standings   []OwnerStanding
+		LastUpdated string
+	}{
+		Owner:       owner,
+		Standings:   ownerStandings(append(slices.Clone(repos), others...), owner),
+		LastUpdated: lastUpdated,
+	}
+
+	return executePage(tmpl, "owners.html", filepath.Join(outputDir, "owners.html"), data)
+}
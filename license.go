@@ -0,0 +1,47 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// licenseID returns the SPDX identifier of the repository's license, such as "MIT".
+// GitHub reports a license file it does not recognize as "NOASSERTION", which is
+// recorded as "Other"; a repository without a license file gets an empty string.
+func licenseID(license *github.License) string {
+	if license == nil {
+		return ""
+	}
+	switch id := license.GetSPDXID(); id {
+	case "", "NOASSERTION":
+		return "Other"
+	default:
+		return id
+	}
+}
+
+// collectLicenses returns the distinct licenses of the repositories, sorted.
+func collectLicenses(summaries []SummaryData) []string {
+	seen := make(map[string]bool)
+	var licenses []string
+	for _, s := range summaries {
+		if s.License != "" && !seen[s.License] {
+			seen[s.License] = true
+			licenses = append(licenses, s.License)
+		}
+	}
+	sort.Strings(licenses)
+	return licenses
+}
+
+// countUnlicensed returns how many of the repositories have no license.
+func countUnlicensed(repos []RepositoryData) int {
+	count := 0
+	for _, repo := range repos {
+		if repo.License == "" {
+			count++
+		}
+	}
+	return count
+}
@@ -1,9 +1,12 @@
 package main
 
 import (
+	"bytes"
+	"cmp"
 	"context"
 	"embed"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
@@ -14,46 +17,130 @@ import (
 	"time"
 
 	"github.com/google/go-github/v62/github"
-	"golang.org/x/oauth2"
 )
 
 // templateFS embeds all HTML templates from the templates directory.
 //
 //go:embed templates/*.html
 //go:embed templates/style.css
+//go:embed templates/script.js
 var templateFS embed.FS
 
+// deprecatedTopic is the repository topic used to mark a repository as deprecated
+const deprecatedTopic = "deprecated"
+
 // CommitInfo represents a single commit with all relevant details
 type CommitInfo struct {
-	SHA       string    `json:"sha"`
-	Author    string    `json:"author"`
-	Message   string    `json:"message"`
-	Timestamp time.Time `json:"timestamp"`
-	URL       string    `json:"url"`
-	IsMerge   bool      `json:"is_merge"`
+	SHA          string    `json:"sha"`
+	Author       string    `json:"author"`
+	Message      string    `json:"message"`
+	Timestamp    time.Time `json:"timestamp"`
+	URL          string    `json:"url"`
+	IsMerge      bool      `json:"is_merge"`
+	Parents      []string  `json:"parents,omitempty"`
+	CherryPicked bool      `json:"cherry_picked,omitempty"`
+	PullRequest  int       `json:"pull_request,omitempty"`
+	Labels       []string  `json:"labels,omitempty"`
+	New          bool      `json:"new,omitempty"`
+}
+
+// AssetInfo represents a single asset attached to a release
+type AssetInfo struct {
+	Name          string `json:"name"`
+	Size          int    `json:"size"`
+	DownloadCount int    `json:"download_count"`
+	URL           string `json:"url"`
 }
 
 // RepositoryData represents all data for a repository
 type RepositoryData struct {
-	Owner             string       `json:"owner"`
-	Name              string       `json:"name"`
-	DefaultBranch     string       `json:"default_branch"`
-	LatestReleaseTag  string       `json:"latest_release_tag"`
-	LatestReleaseTime time.Time    `json:"latest_release_time"`
-	UnreleasedCommits []CommitInfo `json:"unreleased_commits"`
-	RepositoryURL     string       `json:"repository_url"`
+	SchemaVersion     int                   `json:"schema_version"`
+	RepoID            int64                 `json:"repo_id,omitempty"`
+	Owner             string                `json:"owner"`
+	Name              string                `json:"name"`
+	DefaultBranch     string                `json:"default_branch"`
+	LatestReleaseTag  string                `json:"latest_release_tag"`
+	LatestReleaseTime time.Time             `json:"latest_release_time"`
+	BaselineType      string                `json:"baseline_type,omitempty"`
+	ReleaseAssets     []AssetInfo           `json:"release_assets,omitempty"`
+	ReleaseNotes      string                `json:"release_notes,omitempty"`
+	ReleaseHistory    []ReleaseInfo         `json:"release_history,omitempty"`
+	NeverReleased     bool                  `json:"never_released,omitempty"`
+	TotalCommits      int                   `json:"total_commits,omitempty"`
+	CreatedAt         time.Time             `json:"created_at,omitzero"`
+	Archived          bool                  `json:"archived,omitempty"`
+	Deprecated        bool                  `json:"deprecated,omitempty"`
+	Description       string                `json:"description,omitempty"`
+	Language          string                `json:"language,omitempty"`
+	Topics            []string              `json:"topics,omitempty"`
+	Stars             int                   `json:"stars"`
+	Forks             int                   `json:"forks"`
+	OpenIssues        int                   `json:"open_issues"`
+	License           string                `json:"license,omitempty"`
+	GoModule          *GoModuleInfo         `json:"go_module,omitempty"`
+	Packages          []PackageInfo         `json:"packages,omitempty"`
+	ContainerImage    *ImageInfo            `json:"container_image,omitempty"`
+	Homebrew          *HomebrewInfo         `json:"homebrew,omitempty"`
+	Prerelease        *PrereleaseInfo       `json:"prerelease,omitempty"`
+	TagWarnings       []string              `json:"tag_warnings,omitempty"`
+	CIStatus          *CIStatus             `json:"ci_status,omitempty"`
+	OpenPullRequests  *int                  `json:"open_pull_requests,omitempty"`
+	PullRequests      []PullRequestInfo     `json:"pull_requests,omitempty"`
+	Milestones        []MilestoneInfo       `json:"milestones,omitempty"`
+	DependabotAlerts  []DependabotAlertInfo `json:"dependabot_alerts,omitempty"`
+	Channels          []ChannelInfo         `json:"channels,omitempty"`
+	UnreleasedCommits []CommitInfo          `json:"unreleased_commits"`
+	UnreleasedHistory []HistoryPoint        `json:"unreleased_history,omitempty"`
+	BehindBy          int                   `json:"behind_by,omitempty"`
+	RepositoryURL     string                `json:"repository_url"`
+
+	// CherryPickedCommits are split out of UnreleasedCommits when generating pages
+	CherryPickedCommits []CommitInfo `json:"-"`
 }
 
 // SummaryData represents summary info for the index page
 type SummaryData struct {
 	Name                 string
+	Pinned               bool
 	CommitCount          int
+	DependencyCount      int
+	BreakingCount        int
+	SecurityCount        int
+	SecurityFixDays      int
+	OpenAlerts           int
+	UnreleasedAlertFixes int
 	DaysBehind           int
 	DaysSinceRelease     int
+	TypicalReleaseDays   int
 	LatestRelease        string
+	LatestReleaseTime    time.Time
+	BehindBy             int
+	Channels             []ChannelInfo
 	URL                  string
 	RepositoryURL        string
 	DefaultBranch        string
+	BaselineType         string
+	Archived             bool
+	Deprecated           bool
+	Description          string
+	Language             string
+	Topics               []string
+	Stars                int
+	Forks                int
+	OpenIssues           int
+	Impact               int
+	License              string
+	GoProxyLagging       bool
+	UnpublishedTag       string
+	ImageNotPushed       bool
+	BrewReleasesBehind   int
+	PublishLag           *PublishLag
+	Prerelease           *PrereleaseInfo
+	TagWarnings          int
+	CIStatus             *CIStatus
+	OpenPullRequests     *int
+	NextVersion          string
+	PlannedRelease       *PlannedRelease
 	CommitCountBgColor   string
 	CommitCountTextColor string
 	DaysBehindBgColor    string
@@ -62,46 +149,337 @@ type SummaryData struct {
 	DaysSinceTextColor   string
 }
 
+// CrawlOptions holds the settings that control a crawl
+type CrawlOptions struct {
+	Owner         string
+	Limit         int
+	Baseline      string
+	History       bool
+	NeverReleased bool
+	Archived      bool
+	GoProxy       bool
+	Registries    bool
+	Images        bool
+	CherryPicks   bool
+	Prereleases   bool
+	CheckTags     bool
+	CIStatus      bool
+	OpenPRs       bool
+	Milestones    bool
+	PRLabels      bool
+	Dependabot    bool
+	Prune         bool
+	MaxAPICalls   int
+	Resume        bool
+	Since         time.Time
+	Backfill      bool
+	Output        string
+	Repos         []string
+	RepoConfig    bool
+	Config        *Config
+}
+
+// Merge commit display modes for -merges
+const (
+	MergesShow     = "show"
+	MergesCollapse = "collapse"
+	MergesHide     = "hide"
+)
+
+// GenerateOptions holds the settings that control page generation
+type GenerateOptions struct {
+	Merges       string
+	FirstParent  bool
+	MaxCommits   int
+	Force        bool
+	InlineAssets bool
+	HideZero     bool
+	PDFReport    bool
+	Theme        string
+	ConfigHash   string
+	TemplatesDir string
+	CompareDirs  []string
+	Config       *Config
+}
+
 // TimestampData captures when the crawl last ran
 type TimestampData struct {
-	LastCrawled time.Time `json:"last_crawled"`
+	LastCrawled     time.Time `json:"last_crawled"`
+	DurationSeconds float64   `json:"duration_seconds,omitempty"`
 }
 
 func main() {
 	crawlMode := flag.Bool("crawl", false, "Crawl GitHub API and generate JSON files")
 	generateMode := flag.Bool("generate", false, "Generate HTML pages from JSON files")
+	migrateMode := flag.Bool("migrate", false, "Upgrade JSON files in data/ to the current schema version")
+	query := flag.String("query", "", "Print repositories in data/ matching an expression, e.g. 'commits > 10 and days_behind > 30'")
+	format := flag.String("format", FormatTable, "Output format for -query: table or json; with -generate, pdf also writes output/report.pdf, a paginated report for release planning")
+	tuiMode := flag.Bool("tui", false, "Browse data/ in an interactive terminal dashboard with sorting, filtering, and per-repository commit lists")
+	validateMode := flag.Bool("validate", false, "Check JSON files in data/ for missing fields, bad timestamps, and malformed SHAs")
+	checksMode := flag.Bool("checks", false, "Post a check run summarizing the unreleased commits in data/ on each repository's default branch")
+	prComment := flag.Int("pr-comment", 0, "Comment on this pull request with how many commits will be unreleased once it is merged, updating the comment on later runs")
+	badgePRs := flag.Bool("badge-prs", false, "Open a pull request against each repository in data/ adding its badge and dashboard link to the README (needs site_url in -config)")
+	dryRun := flag.Bool("dry-run", false, "With -badge-prs, print the changes instead of pushing branches and opening pull requests")
+	export := flag.String("export", "", "Export data/ for analysis: parquet writes one row per unreleased commit to -out, bigquery streams repositories and commits into the tables in -config")
+	exportOut := flag.String("out", "", "File written by -export (default: commits.parquet)")
+	output := flag.String("output", "", "Also stream each crawled repository to stdout as it completes: ndjson writes one JSON object per line, moving progress messages to stderr (used with -crawl)")
+	prRepo := flag.String("repo", os.Getenv("GITHUB_REPOSITORY"), "Repository of the pull request for -pr-comment, as owner/name (default: $GITHUB_REPOSITORY)")
 	owner := flag.String("owner", "", "GitHub owner/organization name (required for -crawl)")
+	reposFile := flag.String("repos-file", "", "Crawl only the repositories listed in this file (- for stdin), one owner/name per line, instead of every repository of -owner (used with -crawl)")
+	repoConfig := flag.Bool("repo-config", false, "Read .github/unreleasedcommits.yml from each repository for its branch, tag pattern, ignored commits and paths; the config file's repos section takes precedence (used with -crawl)")
 	limit := flag.Int("limit", 0, "Limit number of repositories to process (0 = no limit)")
+	baseline := flag.String("baseline", BaselineRelease, "What to compare the default branch against: release, tag, either (whichever is newer), registry (the published Go module version), or marker (the tag named in a VERSION file); repos can override it with release in -config")
+	history := flag.Bool("history", false, "Fetch the full release history for each repository (used with -crawl)")
+	includeArchived := flag.Bool("include-archived", false, "Include archived repositories, marked as archived in the output (used with -crawl)")
+	goProxy := flag.Bool("go-proxy", false, "Check the Go module proxy for repositories with a go.mod (used with -crawl)")
+	registries := flag.Bool("registries", false, "Check npm, PyPI and crates.io for packages published from each repository and how far the published version trails the default branch (used with -crawl)")
+	images := flag.Bool("images", false, "Check the container registry (ghcr.io/<owner>/<repo> unless image is configured) for image tags matching the latest release and the default branch head (used with -crawl)")
+	cherryPicks := flag.Bool("cherry-picks", false, "Detect unreleased commits already released through a cherry-pick (used with -crawl)")
+	prereleases := flag.Bool("prereleases", false, "Detect release candidate and beta tags newer than the latest stable release (used with -crawl)")
+	checkTags := flag.Bool("check-tags", false, "Warn about tags that are not semantic versions and releases published out of version order (used with -crawl)")
+	openPRs := flag.Bool("open-prs", false, "Count the open pull requests targeting the default branch and record their review state (used with -crawl)")
+	ciStatus := flag.Bool("ci-status", false, "Record the result of the latest GitHub Actions run on the default branch (used with -crawl)")
+	milestones := flag.Bool("milestones", false, "Fetch open milestones to show the progress of the one matching the suggested next version (used with -crawl)")
+	prLabels := flag.Bool("pr-labels", false, "Link unreleased commits to their pull requests and record the pull request labels (used with -crawl)")
+	dependabot := flag.Bool("dependabot", false, "Fetch Dependabot alerts to report vulnerabilities whose fix is merged but not released (used with -crawl)")
+	backfill := flag.Bool("backfill", false, "Reconstruct each repository's unreleased commit count over time from its full release history; implies -history (used with -crawl)")
+	since := flag.String("since", "", "Only crawl repositories pushed to since this date (2024-01-01) or duration ago (72h, 30d); others keep their existing data (used with -crawl)")
+	maxAPICalls := flag.Int("max-api-calls", 0, "Stop the crawl once this many GitHub API requests have been made, saving a checkpoint to continue from with -resume (0 = no limit) (used with -crawl)")
+	resume := flag.Bool("resume", false, "Continue a crawl stopped by -max-api-calls, skipping the repositories it already finished (used with -crawl)")
+	prune := flag.Bool("prune", false, "Delete data files for repositories that no longer exist or were renamed or transferred (used with -crawl)")
+	neverReleased := flag.Bool("never-released", false, "Record repositories without any release instead of skipping them (used with -crawl)")
+	templatesDir := flag.String("templates", "", "Directory of templates that replace built-in pages or partials (header, footer, repo-row, ...) by name; everything else uses the built-in templates (used with -generate)")
+	watch := flag.Bool("watch", false, "Serve output/ with live reload and regenerate when TEMPLATE_PATH or data/ changes (used with -generate)")
+	serve := flag.Bool("serve", false, "Serve output/ and regenerate when data/ changes, with /healthz and /readyz for probes (used with -generate)")
+	maxCrawlAge := flag.Duration("max-crawl-age", 48*time.Hour, "With -serve, /readyz fails when the last crawl is older than this (0 = no limit)")
+	addr := flag.String("addr", "localhost:8080", "Address to serve on with -watch or -serve")
+	inlineAssets := flag.Bool("inline-assets", false, "Embed the CSS and JavaScript into every page, producing standalone HTML files (used with -generate)")
+	hideZero := flag.Bool("hide-zero", false, "Hide repositories without unreleased commits on the index until the \"Hide released\" toggle is cleared (used with -generate)")
+	force := flag.Bool("force", false, "Regenerate every page even if its inputs are unchanged since the last run (used with -generate)")
+	maxCommits := flag.Int("max-commits", 0, "Maximum number of commits rendered on a repository page, with a link to GitHub for the rest (0 = no limit) (used with -generate)")
+	firstParent := flag.Bool("first-parent", false, "Count only commits on the default branch's first-parent chain, i.e. one per merged change (used with -generate)")
+	merges := flag.String("merges", MergesCollapse, "How to present merge commits: show, collapse, or hide (hide also excludes them from counts) (used with -generate)")
+	compare := flag.String("compare", "", "Comma-separated data directories crawled for other owners, ranked against this one on owners.html (used with -generate)")
+	theme := flag.String("theme", "", "Built-in theme for the generated pages: default, compact, or high-contrast, overriding the config's theme (used with -generate)")
+	configPath := flag.String("config", "", "Path to a JSON configuration file")
+	tagPattern := flag.String("tag-pattern", "", "Regular expression a release or tag name must match to be used as the baseline, overriding the config's global tag_pattern (used with -crawl)")
 	flag.Parse()
 
-	if !*crawlMode && !*generateMode {
-		log.Fatal("Please specify either -crawl or -generate mode")
+	modes := 0
+	for _, set := range []bool{*crawlMode, *generateMode, *migrateMode, *validateMode, *query != "", *tuiMode, *checksMode, *prComment != 0, *badgePRs, *export != ""} {
+		if set {
+			modes++
+		}
+	}
+	if modes == 0 {
+		log.Fatal("Please specify a mode: -crawl, -generate, -migrate, -validate, -query, -tui, -checks, -pr-comment, -badge-prs, or -export")
+	}
+	if modes > 1 {
+		log.Fatal("Please specify only one mode: -crawl, -generate, -migrate, -validate, -query, -tui, -checks, -pr-comment, -badge-prs, or -export")
+	}
+
+	if *migrateMode {
+		runMigrate()
+		return
+	}
+	if *validateMode {
+		runValidate()
+		return
+	}
+	if *tuiMode {
+		if err := runTUI(); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if *query != "" {
+		if *format != FormatTable && *format != FormatJSON {
+			log.Fatalf("Invalid -format value %q. Use table or json", *format)
+		}
+		if err := runQuery(*query, *format); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
 	}
+	if *tagPattern != "" {
+		cfg.TagPattern = *tagPattern
+		if err := cfg.validate(); err != nil {
+			log.Fatalf("Invalid -tag-pattern: %v", err)
+		}
+	}
+	httpTuning = cfg.HTTP
 
-	if *crawlMode && *generateMode {
-		log.Fatal("Please specify only one mode: -crawl or -generate")
+	if *export != "" {
+		if err := runExport(*export, *exportOut, cfg); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if *checksMode {
+		if err := runChecks(cfg.SiteURL); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if *badgePRs {
+		if err := runBadgePRs(cfg.SiteURL, *dryRun); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if *prComment != 0 {
+		if !validBaselineMode(*baseline) {
+			log.Fatalf("Invalid -baseline value %q. Use release, tag, either, registry, or marker", *baseline)
+		}
+		if err := runPRComment(PRCommentOptions{Repo: *prRepo, Number: *prComment, Baseline: *baseline, Config: cfg}); err != nil {
+			log.Fatal(err)
+		}
+		return
 	}
 
 	if *crawlMode {
+		var listed []string
+		if *reposFile != "" {
+			fileOwner, names, err := readRepoList(*reposFile)
+			if err != nil {
+				log.Fatalf("Failed to read -repos-file: %v", err)
+			}
+			if *owner != "" && !strings.EqualFold(*owner, fileOwner) {
+				log.Fatalf("-repos-file lists repositories of %s, not -owner %s", fileOwner, *owner)
+			}
+			if *owner == "" {
+				*owner = fileOwner
+			}
+			listed = names
+		}
 		if *owner == "" {
 			log.Fatal("Owner is required when using -crawl mode. Use -owner flag to specify the GitHub owner/organization name")
 		}
-		runCrawl(*owner, *limit)
+		if !validBaselineMode(*baseline) {
+			log.Fatalf("Invalid -baseline value %q. Use release, tag, either, registry, or marker", *baseline)
+		}
+		if *output != "" && *output != OutputNDJSON {
+			log.Fatalf("Invalid -output value %q. Use ndjson", *output)
+		}
+		var sinceTime time.Time
+		if *since != "" {
+			sinceTime, err = parseSince(*since, time.Now())
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+		runCrawl(CrawlOptions{
+			Owner:         *owner,
+			Limit:         *limit,
+			Baseline:      *baseline,
+			History:       *history || *backfill,
+			NeverReleased: *neverReleased,
+			Archived:      *includeArchived,
+			GoProxy:       *goProxy,
+			Registries:    *registries,
+			Images:        *images,
+			CherryPicks:   *cherryPicks,
+			Prereleases:   *prereleases,
+			CheckTags:     *checkTags,
+			CIStatus:      *ciStatus,
+			OpenPRs:       *openPRs,
+			Milestones:    *milestones,
+			PRLabels:      *prLabels,
+			Dependabot:    *dependabot,
+			Prune:         *prune,
+			MaxAPICalls:   *maxAPICalls,
+			Resume:        *resume,
+			Since:         sinceTime,
+			Backfill:      *backfill,
+			Output:        *output,
+			Repos:         listed,
+			RepoConfig:    *repoConfig,
+			Config:        cfg,
+		})
 	} else if *generateMode {
-		runGenerate()
+		switch *merges {
+		case MergesShow, MergesCollapse, MergesHide:
+		default:
+			log.Fatalf("Invalid -merges value %q. Use show, collapse, or hide", *merges)
+		}
+		if *theme != "" {
+			cfg.Theme = *theme
+		}
+		if cfg.Theme == "" {
+			cfg.Theme = ThemeDefault
+		}
+		if !validTheme(cfg.Theme) {
+			log.Fatalf("Invalid theme %q. Use default, compact, or high-contrast", cfg.Theme)
+		}
+		if *format != FormatTable && *format != FormatPDF {
+			log.Fatalf("Invalid -format value %q for -generate. Use pdf", *format)
+		}
+		var compareDirs []string
+		if *compare != "" {
+			compareDirs = strings.Split(*compare, ",")
+		}
+		generateOpts := GenerateOptions{
+			Merges:       *merges,
+			FirstParent:  *firstParent,
+			MaxCommits:   *maxCommits,
+			Force:        *force,
+			InlineAssets: *inlineAssets,
+			HideZero:     *hideZero,
+			PDFReport:    *format == FormatPDF,
+			Theme:        cfg.Theme,
+			ConfigHash:   hashConfig(cfg),
+			TemplatesDir: *templatesDir,
+			CompareDirs:  compareDirs,
+			Config:       cfg,
+		}
+		if *watch && *serve {
+			log.Fatal("Please specify only one of -watch and -serve")
+		}
+		if *watch {
+			if err := runWatch(generateOpts, *addr); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+		if *serve {
+			if err := runServe(generateOpts, *addr, *maxCrawlAge); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+		runGenerate(generateOpts)
 	}
 }
 
-func runCrawl(owner string, limit int) {
+func runCrawl(opts CrawlOptions) {
+	crawlStart := time.Now().UTC()
 	ctx := context.Background()
+	owner := opts.Owner
+
+	var stream *ndjsonStream
+	if opts.Output == OutputNDJSON {
+		stream = newNDJSONStream()
+	}
 
 	token := strings.TrimSpace(os.Getenv("GITHUB_TOKEN"))
 	if token == "" {
 		log.Fatal("GITHUB_TOKEN environment variable is required")
 	}
 
-	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	httpClient := oauth2.NewClient(ctx, ts)
+	httpClient := githubHTTPClient(ctx, token)
+	usage := newAPIUsage(httpClient.Transport)
+	usage.budget = opts.MaxAPICalls
+	httpClient.Transport = usage
+	telemetry := newCrawlTelemetry(usage, owner)
+	if telemetry != nil {
+		httpClient.Transport = telemetry
+		defer telemetry.export(ctx)
+	}
 	client := github.NewClient(httpClient)
 
 	fmt.Printf("Fetching repositories for organization: %s\n", owner)
@@ -111,21 +489,132 @@ func runCrawl(owner string, limit int) {
 		log.Fatalf("Failed to create output directory: %v", err)
 	}
 
-	repos, err := listPublicRepos(ctx, client, owner, limit)
+	filter, err := loadRepoFilter()
+	if err != nil {
+		log.Fatalf("Failed to read repository filter: %v", err)
+	}
+
+	tap, err := loadHomebrewTap(ctx, client, opts.Config.Homebrew)
+	if err != nil {
+		log.Fatalf("Failed to read Homebrew tap: %v", err)
+	}
+
+	var repos []*github.Repository
+	if len(opts.Repos) > 0 {
+		repos, err = getListedRepos(ctx, client, owner, opts.Repos, opts.Limit, opts.Archived, filter)
+	} else {
+		repos, err = listPublicRepos(ctx, client, owner, opts.Limit, opts.Archived, filter)
+	}
 	if err != nil {
 		log.Fatalf("Failed to list repositories: %v", err)
 	}
 
-	fmt.Printf("Found %d public repositories\n", len(repos))
+	if len(opts.Repos) > 0 {
+		fmt.Printf("Found %d of %d listed repositories\n", len(repos), len(opts.Repos))
+	} else {
+		fmt.Printf("Found %d public repositories\n", len(repos))
+	}
+
+	if opts.Limit == 0 && len(opts.Repos) == 0 {
+		migrateRenamedData(ctx, client, outputDir, owner, repos, opts.Prune)
+	}
+
+	before := snapshotRepos(outputDir)
+	previousCrawl, _ := loadLastCrawlTimestamp(filepath.Join(outputDir, "timestamp.json"))
+
+	var completed []string
+	finished := make(map[string]bool)
+	if opts.Resume {
+		cp, err := loadCheckpoint(outputDir)
+		switch {
+		case err != nil:
+			log.Fatalf("Failed to read %s: %v", checkpointFile, err)
+		case cp == nil:
+			fmt.Println("No checkpoint found, crawling every repository")
+		case cp.Owner != owner:
+			log.Fatalf("The checkpoint is for %s, not %s", cp.Owner, owner)
+		default:
+			completed = cp.Completed
+			for _, name := range cp.Completed {
+				finished[name] = true
+			}
+			fmt.Printf("Resuming crawl stopped at %s, %d repositories already done\n", cp.StoppedAt.Format(time.RFC3339), len(cp.Completed))
+		}
+	}
+
+	// A repository counts as done unless the budget cut off one of its requests
+	current := ""
+	refusedBefore := 0
+	markDone := func() {
+		if current != "" && usage.refusedRequests() == refusedBefore {
+			completed = append(completed, current)
+		}
+		current = ""
+	}
 
 	processedCount := 0
+	neverReleasedCount := 0
+	dormantCount := 0
+	stopped := false
 	for i, repo := range repos {
+		markDone()
 		repoName := repo.GetName()
+		if finished[repoName] {
+			continue
+		}
+		if !opts.Since.IsZero() && repo.GetPushedAt().Before(opts.Since) {
+			fmt.Printf("[%d/%d] ⏭️  Skipping %s (no pushes since %s)\n", i+1, len(repos), repoName, opts.Since.Format("2006-01-02"))
+			dormantCount++
+			continue
+		}
+		if usage.exhausted() {
+			stopped = true
+			break
+		}
 		fmt.Printf("[%d/%d] Processing %s...\n", i+1, len(repos), repoName)
+		usage.startRepo(repoName)
+		telemetry.startRepo(repoName)
+		current = repoName
+		refusedBefore = usage.refusedRequests()
+
+		if opts.RepoConfig {
+			fileConfig, err := fetchRepoConfig(ctx, client, owner, repoName)
+			if err != nil {
+				fmt.Printf("  ⚠️  Ignoring repository config: %v\n", err)
+			} else if fileConfig != nil {
+				opts.Config.mergeRepoConfig(repoName, *fileConfig)
+				fmt.Printf("  ⚙️  Using %s\n", repoConfigPath)
+			}
+		}
+		repoConfig := opts.Config.Repo(repoName)
+
+		strategy := releaseStrategyFor(opts.Config, repoName, opts.Baseline)
+		baseline, err := strategy.Latest(ctx, client, owner, repoName)
+		if err != nil {
+			fmt.Printf("  ❌ Error determining baseline: %v\n", err)
+			continue
+		}
+		if baseline == nil {
+			if !opts.NeverReleased {
+				fmt.Printf("  ⏭️  Skipping %s (no %s)\n", repoName, strategy.Missing())
+				continue
+			}
 
-		hasRelease, releaseData := checkLatestRelease(ctx, client, owner, repoName)
-		if !hasRelease {
-			fmt.Printf("  ⏭️  Skipping %s (no releases)\n", repoName)
+			repoData, err := buildNeverReleasedData(ctx, client, owner, repo)
+			if err != nil {
+				fmt.Printf("  ❌ Error counting commits: %v\n", err)
+				continue
+			}
+
+			filename := filepath.Join(outputDir, fmt.Sprintf("%s.json", repoName))
+			if err := writeJSON(filename, repoData); err != nil {
+				fmt.Printf("  ❌ Error writing JSON: %v\n", err)
+				continue
+			}
+
+			fmt.Printf("  📭 Never released: saved %d commits on %s to %s\n", repoData.TotalCommits, repoData.DefaultBranch, filename)
+			stream.write(repoData)
+			neverReleasedCount++
 			continue
 		}
 
@@ -136,148 +625,571 @@ func runCrawl(owner string, limit int) {
 		}
 
 		defaultBranch := repoDetail.GetDefaultBranch()
-		tagName := releaseData.GetTagName()
-		releaseTime := releaseData.GetPublishedAt().Time
+		if repoConfig.Branch != "" {
+			defaultBranch = repoConfig.Branch
+		}
+		tagName := baseline.TagName
+		releaseTime := baseline.Time
 
-		fmt.Printf("  Latest release: %s (%s)\n", tagName, releaseTime.Format("2006-01-02"))
+		fmt.Printf("  Latest %s: %s (%s)\n", baseline.Type, tagName, releaseTime.Format("2006-01-02"))
 
-		commits, err := compareAllCommits(ctx, client, owner, repoName, tagName, defaultBranch)
+		commits, behindBy, err := compareAheadBehind(ctx, client, owner, repoName, tagName, defaultBranch)
 		if err != nil {
 			fmt.Printf("  ❌ Error comparing commits: %v\n", err)
 			continue
 		}
+		if behindBy > 0 {
+			fmt.Printf("  ⚠️  %s has %d commits not on %s\n", tagName, behindBy, defaultBranch)
+		}
+
+		commitInfos := toCommitInfos(commits)
+
+		commitInfos, ignored := dropIgnoredCommits(commitInfos, repoConfig.Ignore)
+		if ignored > 0 {
+			fmt.Printf("  🙈 Ignored %d commits matching the ignore patterns\n", ignored)
+		}
+		if len(repoConfig.Paths) > 0 {
+			scoped, err := commitsInPaths(ctx, client, owner, repoName, defaultBranch, repoConfig.Paths, commitInfos)
+			if err != nil {
+				fmt.Printf("  ⚠️  Error limiting commits to %s: %v\n", strings.Join(repoConfig.Paths, ", "), err)
+			} else {
+				fmt.Printf("  📁 %d of %d commits touch %s\n", len(scoped), len(commitInfos), strings.Join(repoConfig.Paths, ", "))
+				commitInfos = scoped
+			}
+		}
 
-		var commitInfos []CommitInfo
-		for _, c := range commits {
-			author := "unknown"
-			if c.Author != nil && c.Author.GetLogin() != "" {
-				author = c.Author.GetLogin()
-			} else if c.Commit != nil && c.Commit.Author != nil && c.Commit.Author.GetName() != "" {
-				author = c.Commit.Author.GetName()
+		if opts.CherryPicks {
+			marked, err := markCherryPicked(ctx, client, owner, repoName, tagName, defaultBranch, commitInfos)
+			if err != nil {
+				fmt.Printf("  ⚠️  Error detecting cherry-picks: %v\n", err)
+			} else if marked > 0 {
+				fmt.Printf("  🍒 %d commits already released via cherry-pick\n", marked)
 			}
+		}
 
-			// A merge commit has 2 or more parents
-			isMerge := len(c.Parents) >= 2
+		if opts.PRLabels {
+			linked, err := linkPullRequests(ctx, client, owner, repoName, commitInfos)
+			if err != nil {
+				fmt.Printf("  ⚠️  Error linking pull requests: %v\n", err)
+			} else {
+				fmt.Printf("  🏷️  %d of %d commits linked to pull requests\n", linked, len(commitInfos))
+			}
+		}
 
-			commitInfos = append(commitInfos, CommitInfo{
-				SHA:       c.GetSHA(),
-				Author:    author,
-				Message:   c.Commit.GetMessage(),
-				Timestamp: c.Commit.Author.GetDate().Time,
-				URL:       c.GetHTMLURL(),
-				IsMerge:   isMerge,
-			})
+		var releaseHistory []ReleaseInfo
+		if opts.History {
+			releaseHistory, err = fetchReleaseHistory(ctx, client, owner, repoName)
+			if err != nil {
+				fmt.Printf("  ⚠️  Error fetching release history: %v\n", err)
+			} else {
+				fmt.Printf("  Release history: %d releases\n", len(releaseHistory))
+			}
 		}
 
-		// Reverse the commits so newest are first
-		for i, j := 0, len(commitInfos)-1; i < j; i, j = i+1, j-1 {
-			commitInfos[i], commitInfos[j] = commitInfos[j], commitInfos[i]
+		var goModule *GoModuleInfo
+		if opts.GoProxy {
+			goModule, err = checkGoModule(ctx, client, owner, repoName, defaultBranch, tagName)
+			if err != nil {
+				fmt.Printf("  ⚠️  Error checking Go module proxy: %v\n", err)
+			} else if goModule != nil {
+				fmt.Printf("  Go module proxy: %s@%s (%d commits behind %s)\n", goModule.Path, goModule.ProxyVersion, goModule.CommitsBehind, defaultBranch)
+				if goModule.MissingRelease != "" {
+					fmt.Printf("  ⚠️  %s is released but not the proxy's @latest\n", goModule.MissingRelease)
+				}
+			}
+		}
+
+		var packages []PackageInfo
+		if opts.Registries {
+			packages, err = checkPackages(ctx, client, owner, repoName, defaultBranch, tagName)
+			if err != nil {
+				fmt.Printf("  ⚠️  Error checking package registries: %v\n", err)
+			}
+			for _, p := range packages {
+				fmt.Printf("  %s %s@%s (%d commits behind %s)\n", p.Registry, p.Name, p.Version, p.CommitsBehind, defaultBranch)
+				if p.UnpublishedTag != "" {
+					fmt.Printf("  ⚠️  %s is tagged but not published to %s\n", p.UnpublishedTag, p.Registry)
+				}
+			}
+		}
+
+		var image *ImageInfo
+		if opts.Images && baseline.Type != BaselineBranch {
+			headSHA := ""
+			if len(commits) > 0 {
+				headSHA = commits[len(commits)-1].GetSHA()
+			}
+			image, err = checkImage(ctx, repoConfig.Image, owner, repoName, tagName, headSHA)
+			if err != nil {
+				fmt.Printf("  ⚠️  Error checking container image: %v\n", err)
+			} else if image != nil {
+				if !image.ReleasePushed {
+					fmt.Printf("  ⚠️  %s is released but %s has no image for it\n", tagName, image.Image)
+				}
+				if image.HeadTag != "" && !image.HeadPushed {
+					fmt.Printf("  %s has no %s image for the head of %s\n", image.Image, image.HeadTag, defaultBranch)
+				}
+			}
+		}
+
+		brew, err := tap.check(ctx, client, owner, repoName, cmp.Or(repoConfig.Formula, repoName))
+		if err != nil {
+			fmt.Printf("  ⚠️  Error checking Homebrew formula: %v\n", err)
+		} else if brew != nil {
+			fmt.Printf("  Homebrew formula: %s (%d releases behind)\n", brew.Version, brew.ReleasesBehind)
+		}
+
+		var prerelease *PrereleaseInfo
+		if opts.Prereleases && baseline.Type != BaselineBranch {
+			prerelease, err = findNewerPrerelease(ctx, client, owner, repoName, tagName, defaultBranch)
+			if err != nil {
+				fmt.Printf("  ⚠️  Error checking for pre-releases: %v\n", err)
+			} else if prerelease != nil {
+				fmt.Printf("  Pre-release %s published, %d commits since\n", prerelease.Tag, prerelease.CommitsSince)
+			}
+		}
+
+		var tagWarnings []string
+		if opts.CheckTags {
+			tagWarnings, err = checkTagHygiene(ctx, client, owner, repoName)
+			if err != nil {
+				fmt.Printf("  ⚠️  Error checking tags: %v\n", err)
+			} else if len(tagWarnings) > 0 {
+				fmt.Printf("  ⚠️  %d tag warnings\n", len(tagWarnings))
+			}
+		}
+
+		// The count stays unset, rather than 0, when it was not or could not be fetched
+		var openPullRequests *int
+		var pullRequests []PullRequestInfo
+		if opts.OpenPRs {
+			openPulls, err := listOpenPullRequests(ctx, client, owner, repoName, defaultBranch)
+			if err != nil {
+				fmt.Printf("  ⚠️  Error listing open pull requests: %v\n", err)
+			} else {
+				count := len(openPulls)
+				openPullRequests = &count
+				pullRequests = toPullRequestInfos(ctx, client, owner, repoName, openPulls)
+				fmt.Printf("  Open pull requests: %d\n", len(openPulls))
+			}
+		}
+
+		var milestones []MilestoneInfo
+		if opts.Milestones {
+			milestones, err = listOpenMilestones(ctx, client, owner, repoName)
+			if err != nil {
+				fmt.Printf("  ⚠️  Error fetching milestones: %v\n", err)
+			} else if planned := plannedRelease(milestones, suggestNextVersion(tagName, commitInfos)); planned != nil {
+				fmt.Printf("  Milestone %s is %d%% complete\n", planned.Title, planned.PercentComplete)
+			}
+		}
+
+		var channels []ChannelInfo
+		if channelConfigs := opts.Config.ChannelsFor(repoName); len(channelConfigs) > 0 {
+			channels, err = fetchChannels(ctx, client, owner, repoName, defaultBranch, channelConfigs)
+			if err != nil {
+				fmt.Printf("  ⚠️  Error checking release channels: %v\n", err)
+			} else {
+				for _, channel := range channels {
+					if channel.Tag == "" {
+						fmt.Printf("  Channel %s: no matching tag\n", channel.Name)
+					} else {
+						fmt.Printf("  Channel %s: %s (%d commits since)\n", channel.Name, channel.Tag, channel.UnreleasedCommits)
+					}
+				}
+			}
+		}
+
+		var alerts []DependabotAlertInfo
+		if opts.Dependabot {
+			alerts, err = fetchDependabotAlerts(ctx, client, owner, repoName, releaseTime)
+			if err != nil {
+				fmt.Printf("  ⚠️  Error fetching Dependabot alerts: %v\n", err)
+			} else if len(alerts) > 0 {
+				fmt.Printf("  🛡️  Dependabot: %d open alerts, %d fixed but unreleased\n", countAlerts(alerts, AlertOpen), countAlerts(alerts, AlertFixed))
+			}
+		}
+
+		var ci *CIStatus
+		if opts.CIStatus {
+			ci, err = fetchCIStatus(ctx, client, owner, repoName, defaultBranch)
+			if err != nil {
+				fmt.Printf("  ⚠️  Error fetching CI status: %v\n", err)
+			} else if ci != nil {
+				fmt.Printf("  CI: %s %s\n", ci.Workflow, ci.Status)
+			}
 		}
 
 		repoData := RepositoryData{
+			SchemaVersion:     currentSchemaVersion,
+			RepoID:            repo.GetID(),
 			Owner:             owner,
 			Name:              repoName,
 			DefaultBranch:     defaultBranch,
 			LatestReleaseTag:  tagName,
 			LatestReleaseTime: releaseTime,
+			BaselineType:      baseline.Type,
+			ReleaseAssets:     releaseAssets(baseline.Release),
+			ReleaseNotes:      baseline.Release.GetBody(),
+			ReleaseHistory:    releaseHistory,
+			Archived:          repo.GetArchived(),
+			Deprecated:        isDeprecated(repo.Topics),
+			Description:       repo.GetDescription(),
+			Language:          repo.GetLanguage(),
+			Topics:            repo.Topics,
+			Stars:             repo.GetStargazersCount(),
+			Forks:             repo.GetForksCount(),
+			OpenIssues:        repo.GetOpenIssuesCount(),
+			License:           licenseID(repo.License),
+			GoModule:          goModule,
+			Packages:          packages,
+			ContainerImage:    image,
+			Homebrew:          brew,
+			Prerelease:        prerelease,
+			TagWarnings:       tagWarnings,
+			CIStatus:          ci,
+			OpenPullRequests:  openPullRequests,
+			PullRequests:      pullRequests,
+			Milestones:        milestones,
+			DependabotAlerts:  alerts,
+			Channels:          channels,
 			UnreleasedCommits: commitInfos,
+			BehindBy:          behindBy,
 			RepositoryURL:     repoDetail.GetHTMLURL(),
 		}
 
 		filename := filepath.Join(outputDir, fmt.Sprintf("%s.json", repoName))
+
+		// Enrichments cut off by the budget come back empty, so keep the previous data
+		// file rather than overwrite it; -resume crawls the repository again
+		if usage.refusedRequests() != refusedBefore {
+			fmt.Printf("  ⏸️  Not saving %s, the API budget ran out while crawling it\n", repoName)
+			continue
+		}
+
+		// The history is carried over from the previous crawl, which is otherwise replaced
+		if previous, err := loadRepositoryData(filename); err == nil {
+			repoData.UnreleasedHistory = previous.UnreleasedHistory
+			if n := markNewCommits(repoData.UnreleasedCommits, previous.UnreleasedCommits); n > 0 {
+				fmt.Printf("  ✨ %d commits new since the previous crawl\n", n)
+			}
+		}
+		if opts.Backfill && len(releaseHistory) > 0 {
+			backfilled := backfillHistory(releaseHistory, commitInfos, time.Now())
+			repoData.UnreleasedHistory = mergeHistory(repoData.UnreleasedHistory, backfilled)
+			fmt.Printf("  📈 Backfilled %d weeks of unreleased commit history\n", len(backfilled))
+		}
+		repoData.UnreleasedHistory = recordHistory(repoData.UnreleasedHistory, time.Now(), len(commitInfos))
+		repoData.UnreleasedHistory = compactHistory(repoData.UnreleasedHistory, opts.Config.HistoryRetention, time.Now())
+
 		if err := writeJSON(filename, repoData); err != nil {
 			fmt.Printf("  ❌ Error writing JSON: %v\n", err)
 			continue
 		}
 
 		fmt.Printf("  ✅ Saved %d unreleased commits to %s\n", len(commitInfos), filename)
+		stream.write(repoData)
 		processedCount++
 	}
 
+	markDone()
+	usage.startRepo("")
+	telemetry.startRepo("")
+	// The budget may also run out during the last repository
+	if usage.refusedRequests() > 0 {
+		stopped = true
+	}
+
+	if stopped {
+		cp := &CrawlCheckpoint{Owner: owner, StoppedAt: time.Now().UTC(), Completed: completed}
+		if err := saveCheckpoint(outputDir, cp); err != nil {
+			log.Printf("⚠️  Failed to write checkpoint: %v", err)
+		}
+		report := reportAPIUsage(outputDir, usage)
+		fmt.Printf("\n⏸️  Stopped after %d API requests (-max-api-calls); %d of %d repositories done. Run again with -resume to continue.\n", report.Requests, len(completed), len(repos))
+		return
+	}
+	if err := removeCheckpoint(outputDir); err != nil {
+		log.Printf("⚠️  Failed to remove %s: %v", checkpointFile, err)
+	}
+
+	checkStaleData(outputDir, repos, opts)
+
 	crawlTime := time.Now().UTC()
+	duration := crawlTime.Sub(crawlStart)
 	timestampFile := filepath.Join(outputDir, "timestamp.json")
-	if err := writeJSON(timestampFile, TimestampData{LastCrawled: crawlTime}); err != nil {
+	if err := writeJSON(timestampFile, TimestampData{LastCrawled: crawlTime, DurationSeconds: duration.Seconds()}); err != nil {
 		log.Printf("⚠️  Failed to write crawl timestamp: %v", err)
 	} else {
-		fmt.Printf("\n🕒 Recorded crawl timestamp: %s\n", crawlTime.Format(time.RFC3339))
+		fmt.Printf("\n🕒 Recorded crawl timestamp: %s (took %s)\n", crawlTime.Format(time.RFC3339), duration.Round(time.Second))
+	}
+
+	reportAPIUsage(outputDir, usage)
+
+	if opts.Config.Pushgateway != nil {
+		pushMetrics(ctx, opts.Config.Pushgateway, outputDir, owner)
+	}
+
+	notification := crawlNotification(outputDir, owner, before, crawlTime, duration)
+	if err := writeJSON(filepath.Join(outputDir, changesFile), crawlChanges(notification, before, previousCrawl.LastCrawled)); err != nil {
+		log.Printf("⚠️  Failed to write %s: %v", changesFile, err)
+	}
+
+	if opts.Config.notifies() {
+		if len(opts.Config.Webhooks) > 0 {
+			sendWebhooks(ctx, opts.Config.Webhooks, notification)
+		}
+		if opts.Config.Teams != nil {
+			teams := *opts.Config.Teams
+			if teams.SiteURL == "" {
+				teams.SiteURL = opts.Config.SiteURL
+			}
+			sendTeams(ctx, &teams, notification)
+		}
+		if opts.Config.Escalation != nil {
+			escalate(ctx, outputDir, opts.Config.Escalation, notification)
+		}
 	}
 
 	fmt.Printf("\n🎉 Crawl complete! Processed %d repositories with releases.\n", processedCount)
+	if neverReleasedCount > 0 {
+		fmt.Printf("   Recorded %d repositories that have never been released.\n", neverReleasedCount)
+	}
+	if dormantCount > 0 {
+		fmt.Printf("   Skipped %d repositories with no pushes since %s.\n", dormantCount, opts.Since.Format("2006-01-02"))
+	}
+}
+
+func runGenerate(opts GenerateOptions) {
+	if err := generateSite(opts); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println("✅ Generated HTML pages in output/ directory")
+	fmt.Println("   Open output/index.html in your browser")
 }
 
-func runGenerate() {
+// generateSite renders the site from data/ into output/.
+func generateSite(opts GenerateOptions) error {
 	dataDir := "data"
 	outputDir := "output"
 
 	fmt.Println("Generating HTML pages...")
 
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		log.Fatalf("Failed to create output directory: %v", err)
+		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
-	files, err := filepath.Glob(filepath.Join(dataDir, "*.json"))
+	files, err := repositoryDataFiles(dataDir)
 	if err != nil {
-		log.Fatalf("Failed to read data directory: %v", err)
+		return fmt.Errorf("failed to read data directory: %w", err)
 	}
 
 	lastUpdated := ""
 	timestampPath := filepath.Join(dataDir, "timestamp.json")
-	if ts, err := loadLastCrawlTimestamp(timestampPath); err != nil {
+	crawl, err := loadLastCrawlTimestamp(timestampPath)
+	if err != nil {
 		if !os.IsNotExist(err) {
 			fmt.Printf("Warning: could not load crawl timestamp: %v\n", err)
 		}
 	} else {
-		lastUpdated = formatTimestampForFooter(ts)
+		lastUpdated = formatTimestampForFooter(crawl.LastCrawled)
 	}
+	lastCrawled := crawl.LastCrawled
 
 	var allRepos []RepositoryData
 	for _, file := range files {
-		if filepath.Base(file) == "timestamp.json" {
-			continue
+		repo, err := loadRepositoryData(file)
+		var schemaErr *SchemaError
+		if errors.As(err, &schemaErr) {
+			return schemaErr
 		}
-
-		var repo RepositoryData
-		data, err := os.ReadFile(file)
 		if err != nil {
-			fmt.Printf("Error reading %s: %v\n", file, err)
+			fmt.Printf("Error loading %s: %v\n", file, err)
 			continue
 		}
 
-		if err := json.Unmarshal(data, &repo); err != nil {
-			fmt.Printf("Error parsing %s: %v\n", file, err)
-			continue
+		// The first-parent chain runs through cherry-picked commits, so it is followed
+		// before they are split out
+		if opts.FirstParent {
+			commits, ok := firstParentCommits(repo.UnreleasedCommits)
+			if !ok {
+				fmt.Printf("Warning: %s has no parent data, re-crawl to use -first-parent\n", repo.Name)
+			}
+			repo.UnreleasedCommits = commits
+		}
+
+		repo.UnreleasedCommits, repo.CherryPickedCommits = splitCherryPicked(repo.UnreleasedCommits)
+
+		if opts.Merges == MergesHide {
+			repo.UnreleasedCommits = withoutMerges(repo.UnreleasedCommits)
 		}
 
 		allRepos = append(allRepos, repo)
 	}
 
 	if len(allRepos) == 0 {
-		log.Fatal("No repository JSON files found in data directory. Run with -crawl first.")
+		return errors.New("no repository JSON files found in data directory. Run with -crawl first")
 	}
 
 	sort.Slice(allRepos, func(i, j int) bool {
 		return allRepos[i].Name < allRepos[j].Name
 	})
 
-	if err := generateIndexPage(outputDir, allRepos, lastUpdated); err != nil {
-		log.Fatalf("Failed to generate index page: %v", err)
+	siteTheme = opts.Theme
+	siteBuild = newBuildInfo(opts.ConfigHash, crawl)
+
+	if opts.InlineAssets {
+		for _, name := range []string{"style.css", "script.js"} {
+			if err := loadInlineAsset(name); err != nil {
+				return fmt.Errorf("failed to load %s: %w", name, err)
+			}
+		}
+	} else {
+		// Assets are written first so pages can reference their fingerprinted names
+		if err := generateCSS(outputDir); err != nil {
+			return fmt.Errorf("failed to generate CSS: %w", err)
+		}
+
+		if err := generateJS(outputDir); err != nil {
+			return fmt.Errorf("failed to generate JavaScript: %w", err)
+		}
 	}
 
+	changes, err := loadCrawlChanges(dataDir)
+	if err != nil {
+		fmt.Printf("Warning: could not load crawl changes: %v\n", err)
+	}
+	// The first crawl has nothing to compare with
+	hasChanges := changes != nil && !changes.PreviousCrawledAt.IsZero()
+	if hasChanges {
+		if err := generateChangesPage(outputDir, changes, allRepos[0].Owner, lastUpdated, opts); err != nil {
+			fmt.Printf("Error generating changes page: %v\n", err)
+			hasChanges = false
+		}
+	}
+
+	if err := generateIndexPage(outputDir, allRepos, lastUpdated, hasChanges, opts); err != nil {
+		return fmt.Errorf("failed to generate index page: %w", err)
+	}
+
+	cache := loadGenerateCache(outputDir, opts, lastUpdated, opts.Force)
+	skipped := 0
 	for _, repo := range allRepos {
-		if err := generateRepoPage(outputDir, repo, lastUpdated); err != nil {
+		if repo.NeverReleased {
+			continue
+		}
+
+		daysSinceRelease, oldestCommitDays := repoAges(repo)
+		hash := cache.inputHash(struct {
+			Repo             RepositoryData
+			CherryPicked     []CommitInfo
+			DaysSinceRelease int
+			OldestCommitDays int
+			PullRequests     []PendingPullRequest
+		}{repo, repo.CherryPickedCommits, daysSinceRelease, oldestCommitDays, pendingPullRequests(repo)})
+
+		pages := []string{filepath.Join(outputDir, fmt.Sprintf("%s.html", repo.Name))}
+		if len(repo.ReleaseHistory) > 0 {
+			for _, page := range releasePages(repo) {
+				pages = append(pages, filepath.Join(outputDir, page))
+			}
+		}
+		for _, page := range commitChunkPages(repo, opts.MaxCommits) {
+			pages = append(pages, filepath.Join(outputDir, page))
+		}
+		if cache.unchanged(repo.Name, hash, pages...) {
+			skipped++
+			continue
+		}
+
+		ok := true
+		if err := generateRepoPage(outputDir, repo, lastUpdated, opts); err != nil {
 			fmt.Printf("Error generating page for %s: %v\n", repo.Name, err)
+			ok = false
 		}
+		if len(repo.ReleaseHistory) > 0 {
+			if err := generateReleaseHistoryPage(outputDir, repo, lastUpdated, opts); err != nil {
+				fmt.Printf("Error generating release history for %s: %v\n", repo.Name, err)
+				ok = false
+			}
+		}
+		if ok {
+			cache.update(repo.Name, hash)
+		}
+	}
+	if err := cache.save(); err != nil {
+		fmt.Printf("Warning: could not save generate cache: %v\n", err)
+	}
+	if skipped > 0 {
+		fmt.Printf("Skipped %d repositories whose pages are unchanged\n", skipped)
 	}
 
-	if err := generateCSS(outputDir); err != nil {
-		log.Fatalf("Failed to generate CSS: %v", err)
+	if hasReleaseHistory(allRepos) {
+		if err := generateMetricsPage(outputDir, allRepos, lastUpdated, opts); err != nil {
+			fmt.Printf("Error generating metrics page: %v\n", err)
+		}
+	}
+
+	if len(opts.CompareDirs) > 0 {
+		if err := generateLeaderboardPage(outputDir, allRepos, lastUpdated, opts); err != nil {
+			fmt.Printf("Error generating leaderboard: %v\n", err)
+		}
 	}
 
-	fmt.Printf("✅ Generated HTML pages in %s/ directory\n", outputDir)
-	fmt.Printf("   Open %s/index.html in your browser\n", outputDir)
+	if opts.PDFReport {
+		if err := generatePDFReport(outputDir, allRepos, lastUpdated, opts.MaxCommits); err != nil {
+			fmt.Printf("Error generating PDF report: %v\n", err)
+		}
+	}
+
+	if err := generateAPI(outputDir, allRepos, lastCrawled); err != nil {
+		fmt.Printf("Error generating JSON API: %v\n", err)
+	}
+
+	if err := generateFeeds(outputDir, allRepos, lastCrawled); err != nil {
+		fmt.Printf("Error generating Atom feeds: %v\n", err)
+	}
+
+	if err := generateCalendar(outputDir, allRepos, lastCrawled); err != nil {
+		fmt.Printf("Error generating release calendar: %v\n", err)
+	}
+
+	if err := generateBadges(outputDir, allRepos, opts.Config.ColorThresholds); err != nil {
+		fmt.Printf("Error generating badges: %v\n", err)
+	}
+
+	if err := generateBuildJSON(outputDir, siteBuild); err != nil {
+		fmt.Printf("Error writing build.json: %v\n", err)
+	}
+
+	return nil
 }
 
-func listPublicRepos(ctx context.Context, client *github.Client, owner string, limit int) ([]*github.Repository, error) {
+func runMigrate() {
+	dataDir := "data"
+
+	files, err := repositoryDataFiles(dataDir)
+	if err != nil {
+		log.Fatalf("Failed to read data directory: %v", err)
+	}
+
+	migrated := 0
+	for _, file := range files {
+		changed, err := migrateFile(file)
+		if err != nil {
+			log.Fatalf("Failed to migrate %s: %v", file, err)
+		}
+		if changed {
+			fmt.Printf("  ⬆️  Migrated %s to schema version %d\n", file, currentSchemaVersion)
+			migrated++
+		}
+	}
+
+	fmt.Printf("✅ Migrated %d of %d data files to schema version %d\n", migrated, len(files), currentSchemaVersion)
+}
+
+func listPublicRepos(ctx context.Context, client *github.Client, owner string, limit int, includeArchived bool, filter *repoFilter) ([]*github.Repository, error) {
 	var allRepos []*github.Repository
 	opt := &github.RepositoryListByOrgOptions{
 		Type:        "public",
@@ -291,7 +1203,11 @@ func listPublicRepos(ctx context.Context, client *github.Client, owner string, l
 		}
 
 		for _, repo := range repos {
-			if repo.GetArchived() {
+			if repo.GetArchived() && !includeArchived {
+				continue
+			}
+			if reason := filter.skips(owner, repo.GetName()); reason != "" {
+				fmt.Printf("⏭️  Skipping %s (%s)\n", repo.GetName(), reason)
 				continue
 			}
 			allRepos = append(allRepos, repo)
@@ -310,6 +1226,16 @@ func listPublicRepos(ctx context.Context, client *github.Client, owner string, l
 	return allRepos, nil
 }
 
+// isDeprecated reports whether the repository topics mark it as deprecated.
+func isDeprecated(topics []string) bool {
+	for _, topic := range topics {
+		if topic == deprecatedTopic {
+			return true
+		}
+	}
+	return false
+}
+
 func checkLatestRelease(ctx context.Context, client *github.Client, owner, repo string) (bool, *github.RepositoryRelease) {
 	rel, _, err := client.Repositories.GetLatestRelease(ctx, owner, repo)
 	if err != nil || rel == nil || rel.GetTagName() == "" {
@@ -318,8 +1244,117 @@ func checkLatestRelease(ctx context.Context, client *github.Client, owner, repo
 	return true, rel
 }
 
+// toCommitInfos converts commits from the compare API, which are ordered oldest
+// first, into CommitInfo records ordered newest first.
+func toCommitInfos(commits []*github.RepositoryCommit) []CommitInfo {
+	var commitInfos []CommitInfo
+	for _, c := range commits {
+		author := "unknown"
+		if c.Author != nil && c.Author.GetLogin() != "" {
+			author = c.Author.GetLogin()
+		} else if c.Commit != nil && c.Commit.Author != nil && c.Commit.Author.GetName() != "" {
+			author = c.Commit.Author.GetName()
+		}
+
+		// A merge commit has 2 or more parents
+		isMerge := len(c.Parents) >= 2
+
+		var parents []string
+		for _, p := range c.Parents {
+			parents = append(parents, p.GetSHA())
+		}
+
+		commitInfos = append(commitInfos, CommitInfo{
+			SHA:       c.GetSHA(),
+			Author:    author,
+			Message:   c.Commit.GetMessage(),
+			Timestamp: c.Commit.Author.GetDate().Time,
+			URL:       c.GetHTMLURL(),
+			IsMerge:   isMerge,
+			Parents:   parents,
+		})
+	}
+
+	// Reverse the commits so newest are first
+	for i, j := 0, len(commitInfos)-1; i < j; i, j = i+1, j-1 {
+		commitInfos[i], commitInfos[j] = commitInfos[j], commitInfos[i]
+	}
+
+	return commitInfos
+}
+
+// withoutMerges returns the commits that are not merge commits.
+func withoutMerges(commits []CommitInfo) []CommitInfo {
+	var filtered []CommitInfo
+	for _, c := range commits {
+		if !c.IsMerge {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
+// firstParentCommits follows the first-parent chain from the newest commit and returns
+// only the commits on it, in the same newest first order. The chain ends at the first
+// parent outside the list or at the root commit. It returns the commits unchanged and
+// false when they were crawled without parent information.
+func firstParentCommits(commits []CommitInfo) ([]CommitInfo, bool) {
+	if len(commits) == 0 {
+		return commits, true
+	}
+
+	// Only the root commit has no parents, so a list where no commit has any predates
+	// parents being recorded
+	hasParents := false
+	bySHA := make(map[string]CommitInfo, len(commits))
+	for _, c := range commits {
+		hasParents = hasParents || len(c.Parents) > 0
+		bySHA[c.SHA] = c
+	}
+	if !hasParents && len(commits) > 1 {
+		return commits, false
+	}
+
+	chain := []CommitInfo{commits[0]}
+	for c := commits[0]; len(c.Parents) > 0 && len(chain) < len(commits); {
+		next, ok := bySHA[c.Parents[0]]
+		if !ok {
+			break
+		}
+		chain = append(chain, next)
+		c = next
+	}
+	return chain, true
+}
+
+// releaseAssets extracts the asset details from a release, returning nil when there is no release.
+func releaseAssets(rel *github.RepositoryRelease) []AssetInfo {
+	if rel == nil {
+		return nil
+	}
+
+	var assets []AssetInfo
+	for _, a := range rel.Assets {
+		assets = append(assets, AssetInfo{
+			Name:          a.GetName(),
+			Size:          a.GetSize(),
+			DownloadCount: a.GetDownloadCount(),
+			URL:           a.GetBrowserDownloadURL(),
+		})
+	}
+	return assets
+}
+
 func compareAllCommits(ctx context.Context, client *github.Client, owner, repo, base, head string) ([]*github.RepositoryCommit, error) {
+	all, _, err := compareAheadBehind(ctx, client, owner, repo, base, head)
+	return all, err
+}
+
+// compareAheadBehind returns the commits on head that are not on base, along with how
+// many commits base has that head does not.
+func compareAheadBehind(ctx context.Context, client *github.Client, owner, repo, base, head string) ([]*github.RepositoryCommit, int, error) {
 	var all []*github.RepositoryCommit
+	behindBy := 0
 	page := 1
 	perPage := 100
 
@@ -327,10 +1362,11 @@ func compareAllCommits(ctx context.Context, client *github.Client, owner, repo,
 		comp, resp, err := client.Repositories.CompareCommits(ctx, owner, repo, base, head,
 			&github.ListOptions{Page: page, PerPage: perPage})
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
 		all = append(all, comp.Commits...)
+		behindBy = comp.GetBehindBy()
 
 		if resp.NextPage == 0 || len(comp.Commits) < perPage {
 			break
@@ -338,33 +1374,32 @@ func compareAllCommits(ctx context.Context, client *github.Client, owner, repo,
 		page = resp.NextPage
 	}
 
-	return all, nil
+	return all, behindBy, nil
 }
 
 func writeJSON(filename string, data any) error {
-	file, err := os.Create(filename)
-	if err != nil {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(data); err != nil {
 		return err
 	}
-	defer file.Close()
-
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(data)
+	return writeFileAtomic(filename, buf.Bytes())
 }
 
-func loadLastCrawlTimestamp(filename string) (time.Time, error) {
+func loadLastCrawlTimestamp(filename string) (TimestampData, error) {
 	data, err := os.ReadFile(filename)
 	if err != nil {
-		return time.Time{}, err
+		return TimestampData{}, err
 	}
 
 	var ts TimestampData
 	if err := json.Unmarshal(data, &ts); err != nil {
-		return time.Time{}, err
+		return TimestampData{}, err
 	}
 
-	return ts.LastCrawled.UTC(), nil
+	ts.LastCrawled = ts.LastCrawled.UTC()
+	return ts, nil
 }
 
 func formatTimestampForFooter(t time.Time) string {
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFirstParentCommits(t *testing.T) {
+	commit := func(sha string, parents ...string) CommitInfo {
+		return CommitInfo{SHA: sha, Parents: parents}
+	}
+	shas := func(commits []CommitInfo) []string {
+		var result []string
+		for _, c := range commits {
+			result = append(result, c.SHA)
+		}
+		return result
+	}
+	tests := []struct {
+		name    string
+		commits []CommitInfo
+		want    []string
+		ok      bool
+	}{
+		{
+			name:    "linear history",
+			commits: []CommitInfo{commit("c", "b"), commit("b", "a"), commit("a", "base")},
+			want:    []string{"c", "b", "a"},
+			ok:      true,
+		},
+		{
+			// m merges feature branch f2-f1 into a
+			name: "merge",
+			commits: []CommitInfo{
+				commit("m", "a", "f2"),
+				commit("f2", "f1"),
+				commit("a", "base"),
+				commit("f1", "base"),
+			},
+			want: []string{"m", "a"},
+			ok:   true,
+		},
+		{
+			name: "nested merges",
+			commits: []CommitInfo{
+				commit("m2", "m1", "g1"),
+				commit("g1", "f1"),
+				commit("m1", "a", "f1"),
+				commit("f1", "base"),
+				commit("a", "base"),
+			},
+			want: []string{"m2", "m1", "a"},
+			ok:   true,
+		},
+		{
+			name:    "chain reaches the root commit",
+			commits: []CommitInfo{commit("c", "b"), commit("b", "root"), commit("root")},
+			want:    []string{"c", "b", "root"},
+			ok:      true,
+		},
+		{
+			name:    "single root commit",
+			commits: []CommitInfo{commit("root")},
+			want:    []string{"root"},
+			ok:      true,
+		},
+		{
+			name:    "first parent outside the list",
+			commits: []CommitInfo{commit("m", "released", "f1"), commit("f1", "a"), commit("a", "released")},
+			want:    []string{"m"},
+			ok:      true,
+		},
+		{
+			name:    "cycle in edited data",
+			commits: []CommitInfo{commit("a", "b"), commit("b", "a")},
+			want:    []string{"a", "b"},
+			ok:      true,
+		},
+		{
+			name:    "no parent data",
+			commits: []CommitInfo{commit("c"), commit("b"), commit("a")},
+			want:    []string{"c", "b", "a"},
+			ok:      false,
+		},
+		{
+			name: "empty",
+			ok:   true,
+		},
+	}
+	for _, tt := range tests {
+		got, ok := firstParentCommits(tt.commits)
+		if ok != tt.ok || !slices.Equal(shas(got), tt.want) {
+			t.Errorf("%s: firstParentCommits = %v, %v, want %v, %v", tt.name, shas(got), ok, tt.want, tt.ok)
+		}
+	}
+}
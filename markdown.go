@@ -0,0 +1,180 @@
+package main
+
+import (
+	"html"
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+var (
+	mdFence       = regexp.MustCompile("^\\s*(```|~~~)")
+	mdHeading     = regexp.MustCompile(`^\s{0,3}(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdBullet      = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	mdOrdered     = regexp.MustCompile(`^\s*\d+[.)]\s+(.*)$`)
+	mdQuote       = regexp.MustCompile(`^\s*>\s?(.*)$`)
+	mdLinkOrURL   = regexp.MustCompile(`\[([^\]]+)\]\((https?://[^\s)]+)\)|https?://[^\s<>"]+`)
+	mdBold        = regexp.MustCompile(`\*\*([^*\s](?:[^*]*[^*\s])?)\*\*`)
+	mdItalic      = regexp.MustCompile(`\*([^*\s](?:[^*]*[^*\s])?)\*`)
+	mdStrike      = regexp.MustCompile(`~~([^~\s](?:[^~]*[^~\s])?)~~`)
+	mdURLTrailing = ".,;:!?)"
+)
+
+// renderMarkdown converts a commit message or release notes to HTML using a small
+// subset of Markdown: paragraphs, headings, bullet and numbered lists, block quotes,
+// fenced code blocks, code spans, links, bold, italic, strikethrough and emoji
+// shortcodes. All input is escaped before any markup is added, so raw HTML in the
+// source is never rendered and links are limited to http and https URLs.
+func renderMarkdown(src string) template.HTML {
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+
+	var out strings.Builder
+	var para, item []string
+	list := ""
+
+	flushPara := func() {
+		if len(para) > 0 {
+			out.WriteString("<p>" + renderInline(strings.Join(para, " ")) + "</p>")
+			para = nil
+		}
+	}
+	flushItem := func() {
+		if len(item) > 0 {
+			out.WriteString("<li>" + renderInline(strings.Join(item, " ")) + "</li>")
+			item = nil
+		}
+	}
+	closeList := func() {
+		flushItem()
+		if list != "" {
+			out.WriteString("</" + list + ">")
+			list = ""
+		}
+	}
+	openList := func(tag, text string) {
+		flushPara()
+		flushItem()
+		if list != tag {
+			closeList()
+			out.WriteString("<" + tag + ">")
+			list = tag
+		}
+		item = []string{text}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if m := mdFence.FindStringSubmatch(line); m != nil {
+			flushPara()
+			closeList()
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), m[1]); i++ {
+				code = append(code, lines[i])
+			}
+			out.WriteString("<pre><code>" + html.EscapeString(strings.Join(code, "\n")) + "</code></pre>")
+			continue
+		}
+
+		if strings.TrimSpace(line) == "" {
+			flushPara()
+			closeList()
+			continue
+		}
+
+		if m := mdHeading.FindStringSubmatch(line); m != nil {
+			flushPara()
+			closeList()
+			// Headings are demoted so they sit below the page's own section headings
+			out.WriteString("<h4>" + renderInline(m[2]) + "</h4>")
+			continue
+		}
+
+		if m := mdBullet.FindStringSubmatch(line); m != nil {
+			openList("ul", m[1])
+			continue
+		}
+
+		if m := mdOrdered.FindStringSubmatch(line); m != nil {
+			openList("ol", m[1])
+			continue
+		}
+
+		if m := mdQuote.FindStringSubmatch(line); m != nil {
+			flushPara()
+			closeList()
+			out.WriteString("<blockquote>" + renderInline(m[1]) + "</blockquote>")
+			continue
+		}
+
+		// Indented lines continue the previous list item rather than starting a paragraph
+		if len(item) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			item = append(item, strings.TrimSpace(line))
+			continue
+		}
+
+		closeList()
+		para = append(para, strings.TrimSpace(line))
+	}
+	flushPara()
+	closeList()
+
+	return template.HTML(out.String())
+}
+
+// renderInline renders code spans, links and emphasis within a single block of text.
+func renderInline(text string) string {
+	var out strings.Builder
+	for {
+		start := strings.IndexByte(text, '`')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(text[start+1:], '`')
+		if end < 0 {
+			break
+		}
+		out.WriteString(renderText(text[:start]))
+		out.WriteString("<code>" + html.EscapeString(text[start+1:start+1+end]) + "</code>")
+		text = text[start+end+2:]
+	}
+	out.WriteString(renderText(text))
+	return out.String()
+}
+
+// renderText escapes plain text and then applies emoji shortcodes, links and emphasis to it.
+func renderText(text string) string {
+	text = emojify(text)
+
+	var out strings.Builder
+	last := 0
+	for _, loc := range mdLinkOrURL.FindAllStringSubmatchIndex(text, -1) {
+		out.WriteString(renderEmphasis(html.EscapeString(text[last:loc[0]])))
+		last = loc[1]
+
+		if loc[2] >= 0 {
+			label, url := text[loc[2]:loc[3]], text[loc[4]:loc[5]]
+			out.WriteString(markdownLink(url, renderEmphasis(html.EscapeString(label))))
+			continue
+		}
+
+		url := text[loc[0]:loc[1]]
+		trimmed := strings.TrimRight(url, mdURLTrailing)
+		last -= len(url) - len(trimmed)
+		out.WriteString(markdownLink(trimmed, html.EscapeString(trimmed)))
+	}
+	out.WriteString(renderEmphasis(html.EscapeString(text[last:])))
+	return out.String()
+}
+
+// renderEmphasis applies bold, italic and strikethrough to already escaped text.
+func renderEmphasis(escaped string) string {
+	escaped = mdBold.ReplaceAllString(escaped, "<strong>$1</strong>")
+	escaped = mdItalic.ReplaceAllString(escaped, "<em>$1</em>")
+	return mdStrike.ReplaceAllString(escaped, "<del>$1</del>")
+}
+
+// markdownLink builds an anchor that opens an external URL in a new tab.
+func markdownLink(url, label string) string {
+	return `<a href="` + html.EscapeString(url) + `" target="_blank" rel="noopener noreferrer" class="github-link">` + label + `</a>`
+}
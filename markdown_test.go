@@ -0,0 +1,91 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "paragraph",
+			src:  "Fix the crawler\n\nIt **no longer** skips forks",
+			want: "<p>Fix the crawler</p><p>It <strong>no longer</strong> skips forks</p>",
+		},
+		{
+			name: "script tag",
+			src:  "<script>alert(1)</script>",
+			want: "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>",
+		},
+		{
+			name: "raw HTML",
+			src:  `<img src=x onerror="alert(1)">`,
+			want: "<p>&lt;img src=x onerror=&#34;alert(1)&#34;&gt;</p>",
+		},
+		{
+			name: "javascript link",
+			src:  "[click](javascript:alert(1))",
+			want: "<p>[click](javascript:alert(1))</p>",
+		},
+		{
+			name: "data link",
+			src:  "[click](data:text/html,<script>alert(1)</script>)",
+			want: "<p>[click](data:text/html,&lt;script&gt;alert(1)&lt;/script&gt;)</p>",
+		},
+		{
+			name: "link",
+			src:  "See [the docs](https://example.com/docs).",
+			want: `<p>See <a href="https://example.com/docs" target="_blank" rel="noopener noreferrer" class="github-link">the docs</a>.</p>`,
+		},
+		{
+			name: "attribute injection in link",
+			src:  `[x](https://example.com/"onmouseover="alert(1))`,
+			want: `<p><a href="https://example.com/&#34;onmouseover=&#34;alert(1" target="_blank" rel="noopener noreferrer" class="github-link">x</a>)</p>`,
+		},
+		{
+			name: "attribute injection in bare URL",
+			src:  `https://example.com/'onmouseover='alert(1)`,
+			want: `<p><a href="https://example.com/&#39;onmouseover=&#39;alert(1" target="_blank" rel="noopener noreferrer" class="github-link">https://example.com/&#39;onmouseover=&#39;alert(1</a>)</p>`,
+		},
+		{
+			name: "HTML in link label",
+			src:  "[<b>bold</b>](https://example.com)",
+			want: `<p><a href="https://example.com" target="_blank" rel="noopener noreferrer" class="github-link">&lt;b&gt;bold&lt;/b&gt;</a></p>`,
+		},
+		{
+			name: "HTML in code span",
+			src:  "Use `<script>` tags",
+			want: "<p>Use <code>&lt;script&gt;</code> tags</p>",
+		},
+		{
+			name: "emoji outside code and links",
+			src:  ":bug: fix `:bug:` in [:memo: docs](https://example.com/:memo:)",
+			want: `<p>🐛 fix <code>:bug:</code> in <a href="https://example.com/:memo:" target="_blank" rel="noopener noreferrer" class="github-link">📝 docs</a></p>`,
+		},
+		{
+			name: "HTML in code block",
+			src:  "```\n<script>alert(1)</script>\n```",
+			want: "<pre><code>&lt;script&gt;alert(1)&lt;/script&gt;</code></pre>",
+		},
+		{
+			name: "HTML in heading and list",
+			src:  "# <h1>Title</h1>\n- <i>item</i>",
+			want: "<h4>&lt;h1&gt;Title&lt;/h1&gt;</h4><ul><li>&lt;i&gt;item&lt;/i&gt;</li></ul>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(renderMarkdown(tt.src))
+			if got != tt.want {
+				t.Errorf("renderMarkdown(%q)\n got: %s\nwant: %s", tt.src, got, tt.want)
+			}
+			if strings.Contains(got, "<script") || strings.Contains(got, `href="javascript:`) || strings.Contains(got, `href="data:`) {
+				t.Errorf("renderMarkdown(%q) rendered active content: %s", tt.src, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// releaseFrequencyWindow is the period over which recent releases are counted
+const releaseFrequencyWindow = 90 * 24 * time.Hour
+
+// LeadTimeStats summarizes how long commits waited between being authored and being released
+type LeadTimeStats struct {
+	Commits        int
+	Median         time.Duration
+	P90            time.Duration
+	RecentReleases int
+}
+
+// RepoLeadTime is the lead time summary for a single repository
+type RepoLeadTime struct {
+	Name          string
+	URL           string
+	Releases      int
+	LatestRelease string
+	LeadTimeStats
+}
+
+// collectLeadTimes returns the time from each commit to the release that included it.
+func collectLeadTimes(history []ReleaseInfo) []time.Duration {
+	var leadTimes []time.Duration
+	for _, rel := range history {
+		for _, c := range rel.Commits {
+			if c.Timestamp.IsZero() || rel.PublishedAt.Before(c.Timestamp) {
+				continue
+			}
+			leadTimes = append(leadTimes, rel.PublishedAt.Sub(c.Timestamp))
+		}
+	}
+	return leadTimes
+}
+
+// countRecentReleases counts releases published within the release frequency window.
+func countRecentReleases(history []ReleaseInfo, now time.Time) int {
+	count := 0
+	for _, rel := range history {
+		if now.Sub(rel.PublishedAt) <= releaseFrequencyWindow {
+			count++
+		}
+	}
+	return count
+}
+
+// summarizeLeadTimes computes the median and 90th percentile of the lead times.
+func summarizeLeadTimes(leadTimes []time.Duration) LeadTimeStats {
+	if len(leadTimes) == 0 {
+		return LeadTimeStats{}
+	}
+
+	sorted := append([]time.Duration(nil), leadTimes...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return LeadTimeStats{
+		Commits: len(sorted),
+		Median:  median(sorted),
+		P90:     percentile(sorted, 0.9),
+	}
+}
+
+// median returns the median of a non-empty ascending slice.
+func median(sorted []time.Duration) time.Duration {
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// percentile returns the nearest-rank percentile p (0-1) of a non-empty ascending slice.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	idx := int(float64(len(sorted))*p+0.5) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
+// computeLeadTimeMetrics builds the org-wide and per-repository lead time summaries
+// for every repository that has release history.
+func computeLeadTimeMetrics(repos []RepositoryData, now time.Time) (LeadTimeStats, []RepoLeadTime) {
+	var all []time.Duration
+	var perRepo []RepoLeadTime
+	recent := 0
+
+	for _, repo := range repos {
+		if len(repo.ReleaseHistory) == 0 {
+			continue
+		}
+
+		leadTimes := collectLeadTimes(repo.ReleaseHistory)
+		all = append(all, leadTimes...)
+
+		stats := summarizeLeadTimes(leadTimes)
+		stats.RecentReleases = countRecentReleases(repo.ReleaseHistory, now)
+		recent += stats.RecentReleases
+
+		perRepo = append(perRepo, RepoLeadTime{
+			Name:          repo.Name,
+			URL:           fmt.Sprintf("%s.html", repo.Name),
+			Releases:      len(repo.ReleaseHistory),
+			LatestRelease: repo.LatestReleaseTag,
+			LeadTimeStats: stats,
+		})
+	}
+
+	org := summarizeLeadTimes(all)
+	org.RecentReleases = recent
+	return org, perRepo
+}
+
+// formatDuration renders a lead time in hours below two days and in days otherwise.
+func formatDuration(d time.Duration) string {
+	if d < 48*time.Hour {
+		return fmt.Sprintf("%.1f hours", d.Hours())
+	}
+	return fmt.Sprintf("%.1f days", d.Hours()/24)
+}
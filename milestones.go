@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// milestoneVersionPattern finds a version number in a milestone title such as
+// "v1.3.0" or "Release 1.3"
+var milestoneVersionPattern = regexp.MustCompile(`\bv?(\d+)\.(\d+)(?:\.(\d+))?\b`)
+
+// MilestoneInfo is an open milestone of a repository
+type MilestoneInfo struct {
+	Title        string    `json:"title"`
+	URL          string    `json:"url"`
+	OpenIssues   int       `json:"open_issues"`
+	ClosedIssues int       `json:"closed_issues"`
+	DueOn        time.Time `json:"due_on,omitzero"`
+}
+
+// PlannedRelease is the open milestone matching the suggested next version
+type PlannedRelease struct {
+	MilestoneInfo
+	PercentComplete int
+}
+
+// listOpenMilestones returns the repository's open milestones.
+func listOpenMilestones(ctx context.Context, client *github.Client, owner, repo string) ([]MilestoneInfo, error) {
+	var all []MilestoneInfo
+	opt := &github.MilestoneListOptions{
+		State:       "open",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	for {
+		milestones, resp, err := client.Issues.ListMilestones(ctx, owner, repo, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range milestones {
+			all = append(all, MilestoneInfo{
+				Title:        m.GetTitle(),
+				URL:          m.GetHTMLURL(),
+				OpenIssues:   m.GetOpenIssues(),
+				ClosedIssues: m.GetClosedIssues(),
+				DueOn:        m.GetDueOn().Time,
+			})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return all, nil
+}
+
+// plannedRelease returns the open milestone whose title names the suggested next
+// version, ignoring a "v" prefix and a missing patch number, or nil if none does.
+func plannedRelease(milestones []MilestoneInfo, nextVersion string) *PlannedRelease {
+	next, ok := parseSemver(nextVersion)
+	if !ok {
+		return nil
+	}
+	for _, m := range milestones {
+		match := milestoneVersionPattern.FindStringSubmatch(m.Title)
+		if match == nil {
+			continue
+		}
+		version, _ := parseSemver(match[1] + "." + match[2] + "." + orZero(match[3]))
+		if version.Major != next.Major || version.Minor != next.Minor || version.Patch != next.Patch {
+			continue
+		}
+
+		planned := &PlannedRelease{MilestoneInfo: m}
+		if total := m.OpenIssues + m.ClosedIssues; total > 0 {
+			planned.PercentComplete = m.ClosedIssues * 100 / total
+		}
+		return planned
+	}
+	return nil
+}
+
+func orZero(s string) string {
+	if s == "" {
+		return "0"
+	}
+	return s
+}
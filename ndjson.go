@@ -0,0 +1,38 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Crawl outputs for -output
+const (
+	OutputNDJSON = "ndjson"
+)
+
+// ndjsonStream writes each crawled repository to stdout as one line of JSON as soon as
+// it is saved, so a crawl can be piped into jq or another process
+type ndjsonStream struct {
+	enc *json.Encoder
+}
+
+// newNDJSONStream streams to stdout and moves the crawl's progress messages to stderr,
+// which keeps stdout free of anything but the JSON lines.
+func newNDJSONStream() *ndjsonStream {
+	out := io.Writer(os.Stdout)
+	os.Stdout = os.Stderr
+	return &ndjsonStream{enc: json.NewEncoder(out)}
+}
+
+// write emits repo; it is a no-op on a nil stream. A failed write, e.g. because the
+// reading process exited, is reported but does not stop the crawl.
+func (s *ndjsonStream) write(repo RepositoryData) {
+	if s == nil {
+		return
+	}
+	if err := s.enc.Encode(repo); err != nil {
+		fmt.Fprintf(os.Stderr, "  ⚠️  Failed to stream %s: %v\n", repo.Name, err)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// NeverReleasedSummary represents a repository without any release on the index page
+type NeverReleasedSummary struct {
+	Name          string
+	RepositoryURL string
+	DefaultBranch string
+	TotalCommits  int
+	CreatedAt     time.Time
+	AgeDays       int
+	Archived      bool
+	Deprecated    bool
+	License       string
+}
+
+// buildNeverReleasedData records a repository that has no baseline to compare against,
+// counting every commit on its default branch as unreleased.
+func buildNeverReleasedData(ctx context.Context, client *github.Client, owner string, repo *github.Repository) (RepositoryData, error) {
+	total, err := countBranchCommits(ctx, client, owner, repo.GetName(), repo.GetDefaultBranch())
+	if err != nil {
+		return RepositoryData{}, err
+	}
+
+	return RepositoryData{
+		SchemaVersion: currentSchemaVersion,
+		RepoID:        repo.GetID(),
+		Owner:         owner,
+		Name:          repo.GetName(),
+		DefaultBranch: repo.GetDefaultBranch(),
+		NeverReleased: true,
+		TotalCommits:  total,
+		CreatedAt:     repo.GetCreatedAt().Time,
+		Archived:      repo.GetArchived(),
+		Deprecated:    isDeprecated(repo.Topics),
+		Description:   repo.GetDescription(),
+		Language:      repo.GetLanguage(),
+		Topics:        repo.Topics,
+		Stars:         repo.GetStargazersCount(),
+		Forks:         repo.GetForksCount(),
+		OpenIssues:    repo.GetOpenIssuesCount(),
+		License:       licenseID(repo.License),
+		RepositoryURL: repo.GetHTMLURL(),
+	}, nil
+}
+
+// countBranchCommits counts the commits reachable from a branch by requesting one
+// commit per page and reading the number of the last page.
+func countBranchCommits(ctx context.Context, client *github.Client, owner, repo, branch string) (int, error) {
+	commits, resp, err := client.Repositories.ListCommits(ctx, owner, repo, &github.CommitsListOptions{
+		SHA:         branch,
+		ListOptions: github.ListOptions{PerPage: 1},
+	})
+	if err != nil {
+		return 0, err
+	}
+	if resp.LastPage > 0 {
+		return resp.LastPage, nil
+	}
+	return len(commits), nil
+}
+
+// summarizeNeverReleased builds the index rows for never released repositories,
+// ordered by total commit count with the largest first.
+func summarizeNeverReleased(repos []RepositoryData) []NeverReleasedSummary {
+	var summaries []NeverReleasedSummary
+	for _, repo := range repos {
+		if !repo.NeverReleased {
+			continue
+		}
+		ageDays := 0
+		if !repo.CreatedAt.IsZero() {
+			ageDays = int(time.Since(repo.CreatedAt).Hours() / 24)
+		}
+		summaries = append(summaries, NeverReleasedSummary{
+			Name:          repo.Name,
+			RepositoryURL: repo.RepositoryURL,
+			DefaultBranch: repo.DefaultBranch,
+			TotalCommits:  repo.TotalCommits,
+			CreatedAt:     repo.CreatedAt,
+			AgeDays:       ageDays,
+			Archived:      repo.Archived,
+			Deprecated:    repo.Deprecated,
+			License:       repo.License,
+		})
+	}
+
+	sort.SliceStable(summaries, func(i, j int) bool {
+		return summaries[i].TotalCommits > summaries[j].TotalCommits
+	})
+	return summaries
+}
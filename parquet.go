@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"time"
+)
+
+// Parquet physical types, converted types and encodings used by parquetWriter, numbered
+// as in the Parquet format's Thrift definitions
+const (
+	parquetBoolean   = 0
+	parquetInt64     = 2
+	parquetByteArray = 6
+
+	parquetUTF8            = 0
+	parquetTimestampMillis = 9
+
+	parquetPlain = 0
+	parquetRLE   = 3
+)
+
+// ParquetColumn is a required column of a Parquet file
+type ParquetColumn struct {
+	Name string
+	Kind ParquetKind
+}
+
+// ParquetKind is the type of a Parquet column's values: string, int64, bool or time.Time
+type ParquetKind int
+
+// Supported Parquet column kinds
+const (
+	ParquetString ParquetKind = iota
+	ParquetInt64
+	ParquetBool
+	ParquetTimestamp
+)
+
+// writeParquet writes rows as an uncompressed Parquet file with a single row group.
+// Every column is required and PLAIN encoded, the simplest layout every reader accepts;
+// the values in each row must match the kinds of columns.
+func writeParquet(w io.Writer, columns []ParquetColumn, rows [][]any) error {
+	var file bytes.Buffer
+	file.WriteString("PAR1")
+
+	chunks := make([]thriftStruct, len(columns))
+	var totalSize int64
+	for i, col := range columns {
+		var data bytes.Buffer
+		encodeParquetValues(&data, col.Kind, rows, i)
+
+		header := thriftStruct{
+			{1, int32(0)}, // DATA_PAGE
+			{2, int32(data.Len())},
+			{3, int32(data.Len())},
+			{5, thriftStruct{
+				{1, int32(len(rows))},
+				{2, int32(parquetPlain)},
+				{3, int32(parquetRLE)},
+				{4, int32(parquetRLE)},
+			}},
+		}
+		var page bytes.Buffer
+		header.encode(&page)
+		page.Write(data.Bytes())
+
+		offset := int64(file.Len())
+		file.Write(page.Bytes())
+		size := int64(page.Len())
+		totalSize += size
+
+		chunks[i] = thriftStruct{
+			{2, offset},
+			{3, thriftStruct{
+				{1, int32(parquetPhysicalType(col.Kind))},
+				{2, []any{int32(parquetPlain), int32(parquetRLE)}},
+				{3, []any{col.Name}},
+				{4, int32(0)}, // UNCOMPRESSED
+				{5, int64(len(rows))},
+				{6, size},
+				{7, size},
+				{9, offset},
+			}},
+		}
+	}
+
+	schema := []any{thriftStruct{
+		{4, "schema"},
+		{5, int32(len(columns))},
+	}}
+	for _, col := range columns {
+		element := thriftStruct{
+			{1, int32(parquetPhysicalType(col.Kind))},
+			{3, int32(0)}, // REQUIRED
+			{4, col.Name},
+		}
+		switch col.Kind {
+		case ParquetString:
+			element = append(element, thriftField{6, int32(parquetUTF8)})
+		case ParquetTimestamp:
+			element = append(element, thriftField{6, int32(parquetTimestampMillis)})
+		}
+		schema = append(schema, element)
+	}
+
+	columnChunks := make([]any, len(chunks))
+	for i, chunk := range chunks {
+		columnChunks[i] = chunk
+	}
+	metadata := thriftStruct{
+		{1, int32(1)},
+		{2, schema},
+		{3, int64(len(rows))},
+		{4, []any{thriftStruct{
+			{1, columnChunks},
+			{2, totalSize},
+			{3, int64(len(rows))},
+		}}},
+		{6, "unreleasedcommits " + version},
+	}
+
+	var footer bytes.Buffer
+	metadata.encode(&footer)
+	file.Write(footer.Bytes())
+	binary.Write(&file, binary.LittleEndian, uint32(footer.Len()))
+	file.WriteString("PAR1")
+
+	_, err := w.Write(file.Bytes())
+	return err
+}
+
+// parquetPhysicalType returns how values of kind are stored.
+func parquetPhysicalType(kind ParquetKind) int {
+	switch kind {
+	case ParquetInt64, ParquetTimestamp:
+		return parquetInt64
+	case ParquetBool:
+		return parquetBoolean
+	}
+	return parquetByteArray
+}
+
+// encodeParquetValues PLAIN encodes column i of rows.
+func encodeParquetValues(buf *bytes.Buffer, kind ParquetKind, rows [][]any, i int) {
+	if kind == ParquetBool {
+		// Booleans are bit-packed, least significant bit first
+		packed := make([]byte, (len(rows)+7)/8)
+		for r, row := range rows {
+			if row[i].(bool) {
+				packed[r/8] |= 1 << (r % 8)
+			}
+		}
+		buf.Write(packed)
+		return
+	}
+
+	for _, row := range rows {
+		switch kind {
+		case ParquetString:
+			s := row[i].(string)
+			binary.Write(buf, binary.LittleEndian, uint32(len(s)))
+			buf.WriteString(s)
+		case ParquetInt64:
+			binary.Write(buf, binary.LittleEndian, row[i].(int64))
+		case ParquetTimestamp:
+			binary.Write(buf, binary.LittleEndian, row[i].(time.Time).UnixMilli())
+		}
+	}
+}
+
+// thriftField is a field of a Thrift struct. Values are int32, int64, string, []any
+// (a list of values of one type) or a nested thriftStruct.
+type thriftField struct {
+	ID    int16
+	Value any
+}
+
+// thriftStruct is a Thrift struct with its fields in ascending ID order
+type thriftStruct []thriftField
+
+// Thrift compact protocol type codes
+const (
+	thriftTypeI32    = 5
+	thriftTypeI64    = 6
+	thriftTypeBinary = 8
+	thriftTypeList   = 9
+	thriftTypeStruct = 12
+)
+
+// encode writes the struct in the Thrift compact protocol, which Parquet uses for its
+// page headers and file metadata.
+func (s thriftStruct) encode(buf *bytes.Buffer) {
+	var last int16
+	for _, f := range s {
+		typ := thriftType(f.Value)
+		if delta := f.ID - last; delta > 0 && delta <= 15 {
+			buf.WriteByte(byte(delta)<<4 | typ)
+		} else {
+			buf.WriteByte(typ)
+			writeVarint(buf, zigzag(int64(f.ID)))
+		}
+		last = f.ID
+		writeThriftValue(buf, f.Value)
+	}
+	buf.WriteByte(0) // STOP
+}
+
+// thriftType returns the compact protocol type code of a value.
+func thriftType(v any) byte {
+	switch v.(type) {
+	case int32:
+		return thriftTypeI32
+	case int64:
+		return thriftTypeI64
+	case string:
+		return thriftTypeBinary
+	case []any:
+		return thriftTypeList
+	}
+	return thriftTypeStruct
+}
+
+// writeThriftValue writes a single value in the Thrift compact protocol.
+func writeThriftValue(buf *bytes.Buffer, v any) {
+	switch v := v.(type) {
+	case int32:
+		writeVarint(buf, zigzag(int64(v)))
+	case int64:
+		writeVarint(buf, zigzag(v))
+	case string:
+		writeVarint(buf, uint64(len(v)))
+		buf.WriteString(v)
+	case []any:
+		var elem byte = thriftTypeStruct
+		if len(v) > 0 {
+			elem = thriftType(v[0])
+		}
+		if len(v) < 15 {
+			buf.WriteByte(byte(len(v))<<4 | elem)
+		} else {
+			buf.WriteByte(0xF0 | elem)
+			writeVarint(buf, uint64(len(v)))
+		}
+		for _, e := range v {
+			writeThriftValue(buf, e)
+		}
+	case thriftStruct:
+		v.encode(buf)
+	}
+}
+
+func zigzag(n int64) uint64 {
+	return uint64(n<<1) ^ uint64(n>>63)
+}
+
+func writeVarint(buf *bytes.Buffer, n uint64) {
+	var b [binary.MaxVarintLen64]byte
+	buf.Write(b[:binary.PutUvarint(b[:], n)])
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// thriftReader decodes the Thrift compact protocol subset written by thriftStruct, so
+// tests can read back a Parquet footer and page headers
+type thriftReader struct {
+	t   *testing.T
+	buf []byte
+	pos int
+}
+
+func (r *thriftReader) readByte() byte {
+	if r.pos >= len(r.buf) {
+		r.t.Fatalf("thrift: unexpected end of data at %d", r.pos)
+	}
+	b := r.buf[r.pos]
+	r.pos++
+	return b
+}
+
+func (r *thriftReader) varint() uint64 {
+	n, size := binary.Uvarint(r.buf[r.pos:])
+	if size <= 0 {
+		r.t.Fatalf("thrift: invalid varint at %d", r.pos)
+	}
+	r.pos += size
+	return n
+}
+
+func (r *thriftReader) zigzag() int64 {
+	n := r.varint()
+	return int64(n>>1) ^ -int64(n&1)
+}
+
+func (r *thriftReader) value(typ byte) any {
+	switch typ {
+	case thriftTypeI32, thriftTypeI64:
+		return r.zigzag()
+	case thriftTypeBinary:
+		n := int(r.varint())
+		s := string(r.buf[r.pos : r.pos+n])
+		r.pos += n
+		return s
+	case thriftTypeList:
+		header := r.readByte()
+		n, elem := int(header>>4), header&0x0F
+		if n == 15 {
+			n = int(r.varint())
+		}
+		list := make([]any, n)
+		for i := range list {
+			list[i] = r.value(elem)
+		}
+		return list
+	case thriftTypeStruct:
+		return r.structure()
+	}
+	r.t.Fatalf("thrift: unsupported type %d at %d", typ, r.pos)
+	return nil
+}
+
+// structure reads a struct as a map from field ID to value.
+func (r *thriftReader) structure() map[int16]any {
+	fields := make(map[int16]any)
+	var last int16
+	for {
+		header := r.readByte()
+		if header == 0 {
+			return fields
+		}
+		id := last + int16(header>>4)
+		if header>>4 == 0 {
+			id = int16(r.zigzag())
+		}
+		fields[id] = r.value(header & 0x0F)
+		last = id
+	}
+}
+
+func TestWriteParquet(t *testing.T) {
+	columns := []ParquetColumn{
+		{Name: "repo", Kind: ParquetString},
+		{Name: "count", Kind: ParquetInt64},
+		{Name: "breaking", Kind: ParquetBool},
+		{Name: "timestamp", Kind: ParquetTimestamp},
+	}
+	base := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	var rows [][]any
+	for i := range 20 {
+		rows = append(rows, []any{
+			"repo-" + string(rune('a'+i)) + "-ü",
+			int64(i*1000 - 5000),
+			i%3 == 0,
+			base.Add(time.Duration(i) * time.Hour),
+		})
+	}
+
+	var out bytes.Buffer
+	if err := writeParquet(&out, columns, rows); err != nil {
+		t.Fatal(err)
+	}
+	file := out.Bytes()
+
+	if !bytes.HasPrefix(file, []byte("PAR1")) || !bytes.HasSuffix(file, []byte("PAR1")) {
+		t.Fatal("file does not start and end with the PAR1 magic")
+	}
+	footerLen := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	footerStart := len(file) - 8 - footerLen
+	if footerStart < 4 {
+		t.Fatalf("footer length %d does not fit in a %d byte file", footerLen, len(file))
+	}
+	footer := &thriftReader{t: t, buf: file[footerStart : len(file)-8]}
+	metadata := footer.structure()
+	if footer.pos != footerLen {
+		t.Errorf("footer metadata is %d bytes, footer length says %d", footer.pos, footerLen)
+	}
+
+	if metadata[1] != int64(1) {
+		t.Errorf("version = %v, want 1", metadata[1])
+	}
+	if metadata[3] != int64(len(rows)) {
+		t.Errorf("num_rows = %v, want %d", metadata[3], len(rows))
+	}
+
+	schema := metadata[2].([]any)
+	if len(schema) != len(columns)+1 || schema[0].(map[int16]any)[5] != int64(len(columns)) {
+		t.Fatalf("schema = %v, want a root with %d children", schema, len(columns))
+	}
+	for i, col := range columns {
+		element := schema[i+1].(map[int16]any)
+		if element[4] != col.Name || element[1] != int64(parquetPhysicalType(col.Kind)) {
+			t.Errorf("schema element %d = %v, want %s", i+1, element, col.Name)
+		}
+	}
+
+	rowGroups := metadata[4].([]any)
+	if len(rowGroups) != 1 {
+		t.Fatalf("%d row groups, want 1", len(rowGroups))
+	}
+	rowGroup := rowGroups[0].(map[int16]any)
+	if rowGroup[3] != int64(len(rows)) {
+		t.Errorf("row group num_rows = %v, want %d", rowGroup[3], len(rows))
+	}
+	chunks := rowGroup[1].([]any)
+	if len(chunks) != len(columns) {
+		t.Fatalf("%d column chunks, want %d", len(chunks), len(columns))
+	}
+
+	for i, col := range columns {
+		chunk := chunks[i].(map[int16]any)
+		meta := chunk[3].(map[int16]any)
+		if !reflect.DeepEqual(meta[3], []any{col.Name}) || meta[5] != int64(len(rows)) {
+			t.Errorf("column %s metadata = %v", col.Name, meta)
+		}
+
+		offset := int(meta[9].(int64))
+		size := int(meta[7].(int64))
+		if offset < 4 || offset+size > footerStart {
+			t.Fatalf("column %s page at %d+%d is outside the data", col.Name, offset, size)
+		}
+		page := &thriftReader{t: t, buf: file[offset : offset+size]}
+		header := page.structure()
+		dataHeader := header[5].(map[int16]any)
+		if dataHeader[1] != int64(len(rows)) {
+			t.Errorf("column %s page has %v values, want %d", col.Name, dataHeader[1], len(rows))
+		}
+		data := page.buf[page.pos:]
+		if header[2] != int64(len(data)) {
+			t.Errorf("column %s page header says %v bytes, page has %d", col.Name, header[2], len(data))
+		}
+
+		for r, row := range rows {
+			var got any
+			switch col.Kind {
+			case ParquetString:
+				n := int(binary.LittleEndian.Uint32(data))
+				got, data = string(data[4:4+n]), data[4+n:]
+			case ParquetInt64:
+				got, data = int64(binary.LittleEndian.Uint64(data)), data[8:]
+			case ParquetTimestamp:
+				got, data = time.UnixMilli(int64(binary.LittleEndian.Uint64(data))).UTC(), data[8:]
+			case ParquetBool:
+				got = data[r/8]&(1<<(r%8)) != 0
+			}
+			if got != row[i] {
+				t.Errorf("column %s row %d = %v, want %v", col.Name, r, got, row[i])
+			}
+		}
+	}
+}
+
+func TestWriteParquetNoRows(t *testing.T) {
+	var out bytes.Buffer
+	if err := writeParquet(&out, []ParquetColumn{{Name: "repo", Kind: ParquetString}}, nil); err != nil {
+		t.Fatal(err)
+	}
+	file := out.Bytes()
+	footerLen := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	footer := &thriftReader{t: t, buf: file[len(file)-8-footerLen : len(file)-8]}
+	if rows := footer.structure()[3]; rows != int64(0) {
+		t.Errorf("num_rows = %v, want 0", rows)
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// PDF page layout in points, for US Letter pages
+const (
+	pdfPageWidth  = 612
+	pdfPageHeight = 792
+	pdfMargin     = 50
+	pdfFontSize   = 9
+	pdfLineHeight = 12
+	// Courier glyphs are 0.6em wide, so a line holds a fixed number of characters
+	pdfLineChars = (pdfPageWidth - 2*pdfMargin) * 10 / (pdfFontSize * 6)
+)
+
+// Fonts of a pdfDocument, both standard Type 1 fonts every PDF reader provides
+const (
+	pdfFontText    = "F1"
+	pdfFontHeading = "F2"
+)
+
+// pdfText is a line of text placed on a page
+type pdfText struct {
+	Font string
+	Size float64
+	X, Y float64
+	Text string
+}
+
+// pdfDocument lays out lines of text top to bottom, starting a new page when one is full
+type pdfDocument struct {
+	pages [][]pdfText
+	y     float64
+}
+
+func newPDFDocument() *pdfDocument {
+	d := &pdfDocument{}
+	d.newPage()
+	return d
+}
+
+func (d *pdfDocument) newPage() {
+	d.pages = append(d.pages, nil)
+	d.y = pdfPageHeight - pdfMargin
+}
+
+// add places text at the current position, moving to a new page when the space left is
+// less than needed.
+func (d *pdfDocument) add(font string, size, height, needed float64, text string) {
+	if d.y-needed < pdfMargin {
+		d.newPage()
+	}
+	d.y -= height
+	page := len(d.pages) - 1
+	d.pages[page] = append(d.pages[page], pdfText{Font: font, Size: size, X: pdfMargin, Y: d.y, Text: text})
+}
+
+// title adds the document title in large bold type.
+func (d *pdfDocument) title(text string) {
+	d.add(pdfFontHeading, 16, 20, 20, text)
+}
+
+// heading adds a bold section heading, kept on the same page as at least the next few lines.
+func (d *pdfDocument) heading(text string) {
+	d.gap()
+	d.add(pdfFontHeading, 11, 16, 16+4*pdfLineHeight, text)
+}
+
+// line adds a line of monospaced text, cut to the width of the page.
+func (d *pdfDocument) line(text string) {
+	d.add(pdfFontText, pdfFontSize, pdfLineHeight, pdfLineHeight, truncateRunes(text, pdfLineChars))
+}
+
+// gap adds a blank line, unless at the top of a page.
+func (d *pdfDocument) gap() {
+	if d.y < pdfPageHeight-pdfMargin {
+		d.y -= pdfLineHeight / 2
+	}
+}
+
+// bytes renders the document, numbering the pages in the bottom margin.
+func (d *pdfDocument) bytes() []byte {
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// Objects 1 to 4 are the catalog, page tree, and fonts; each page is then
+	// followed by its content stream
+	buf.WriteString("%PDF-1.4\n")
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range d.pages {
+		footer := fmt.Sprintf("Page %d of %d", i+1, len(d.pages))
+		page = append(page, pdfText{
+			Font: pdfFontText,
+			Size: 8,
+			X:    pdfPageWidth - pdfMargin - float64(len(footer))*8*0.6,
+			Y:    pdfMargin / 2,
+			Text: footer,
+		})
+
+		var content strings.Builder
+		for _, t := range page {
+			fmt.Fprintf(&content, "BT /%s %g Tf %g %g Td (%s) Tj ET\n", t.Font, t.Size, t.X, t.Y, pdfString(t.Text))
+		}
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /%s 3 0 R /%s 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, pdfFontText, pdfFontHeading, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return buf.Bytes()
+}
+
+// pdfString escapes text for a PDF string literal in WinAnsiEncoding. Characters outside
+// Latin-1 are replaced with the closest ASCII or a question mark.
+func pdfString(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		case r == '‘' || r == '’':
+			b.WriteByte('\'')
+		case r == '“' || r == '”':
+			b.WriteByte('"')
+		case r == '–' || r == '—':
+			b.WriteByte('-')
+		case r == '…':
+			b.WriteString("...")
+		case r == '\t':
+			b.WriteByte(' ')
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// truncateRunes shortens text to at most n characters, ending with "..." when cut.
+func truncateRunes(text string, n int) string {
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
+	}
+	return string(runes[:n-3]) + "..."
+}
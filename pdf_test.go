@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestPDFDocumentXref(t *testing.T) {
+	doc := newPDFDocument()
+	doc.title("Unreleased Commits: acme (ü)")
+	for i := range 150 {
+		if i%40 == 0 {
+			doc.heading(fmt.Sprintf("Section %d", i/40))
+		}
+		doc.line(fmt.Sprintf("line %d with (parentheses), a \\ backslash and “quotes” ✓", i))
+	}
+	if len(doc.pages) < 3 {
+		t.Fatalf("expected at least 3 pages, got %d", len(doc.pages))
+	}
+	pdf := doc.bytes()
+
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Fatal("missing PDF header or EOF marker")
+	}
+
+	m := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(pdf)
+	if m == nil {
+		t.Fatal("missing startxref")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	if !bytes.HasPrefix(pdf[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %d does not point at the xref table", xref)
+	}
+
+	lines := strings.Split(string(pdf[xref:]), "\n")
+	var first, count int
+	if _, err := fmt.Sscanf(lines[1], "%d %d", &first, &count); err != nil || first != 0 {
+		t.Fatalf("invalid xref subsection header %q", lines[1])
+	}
+	wantCount := 4 + 2*len(doc.pages) + 1
+	if count != wantCount {
+		t.Errorf("xref has %d entries, want %d", count, wantCount)
+	}
+	if lines[2] != "0000000000 65535 f " {
+		t.Errorf("xref entry 0 = %q", lines[2])
+	}
+	for n := 1; n < count; n++ {
+		entry := lines[2+n]
+		// Each entry is exactly 20 bytes including the end of line
+		if len(entry) != 19 || !strings.HasSuffix(entry, " 00000 n ") {
+			t.Fatalf("xref entry %d = %q", n, entry)
+		}
+		offset, _ := strconv.Atoi(entry[:10])
+		if want := fmt.Sprintf("%d 0 obj\n", n); !bytes.HasPrefix(pdf[offset:], []byte(want)) {
+			t.Errorf("xref entry %d points at %q, want %q", n, pdf[offset:min(offset+20, len(pdf))], want)
+		}
+	}
+	if !strings.Contains(string(pdf[xref:]), fmt.Sprintf("/Size %d ", count)) {
+		t.Errorf("trailer /Size does not match the %d xref entries", count)
+	}
+
+	// Each content stream's /Length matches the bytes between stream and endstream
+	streams := regexp.MustCompile(`(?s)<< /Length (\d+) >>\nstream\n(.*?)\nendstream`).FindAllSubmatch(pdf, -1)
+	if len(streams) != len(doc.pages) {
+		t.Fatalf("found %d content streams, want %d", len(streams), len(doc.pages))
+	}
+	for i, s := range streams {
+		if length, _ := strconv.Atoi(string(s[1])); length != len(s[2]) {
+			t.Errorf("page %d stream /Length %d, content is %d bytes", i+1, length, len(s[2]))
+		}
+	}
+}
+
+func TestPDFString(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"plain text", "plain text"},
+		{`a (b) \c`, `a \(b\) \\c`},
+		{"café", `caf\351`},
+		{"‘single’ “double” – — …", `'single' "double" - - ...`},
+		{"tab\there", "tab here"},
+		{"✓ 日本", "? ??"},
+		{"line\nbreak", "line?break"},
+	}
+	for _, tt := range tests {
+		if got := pdfString(tt.text); got != tt.want {
+			t.Errorf("pdfString(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestTruncateRunes(t *testing.T) {
+	tests := []struct {
+		text string
+		n    int
+		want string
+	}{
+		{"short", 10, "short"},
+		{"exactly10!", 10, "exactly10!"},
+		{"a bit too long", 10, "a bit t..."},
+		{"ünïcödé text", 8, "ünïcö..."},
+	}
+	for _, tt := range tests {
+		if got := truncateRunes(tt.text, tt.n); got != tt.want {
+			t.Errorf("truncateRunes(%q, %d) = %q, want %q", tt.text, tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// impactScore weighs a repository's unreleased commits by its popularity, so a widely
+// used repository with a few pending commits ranks above an unused one with many. The
+// square root keeps a handful of extremely popular repositories from dwarfing the rest.
+func impactScore(commits, stars, forks int) int {
+	return int(math.Round(float64(commits) * math.Sqrt(float64(1+stars+forks))))
+}
+
+// formatCount abbreviates large counts, e.g. 1234 as 1.2k.
+func formatCount(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return trimDecimal(float64(n)/1_000_000) + "M"
+	case n >= 1_000:
+		return trimDecimal(float64(n)/1_000) + "k"
+	}
+	return fmt.Sprint(n)
+}
+
+// trimDecimal formats f with one decimal place, dropping it when it is zero.
+func trimDecimal(f float64) string {
+	s := fmt.Sprintf("%.1f", math.Floor(f*10)/10)
+	if len(s) > 2 && s[len(s)-2:] == ".0" {
+		return s[:len(s)-2]
+	}
+	return s
+}
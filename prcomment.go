@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// prCommentMarker identifies the comment posted by -pr-comment, so later runs update it
+// instead of adding another
+const prCommentMarker = "<!-- unreleasedcommits -->"
+
+// PRCommentOptions holds the settings for -pr-comment
+type PRCommentOptions struct {
+	Repo     string
+	Number   int
+	Baseline string
+	Config   *Config
+}
+
+// runPRComment posts or updates a comment on a pull request telling how many commits
+// will be unreleased once it is merged and how long ago the last release was.
+func runPRComment(opts PRCommentOptions) error {
+	owner, repo, ok := strings.Cut(opts.Repo, "/")
+	if !ok || owner == "" || repo == "" {
+		return fmt.Errorf("invalid repository %q, expected owner/name", opts.Repo)
+	}
+
+	ctx := context.Background()
+	client, err := newGitHubClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	pr, _, err := client.PullRequests.Get(ctx, owner, repo, opts.Number)
+	if err != nil {
+		return fmt.Errorf("failed to get pull request #%d: %w", opts.Number, err)
+	}
+	target := pr.GetBase().GetRef()
+
+	baseline, err := releaseStrategyFor(opts.Config, repo, opts.Baseline).Latest(ctx, client, owner, repo)
+	if err != nil {
+		return fmt.Errorf("failed to determine baseline: %w", err)
+	}
+
+	unreleased := 0
+	if baseline != nil {
+		// Only the count is needed, which the first page of the comparison reports
+		comparison, _, err := client.Repositories.CompareCommits(ctx, owner, repo, baseline.TagName, target, &github.ListOptions{PerPage: 1})
+		if err != nil {
+			return fmt.Errorf("failed to compare %s with %s: %w", baseline.TagName, target, err)
+		}
+		unreleased = comparison.GetAheadBy()
+	}
+
+	body := prCommentBody(baseline, unreleased, target, time.Now())
+	comment, err := findPRComment(ctx, client, owner, repo, opts.Number)
+	if err != nil {
+		return err
+	}
+	if comment != nil {
+		if _, _, err := client.Issues.EditComment(ctx, owner, repo, comment.GetID(), &github.IssueComment{Body: github.String(body)}); err != nil {
+			return fmt.Errorf("failed to update comment: %w", err)
+		}
+		fmt.Printf("✅ Updated comment on %s#%d\n", opts.Repo, opts.Number)
+		return nil
+	}
+	if _, _, err := client.Issues.CreateComment(ctx, owner, repo, opts.Number, &github.IssueComment{Body: github.String(body)}); err != nil {
+		return fmt.Errorf("failed to create comment: %w", err)
+	}
+	fmt.Printf("✅ Commented on %s#%d\n", opts.Repo, opts.Number)
+	return nil
+}
+
+// prCommentBody words the comment, counting the pull request as one more commit on
+// target, as a merge or squash adds.
+func prCommentBody(baseline *Baseline, unreleased int, target string, now time.Time) string {
+	var b strings.Builder
+	b.WriteString(prCommentMarker + "\n")
+	if baseline == nil {
+		fmt.Fprintf(&b, "📦 This repository has no release yet, so merging this will add to `%s` without ever being released. Consider cutting a first release.\n", target)
+		return b.String()
+	}
+
+	days := int(now.Sub(baseline.Time).Hours() / 24)
+	fmt.Fprintf(&b, "📦 Merging this will make **%d unreleased commits** on `%s`; the last release, `%s`, was %d days ago.\n", unreleased+1, target, baseline.TagName, days)
+	if unreleased > 0 {
+		b.WriteString("\nConsider cutting a release soon so these changes reach users.\n")
+	}
+	return b.String()
+}
+
+// findPRComment returns the comment posted by an earlier -pr-comment run, or nil.
+func findPRComment(ctx context.Context, client *github.Client, owner, repo string, number int) (*github.IssueComment, error) {
+	opt := &github.IssueListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		comments, resp, err := client.Issues.ListComments(ctx, owner, repo, number, opt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list comments: %w", err)
+		}
+		for _, c := range comments {
+			if strings.HasPrefix(c.GetBody(), prCommentMarker) {
+				return c, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			return nil, nil
+		}
+		opt.Page = resp.NextPage
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// PrereleaseInfo describes a release candidate or beta tag newer than the baseline
+type PrereleaseInfo struct {
+	Tag          string `json:"tag"`
+	CommitsSince int    `json:"commits_since"`
+}
+
+// findNewerPrerelease looks for the highest prerelease tag (such as v1.3.0-rc.1 or
+// v2.0.0-beta) that sorts after the stable baseline tag, and counts the commits on the
+// branch since it. It returns nil when the baseline is not a version or nothing newer exists.
+func findNewerPrerelease(ctx context.Context, client *github.Client, owner, repo, baselineTag, branch string) (*PrereleaseInfo, error) {
+	stable, ok := parseSemver(baselineTag)
+	if !ok {
+		return nil, nil
+	}
+
+	tags, err := listAllTags(ctx, client, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var best string
+	var bestVersion SemVersion
+	for _, tag := range tags {
+		v, ok := parseSemver(tag.GetName())
+		if !ok || v.Prerelease == "" || v.Compare(stable) <= 0 {
+			continue
+		}
+		if best == "" || v.Compare(bestVersion) > 0 {
+			best = tag.GetName()
+			bestVersion = v
+		}
+	}
+	if best == "" {
+		return nil, nil
+	}
+
+	comp, _, err := client.Repositories.CompareCommits(ctx, owner, repo, best, branch, &github.ListOptions{PerPage: 1})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare %s...%s: %w", best, branch, err)
+	}
+
+	return &PrereleaseInfo{
+		Tag:          best,
+		CommitsSince: comp.GetAheadBy(),
+	}, nil
+}
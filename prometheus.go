@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// prometheusContentType is the Prometheus text exposition format
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// PushgatewayConfig pushes the gauges to a Prometheus Pushgateway after each crawl
+type PushgatewayConfig struct {
+	URL string `json:"url"`
+	Job string `json:"job,omitempty"`
+}
+
+// repoGauge is a per-repository gauge of the Prometheus metrics. Repositories for which
+// known returns false, such as counts the crawl did not collect, have no sample.
+type repoGauge struct {
+	name  string
+	help  string
+	value func(APIRepoSummary) float64
+	known func(APIRepoSummary) bool
+}
+
+// repoGauges are exported for every repository, labeled with owner and repo
+var repoGauges = []repoGauge{
+	{"unreleasedcommits_unreleased_commits", "Commits on the default branch since the latest release", func(s APIRepoSummary) float64 { return float64(s.UnreleasedCommits) }, nil},
+	{"unreleasedcommits_days_behind", "Days between the latest release and the newest unreleased commit", func(s APIRepoSummary) float64 { return float64(s.DaysBehind) }, nil},
+	{"unreleasedcommits_days_since_release", "Days since the latest release", func(s APIRepoSummary) float64 { return float64(s.DaysSinceRelease) }, nil},
+	{"unreleasedcommits_security_fixes", "Unreleased commits that fix a security issue", func(s APIRepoSummary) float64 { return float64(s.SecurityFixes) }, nil},
+	{"unreleasedcommits_breaking_changes", "Unreleased commits marked as breaking changes", func(s APIRepoSummary) float64 { return float64(s.BreakingChanges) }, nil},
+	{"unreleasedcommits_open_pull_requests", "Open pull requests targeting the default branch", func(s APIRepoSummary) float64 { return float64(*s.OpenPullRequests) }, func(s APIRepoSummary) bool { return s.OpenPullRequests != nil }},
+}
+
+// validatePushgateway checks that the Pushgateway has a URL.
+func validatePushgateway(p *PushgatewayConfig) error {
+	if p == nil {
+		return nil
+	}
+	if p.URL == "" {
+		return fmt.Errorf("url is required")
+	}
+	if _, err := url.Parse(p.URL); err != nil {
+		return err
+	}
+	return nil
+}
+
+// writePrometheusMetrics writes the gauges for the repositories in dir in the Prometheus
+// text format.
+func writePrometheusMetrics(w io.Writer, dir string) error {
+	repos, err := loadAllRepositoryData(dir)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	for _, g := range repoGauges {
+		fmt.Fprintf(&buf, "# HELP %s %s\n# TYPE %s gauge\n", g.name, g.help, g.name)
+		for _, repo := range repos {
+			s := apiRepoSummary(repo)
+			if g.known != nil && !g.known(s) {
+				continue
+			}
+			fmt.Fprintf(&buf, "%s{owner=\"%s\",repo=\"%s\"} %g\n", g.name, escapeLabel(repo.Owner), escapeLabel(repo.Name), g.value(s))
+		}
+	}
+
+	if crawl, err := loadLastCrawlTimestamp(filepath.Join(dir, "timestamp.json")); err == nil {
+		fmt.Fprintf(&buf, "# HELP unreleasedcommits_last_crawl_timestamp_seconds Time the last crawl finished\n# TYPE unreleasedcommits_last_crawl_timestamp_seconds gauge\n")
+		fmt.Fprintf(&buf, "unreleasedcommits_last_crawl_timestamp_seconds %d\n", crawl.LastCrawled.Unix())
+		fmt.Fprintf(&buf, "# HELP unreleasedcommits_crawl_duration_seconds Duration of the last crawl\n# TYPE unreleasedcommits_crawl_duration_seconds gauge\n")
+		fmt.Fprintf(&buf, "unreleasedcommits_crawl_duration_seconds %g\n", crawl.DurationSeconds)
+	}
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// escapeLabel escapes a Prometheus label value.
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+// registerMetrics adds /metrics, serving the gauges for the repositories in dataDir.
+func registerMetrics(mux *http.ServeMux, dataDir string) {
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		if err := writePrometheusMetrics(&buf, dataDir); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", prometheusContentType)
+		w.Write(buf.Bytes())
+	})
+}
+
+// pushMetrics replaces the owner's group on the Pushgateway with the gauges for dir, so
+// repositories removed since the previous push disappear too. A failed push is reported
+// but does not fail the crawl.
+func pushMetrics(ctx context.Context, p *PushgatewayConfig, dir, owner string) {
+	var body bytes.Buffer
+	if err := writePrometheusMetrics(&body, dir); err != nil {
+		fmt.Printf("⚠️  Failed to collect metrics for the Pushgateway: %v\n", err)
+		return
+	}
+
+	job := p.Job
+	if job == "" {
+		job = "unreleasedcommits"
+	}
+	endpoint := fmt.Sprintf("%s/metrics/job/%s/owner/%s", strings.TrimSuffix(p.URL, "/"), url.PathEscape(job), url.PathEscape(owner))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, &body)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to push metrics: %v\n", err)
+		return
+	}
+	req.Header.Set("Content-Type", prometheusContentType)
+	req.Header.Set("User-Agent", "unreleasedcommits/"+version)
+
+	client := &http.Client{Timeout: webhookTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to push metrics: %v\n", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		fmt.Printf("⚠️  Failed to push metrics: unexpected status %s\n", resp.Status)
+		return
+	}
+	fmt.Printf("📈 Pushed metrics to %s\n", strings.TrimSuffix(p.URL, "/"))
+}
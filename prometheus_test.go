@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWritePrometheusMetricsUncounted(t *testing.T) {
+	dir := t.TempDir()
+	two := 2
+	for _, repo := range []RepositoryData{
+		{SchemaVersion: currentSchemaVersion, Owner: "acme", Name: "counted", OpenPullRequests: &two},
+		{SchemaVersion: currentSchemaVersion, Owner: "acme", Name: "uncounted"},
+	} {
+		if err := writeJSON(filepath.Join(dir, repo.Name+".json"), repo); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := writePrometheusMetrics(&buf, dir); err != nil {
+		t.Fatal(err)
+	}
+	metrics := buf.String()
+
+	if !strings.Contains(metrics, `unreleasedcommits_open_pull_requests{owner="acme",repo="counted"} 2`+"\n") {
+		t.Errorf("missing the counted repository's sample:\n%s", metrics)
+	}
+	if strings.Contains(metrics, `unreleasedcommits_open_pull_requests{owner="acme",repo="uncounted"}`) {
+		t.Errorf("published a sample for a repository whose pull requests were not counted:\n%s", metrics)
+	}
+	if !strings.Contains(metrics, `unreleasedcommits_unreleased_commits{owner="acme",repo="uncounted"} 0`+"\n") {
+		t.Errorf("missing the uncounted repository's other samples:\n%s", metrics)
+	}
+
+	// The BigQuery row and the API summary leave the count null rather than 0
+	row, err := json.Marshal(bigQueryRepoRow(RepositoryData{Name: "uncounted"}, time.Time{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(row), `"open_pull_requests":null`) {
+		t.Errorf("BigQuery row = %s, want open_pull_requests null", row)
+	}
+	summary, err := json.Marshal(apiRepoSummary(RepositoryData{Name: "uncounted"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(summary), "open_pull_requests") {
+		t.Errorf("API summary = %s, want no open_pull_requests", summary)
+	}
+}
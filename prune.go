@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// staleDataFiles returns the data files in dir that do not belong to any of repos,
+// such as files left behind by repositories that were deleted, renamed, or made private.
+func staleDataFiles(dir string, repos []*github.Repository) ([]string, error) {
+	current := make(map[string]bool, len(repos))
+	for _, repo := range repos {
+		current[repo.GetName()] = true
+	}
+
+	files, err := repositoryDataFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var stale []string
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".json")
+		if !current[name] {
+			stale = append(stale, file)
+		}
+	}
+	return stale, nil
+}
+
+// checkStaleData reports the data files the crawl did not see, deleting them with -prune.
+// A crawl limited with -limit or -repos-file only sees some repositories, so it cannot
+// tell which files are stale and leaves them all alone.
+func checkStaleData(dir string, repos []*github.Repository, opts CrawlOptions) {
+	if opts.Limit > 0 || len(opts.Repos) > 0 {
+		if opts.Prune {
+			fmt.Println("\n⚠️  Skipping -prune because -limit or -repos-file only lists some repositories")
+		}
+		return
+	}
+	reportStaleData(dir, repos, opts.Prune)
+}
+
+// reportStaleData lists data files for repositories that no longer exist and deletes
+// them when prune is set.
+func reportStaleData(dir string, repos []*github.Repository, prune bool) {
+	stale, err := staleDataFiles(dir, repos)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to check for stale data files: %v\n", err)
+		return
+	}
+	if len(stale) == 0 {
+		return
+	}
+
+	if !prune {
+		fmt.Printf("\n⚠️  %d data files no longer match a repository (use -prune to remove them):\n", len(stale))
+		for _, file := range stale {
+			fmt.Printf("   - %s\n", file)
+		}
+		return
+	}
+
+	fmt.Printf("\n🧹 Pruning %d data files that no longer match a repository:\n", len(stale))
+	for _, file := range stale {
+		if err := os.Remove(file); err != nil {
+			fmt.Printf("   ❌ %s: %v\n", file, err)
+			continue
+		}
+		fmt.Printf("   - %s\n", file)
+	}
+}
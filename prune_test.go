@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/google/go-github/v62/github"
+)
+
+func TestCheckStaleData(t *testing.T) {
+	setup := func(t *testing.T) string {
+		dir := t.TempDir()
+		for _, name := range []string{"alpha.json", "beta.json", "gamma.json", "timestamp.json"} {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return dir
+	}
+	remaining := func(t *testing.T, dir string) []string {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		return names
+	}
+	crawled := []*github.Repository{{Name: github.String("alpha")}}
+	all := []string{"alpha.json", "beta.json", "gamma.json", "timestamp.json"}
+
+	tests := []struct {
+		name string
+		opts CrawlOptions
+		want []string
+	}{
+		{"full crawl with prune", CrawlOptions{Prune: true}, []string{"alpha.json", "timestamp.json"}},
+		{"full crawl without prune", CrawlOptions{}, all},
+		{"repos file with prune", CrawlOptions{Prune: true, Repos: []string{"acme/alpha"}}, all},
+		{"limit with prune", CrawlOptions{Prune: true, Limit: 1}, all},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := setup(t)
+			checkStaleData(dir, crawled, tt.opts)
+			if got := remaining(t, dir); !slices.Equal(got, tt.want) {
+				t.Errorf("files left = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// PublishLag is how long the latest release took to become available on a distribution
+// channel, or has been waiting for it when Pending
+type PublishLag struct {
+	Channel   string
+	Version   string
+	Available time.Time
+	Lag       time.Duration
+	Pending   bool
+}
+
+// publishLags returns the lag between the latest release and its availability on each
+// distribution channel the crawl checked, worst first. The Go module proxy does not record
+// when it fetched a version, so it only appears while the release is missing from it.
+func publishLags(repo RepositoryData, now time.Time) []PublishLag {
+	released := repo.LatestReleaseTime
+	if repo.LatestReleaseTag == "" || released.IsZero() || repo.BaselineType == BaselineBranch {
+		return nil
+	}
+
+	var lags []PublishLag
+	available := func(channel, version string, at time.Time) {
+		lags = append(lags, PublishLag{Channel: channel, Version: version, Available: at, Lag: max(at.Sub(released), 0)})
+	}
+	pending := func(channel string) {
+		lags = append(lags, PublishLag{Channel: channel, Version: repo.LatestReleaseTag, Lag: now.Sub(released), Pending: true})
+	}
+
+	if m := repo.GoModule; m != nil && m.MissingRelease == repo.LatestReleaseTag {
+		pending("Go module proxy")
+	}
+	for _, p := range repo.Packages {
+		switch {
+		case p.UnpublishedTag == repo.LatestReleaseTag:
+			pending(registryName(p.Registry))
+		case p.Tag == repo.LatestReleaseTag && !p.PublishedAt.IsZero():
+			available(registryName(p.Registry), p.Version, p.PublishedAt)
+		}
+	}
+	if img := repo.ContainerImage; img != nil {
+		switch {
+		case !img.ReleasePushed:
+			pending("Container image")
+		case !img.ReleaseCreated.IsZero():
+			available("Container image", img.ReleaseTag, img.ReleaseCreated)
+		}
+	}
+	if brew := repo.Homebrew; brew != nil {
+		switch {
+		case brew.Tag != repo.LatestReleaseTag:
+			pending("Homebrew")
+		case !brew.UpdatedAt.IsZero():
+			available("Homebrew", brew.Version, brew.UpdatedAt)
+		}
+	}
+
+	slices.SortStableFunc(lags, func(a, b PublishLag) int {
+		return cmp.Compare(b.Lag, a.Lag)
+	})
+	return lags
+}
+
+// worstPublishLag returns the channel that took or is taking longest to publish the
+// latest release, or nil when no channel was checked.
+func worstPublishLag(repo RepositoryData, now time.Time) *PublishLag {
+	lags := publishLags(repo, now)
+	if len(lags) == 0 {
+		return nil
+	}
+	return &lags[0]
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// Review states of an open pull request, from its latest review by each reviewer
+const (
+	ReviewApproved         = "approved"
+	ReviewChangesRequested = "changes_requested"
+	ReviewRequested        = "review_requested"
+)
+
+// PullRequestInfo is an open pull request targeting the default branch
+type PullRequestInfo struct {
+	Number      int       `json:"number"`
+	Title       string    `json:"title"`
+	Author      string    `json:"author"`
+	URL         string    `json:"url"`
+	CreatedAt   time.Time `json:"created_at"`
+	Draft       bool      `json:"draft,omitempty"`
+	ReviewState string    `json:"review_state,omitempty"`
+}
+
+// PendingPullRequest is an open pull request as listed on the repository page
+type PendingPullRequest struct {
+	PullRequestInfo
+	AgeDays int
+}
+
+// listOpenPullRequests returns the open pull requests targeting base.
+func listOpenPullRequests(ctx context.Context, client *github.Client, owner, repo, base string) ([]*github.PullRequest, error) {
+	var all []*github.PullRequest
+	opt := &github.PullRequestListOptions{
+		State:       "open",
+		Base:        base,
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	for {
+		pulls, resp, err := client.PullRequests.List(ctx, owner, repo, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, pulls...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return all, nil
+}
+
+// toPullRequestInfos records each pull request with its review state. A pull request
+// whose reviews cannot be fetched is still recorded, without a review state.
+func toPullRequestInfos(ctx context.Context, client *github.Client, owner, repo string, pulls []*github.PullRequest) []PullRequestInfo {
+	infos := make([]PullRequestInfo, 0, len(pulls))
+	for _, pr := range pulls {
+		info := PullRequestInfo{
+			Number:    pr.GetNumber(),
+			Title:     pr.GetTitle(),
+			Author:    pr.GetUser().GetLogin(),
+			URL:       pr.GetHTMLURL(),
+			CreatedAt: pr.GetCreatedAt().Time,
+			Draft:     pr.GetDraft(),
+		}
+		reviews, err := listReviews(ctx, client, owner, repo, pr.GetNumber())
+		if err == nil {
+			info.ReviewState = reviewState(reviews, len(pr.RequestedReviewers)+len(pr.RequestedTeams) > 0)
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// listReviews returns every review of a pull request, oldest first.
+func listReviews(ctx context.Context, client *github.Client, owner, repo string, number int) ([]*github.PullRequestReview, error) {
+	var all []*github.PullRequestReview
+	opt := &github.ListOptions{PerPage: 100}
+
+	for {
+		reviews, resp, err := client.PullRequests.ListReviews(ctx, owner, repo, number, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, reviews...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return all, nil
+}
+
+// reviewState summarizes the latest approval or change request from each reviewer:
+// any outstanding change request wins over approvals. Without either, a pull request
+// is awaiting review when reviewers have been requested.
+func reviewState(reviews []*github.PullRequestReview, requested bool) string {
+	latest := make(map[string]string)
+	for _, review := range reviews {
+		switch state := review.GetState(); state {
+		case "APPROVED", "CHANGES_REQUESTED", "DISMISSED":
+			// Reviews are returned oldest first, so later ones replace earlier ones
+			latest[review.GetUser().GetLogin()] = state
+		}
+	}
+
+	approved := false
+	for _, state := range latest {
+		switch state {
+		case "CHANGES_REQUESTED":
+			return ReviewChangesRequested
+		case "APPROVED":
+			approved = true
+		}
+	}
+	if approved {
+		return ReviewApproved
+	}
+	if requested {
+		return ReviewRequested
+	}
+	return ""
+}
+
+// pendingPullRequests returns the repository's open pull requests, oldest first, with
+// their age in days.
+func pendingPullRequests(repo RepositoryData) []PendingPullRequest {
+	pending := make([]PendingPullRequest, 0, len(repo.PullRequests))
+	for _, pr := range repo.PullRequests {
+		pending = append(pending, PendingPullRequest{
+			PullRequestInfo: pr,
+			AgeDays:         int(time.Since(pr.CreatedAt).Hours() / 24),
+		})
+	}
+	slices.SortStableFunc(pending, func(a, b PendingPullRequest) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return pending
+}
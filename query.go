@@ -0,0 +1,380 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Output formats for -format
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatPDF   = "pdf"
+)
+
+// queryFields are the repository fields a -query expression can refer to, read from the
+// same summary that the JSON API publishes
+var queryFields = map[string]func(APIRepoSummary) any{
+	"name":                   func(r APIRepoSummary) any { return r.Name },
+	"commits":                func(r APIRepoSummary) any { return r.UnreleasedCommits },
+	"unreleased_commits":     func(r APIRepoSummary) any { return r.UnreleasedCommits },
+	"dependency_updates":     func(r APIRepoSummary) any { return r.DependencyUpdates },
+	"breaking_changes":       func(r APIRepoSummary) any { return r.BreakingChanges },
+	"security_fixes":         func(r APIRepoSummary) any { return r.SecurityFixes },
+	"security_fix_days":      func(r APIRepoSummary) any { return r.SecurityFixDays },
+	"open_alerts":            func(r APIRepoSummary) any { return r.OpenAlerts },
+	"unreleased_alert_fixes": func(r APIRepoSummary) any { return r.UnreleasedAlertFixes },
+	"open_prs":               func(r APIRepoSummary) any { return r.OpenPullRequests },
+	"days_behind":            func(r APIRepoSummary) any { return r.DaysBehind },
+	"days_since_release":     func(r APIRepoSummary) any { return r.DaysSinceRelease },
+	"baseline_type":          func(r APIRepoSummary) any { return r.BaselineType },
+	"baseline_tag":           func(r APIRepoSummary) any { return r.BaselineTag },
+	"default_branch":         func(r APIRepoSummary) any { return r.DefaultBranch },
+	"language":               func(r APIRepoSummary) any { return r.Language },
+	"topics":                 func(r APIRepoSummary) any { return r.Topics },
+	"stars":                  func(r APIRepoSummary) any { return r.Stars },
+	"forks":                  func(r APIRepoSummary) any { return r.Forks },
+	"open_issues":            func(r APIRepoSummary) any { return r.OpenIssues },
+	"impact":                 func(r APIRepoSummary) any { return r.Impact },
+	"license":                func(r APIRepoSummary) any { return r.License },
+	"behind_by":              func(r APIRepoSummary) any { return r.BehindBy },
+	"archived":               func(r APIRepoSummary) any { return r.Archived },
+	"deprecated":             func(r APIRepoSummary) any { return r.Deprecated },
+	"never_released":         func(r APIRepoSummary) any { return r.NeverReleased },
+}
+
+// queryExpr is a parsed -query expression
+type queryExpr interface {
+	eval(r APIRepoSummary) (bool, error)
+}
+
+type queryAnd struct{ left, right queryExpr }
+type queryOr struct{ left, right queryExpr }
+type queryNot struct{ expr queryExpr }
+
+// queryCompare compares a field with a literal, or tests a boolean field when op is empty
+type queryCompare struct {
+	field string
+	op    string
+	value string
+}
+
+func (q queryAnd) eval(r APIRepoSummary) (bool, error) {
+	ok, err := q.left.eval(r)
+	if err != nil || !ok {
+		return false, err
+	}
+	return q.right.eval(r)
+}
+
+func (q queryOr) eval(r APIRepoSummary) (bool, error) {
+	ok, err := q.left.eval(r)
+	if err != nil || ok {
+		return ok, err
+	}
+	return q.right.eval(r)
+}
+
+func (q queryNot) eval(r APIRepoSummary) (bool, error) {
+	ok, err := q.expr.eval(r)
+	return !ok, err
+}
+
+func (q queryCompare) eval(r APIRepoSummary) (bool, error) {
+	switch v := queryFields[q.field](r).(type) {
+	case int:
+		n, err := strconv.Atoi(q.value)
+		if err != nil {
+			return false, fmt.Errorf("%s is a number, cannot compare it with %q", q.field, q.value)
+		}
+		return compareOrdered(v, n, q.op)
+	case string:
+		return compareOrdered(strings.ToLower(v), strings.ToLower(q.value), q.op)
+	case bool:
+		if q.op == "" {
+			return v, nil
+		}
+		b, err := strconv.ParseBool(q.value)
+		if err != nil {
+			return false, fmt.Errorf("%s is true or false, cannot compare it with %q", q.field, q.value)
+		}
+		return compareEqual(v == b, q.op, q.field)
+	case *int:
+		n, err := strconv.Atoi(q.value)
+		if err != nil {
+			return false, fmt.Errorf("%s is a number, cannot compare it with %q", q.field, q.value)
+		}
+		// Counts that were not collected match no comparison
+		if v == nil {
+			return false, nil
+		}
+		return compareOrdered(*v, n, q.op)
+	case []string:
+		// List fields match when they contain the value
+		return compareEqual(slices.ContainsFunc(v, func(s string) bool { return strings.EqualFold(s, q.value) }), q.op, q.field)
+	}
+	return false, fmt.Errorf("unsupported field %s", q.field)
+}
+
+func compareOrdered[T int | string](a, b T, op string) (bool, error) {
+	switch op {
+	case "=", "==":
+		return a == b, nil
+	case "!=":
+		return a != b, nil
+	case ">":
+		return a > b, nil
+	case ">=":
+		return a >= b, nil
+	case "<":
+		return a < b, nil
+	case "<=":
+		return a <= b, nil
+	}
+	return false, fmt.Errorf("missing comparison operator")
+}
+
+func compareEqual(equal bool, op, field string) (bool, error) {
+	switch op {
+	case "=", "==":
+		return equal, nil
+	case "!=":
+		return !equal, nil
+	}
+	return false, fmt.Errorf("%s only supports = and !=", field)
+}
+
+// parseQuery parses an expression such as `commits > 10 and days_behind > 30`.
+// Comparisons (=, ==, !=, >, >=, <, <=) can be combined with and, or, not and
+// parentheses; a boolean field on its own, such as `archived`, tests that it is true.
+func parseQuery(input string) (queryExpr, error) {
+	tokens, err := tokenizeQuery(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	return expr, nil
+}
+
+// tokenizeQuery splits a query into words, quoted strings, operators and parentheses.
+func tokenizeQuery(input string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(input); {
+		c, size := utf8.DecodeRuneInString(input[i:])
+		switch {
+		case unicode.IsSpace(c):
+			i += size
+		case c == '(' || c == ')':
+			tokens = append(tokens, string(c))
+			i++
+		case strings.ContainsRune("=!<>", c):
+			j := i + 1
+			if j < len(input) && input[j] == '=' {
+				j++
+			}
+			tokens = append(tokens, input[i:j])
+			i = j
+		case c == '"' || c == '\'':
+			end := strings.IndexRune(input[i+1:], c)
+			if end < 0 {
+				return nil, errors.New("unterminated string")
+			}
+			// Quoted strings keep their quote so they are never mistaken for keywords
+			tokens = append(tokens, input[i:i+end+2])
+			i += end + 2
+		default:
+			j := i
+			for j < len(input) {
+				r, size := utf8.DecodeRuneInString(input[j:])
+				if unicode.IsSpace(r) || strings.ContainsRune("()=!<>\"'", r) {
+					break
+				}
+				j += size
+			}
+			tokens = append(tokens, input[i:j])
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+type queryParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *queryParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *queryParser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *queryParser) parseOr() (queryExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for strings.EqualFold(p.peek(), "or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = queryOr{left, right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (queryExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for strings.EqualFold(p.peek(), "and") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = queryAnd{left, right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseUnary() (queryExpr, error) {
+	switch tok := p.next(); {
+	case tok == "":
+		return nil, errors.New("unexpected end of query")
+	case strings.EqualFold(tok, "not"):
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return queryNot{expr}, nil
+	case tok == "(":
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, errors.New("missing )")
+		}
+		return expr, nil
+	default:
+		field := strings.ToLower(tok)
+		if _, ok := queryFields[field]; !ok {
+			return nil, fmt.Errorf("unknown field %q", tok)
+		}
+		switch op := p.peek(); op {
+		case "=", "==", "!=", ">", ">=", "<", "<=":
+			p.next()
+			value := p.next()
+			if value == "" || strings.ContainsAny(value[:1], "()=!<>") {
+				return nil, fmt.Errorf("missing value after %s %s", tok, op)
+			}
+			if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+				value = value[1 : len(value)-1]
+			}
+			return queryCompare{field: field, op: op, value: value}, nil
+		}
+		if _, ok := queryFields[field](APIRepoSummary{}).(bool); !ok {
+			return nil, fmt.Errorf("%s needs a comparison", tok)
+		}
+		return queryCompare{field: field}, nil
+	}
+}
+
+// runQuery prints the repositories in data/ that match expr as a table or JSON.
+func runQuery(expr, format string) error {
+	query, err := parseQuery(expr)
+	if err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+
+	repos, err := loadAllRepositoryData("data")
+	if err != nil {
+		return err
+	}
+
+	matches := []APIRepoSummary{}
+	for _, repo := range repos {
+		summary := apiRepoSummary(repo)
+		ok, err := query.eval(summary)
+		if err != nil {
+			return fmt.Errorf("%s: %w", repo.Name, err)
+		}
+		if ok {
+			matches = append(matches, summary)
+		}
+	}
+
+	if format == FormatJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(matches)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "REPOSITORY\tBASELINE\tCOMMITS\tDEPS\tDAYS BEHIND\tDAYS SINCE RELEASE")
+	for _, r := range matches {
+		baseline := r.BaselineTag
+		if r.NeverReleased {
+			baseline = "(never released)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\n", r.Name, baseline, r.UnreleasedCommits, r.DependencyUpdates, r.DaysBehind, r.DaysSinceRelease)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%d of %d repositories match\n", len(matches), len(repos))
+	return nil
+}
+
+// loadAllRepositoryData reads every repository data file in dir, sorted by name, with
+// cherry-picked commits split out as they are when generating pages.
+func loadAllRepositoryData(dir string) ([]RepositoryData, error) {
+	files, err := repositoryDataFiles(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data directory: %w", err)
+	}
+
+	var repos []RepositoryData
+	for _, file := range files {
+		repo, err := loadRepositoryData(file)
+		if err != nil {
+			return nil, err
+		}
+		repo.UnreleasedCommits, repo.CherryPickedCommits = splitCherryPicked(repo.UnreleasedCommits)
+		repos = append(repos, repo)
+	}
+	if len(repos) == 0 {
+		return nil, errors.New("no repository JSON files found in data directory. Run with -crawl first")
+	}
+
+	slices.SortFunc(repos, func(a, b RepositoryData) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return repos, nil
+}
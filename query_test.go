@@ -0,0 +1,162 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTokenizeQuery(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"commits > 10", []string{"commits", ">", "10"}},
+		{"commits>=10 and(archived)", []string{"commits", ">=", "10", "and", "(", "archived", ")"}},
+		{"name != 'a b' or name == \"c\"", []string{"name", "!=", "'a b'", "or", "name", "==", `"c"`}},
+		{`language = "and"`, []string{"language", "=", `"and"`}},
+		{"topics = à-la-carte", []string{"topics", "=", "à-la-carte"}},
+		{"  \t", nil},
+	}
+	for _, tt := range tests {
+		got, err := tokenizeQuery(tt.input)
+		if err != nil {
+			t.Errorf("tokenizeQuery(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("tokenizeQuery(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+
+	if _, err := tokenizeQuery(`name = "unterminated`); err == nil {
+		t.Error("tokenizeQuery accepted an unterminated string")
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	repo := APIRepoSummary{
+		Name:              "Example-Repo",
+		UnreleasedCommits: 12,
+		DaysBehind:        40,
+		Archived:          true,
+		Language:          "Go",
+		Topics:            []string{"cli", "à-la-carte"},
+	}
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"commits > 10", true},
+		{"commits > 10 and days_behind > 50", false},
+		{"COMMITS >= 12 AND Days_Behind < 50", true},
+		{"name = example-repo", true},
+		{"name = 'Example-Repo'", true},
+		{`language == "go"`, true},
+		{"language != go", false},
+		{"topics = cli", true},
+		{"topics = à-la-carte", true},
+		{"topics != web", true},
+		{"archived", true},
+		{"not archived", false},
+		{"archived = false", false},
+		{"never_released", false},
+
+		// and binds tighter than or, and not tighter than both
+		{"commits < 5 and days_behind > 30 or archived", true},
+		{"archived or commits < 5 and days_behind > 100", true},
+		{"commits < 5 and (days_behind > 30 or archived)", false},
+		{"not archived or commits > 10", true},
+		{"not (archived or commits > 10)", false},
+		{"not not archived", true},
+	}
+	for _, tt := range tests {
+		expr, err := parseQuery(tt.query)
+		if err != nil {
+			t.Errorf("parseQuery(%q) returned error: %v", tt.query, err)
+			continue
+		}
+		got, err := expr.eval(repo)
+		if err != nil {
+			t.Errorf("eval(%q) returned error: %v", tt.query, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("eval(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	queries := []string{
+		"",
+		"   ",
+		"stars_count > 5",
+		"commits",
+		"commits >",
+		"commits > )",
+		"commits > >",
+		"commits > = 5",
+		"(commits > 5",
+		"commits > 5)",
+		"()",
+		"commits > 5 and",
+		"or commits > 5",
+		"commits > 5 days_behind > 3",
+		"not",
+		`name = "open`,
+		"= 5",
+		"'commits' > 5",
+	}
+	for _, query := range queries {
+		if _, err := parseQuery(query); err == nil {
+			t.Errorf("parseQuery(%q) returned no error", query)
+		}
+	}
+}
+
+func TestQueryEvalErrors(t *testing.T) {
+	queries := []string{
+		"commits > many",
+		"archived = maybe",
+		"archived > true",
+		"topics > cli",
+		"open_prs > many",
+	}
+	for _, query := range queries {
+		expr, err := parseQuery(query)
+		if err != nil {
+			t.Errorf("parseQuery(%q) returned error: %v", query, err)
+			continue
+		}
+		if _, err := expr.eval(APIRepoSummary{}); err == nil {
+			t.Errorf("eval(%q) returned no error", query)
+		}
+	}
+}
+
+func TestQueryUncountedOpenPullRequests(t *testing.T) {
+	three := 3
+	counted := APIRepoSummary{OpenPullRequests: &three}
+	uncounted := APIRepoSummary{}
+	tests := []struct {
+		query              string
+		counted, uncounted bool
+	}{
+		{"open_prs > 0", true, false},
+		{"open_prs = 0", false, false},
+		{"open_prs <= 3", true, false},
+		{"not open_prs > 0", false, true},
+	}
+	for _, tt := range tests {
+		expr, err := parseQuery(tt.query)
+		if err != nil {
+			t.Fatalf("parseQuery(%q) returned error: %v", tt.query, err)
+		}
+		if got, err := expr.eval(counted); err != nil || got != tt.counted {
+			t.Errorf("eval(%q) with 3 open pull requests = %v, %v, want %v", tt.query, got, err, tt.counted)
+		}
+		if got, err := expr.eval(uncounted); err != nil || got != tt.uncounted {
+			t.Errorf("eval(%q) without a count = %v, %v, want %v", tt.query, got, err, tt.uncounted)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// rateLimitReportFile is the crawl's API usage report, written to the data directory
+const rateLimitReportFile = "rate-limit.json"
+
+// apiUsage is an http.RoundTripper that counts the GitHub API requests made by the crawl,
+// attributing each to the repository being processed, and remembers the rate limit
+// reported by the most recent response
+type apiUsage struct {
+	base http.RoundTripper
+	// budget is the most requests allowed, or 0 for no limit
+	budget int
+
+	mu        sync.Mutex
+	total     int
+	refused   int
+	repo      string
+	perRepo   map[string]int
+	limit     int
+	remaining int
+	reset     time.Time
+}
+
+// newAPIUsage wraps base, or http.DefaultTransport when base is nil.
+func newAPIUsage(base http.RoundTripper) *apiUsage {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &apiUsage{base: base, perRepo: make(map[string]int), remaining: -1}
+}
+
+func (u *apiUsage) RoundTrip(req *http.Request) (*http.Response, error) {
+	u.mu.Lock()
+	if u.budget > 0 && u.total >= u.budget {
+		u.refused++
+		u.mu.Unlock()
+		// A RoundTripper must close the request body, even on error
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, errAPIBudget
+	}
+	u.total++
+	if u.repo != "" {
+		u.perRepo[u.repo]++
+	}
+	u.mu.Unlock()
+
+	resp, err := u.base.RoundTrip(req)
+	if err == nil {
+		u.recordLimit(resp.Header)
+	}
+	return resp, err
+}
+
+// recordLimit keeps the X-RateLimit headers of a response.
+func (u *apiUsage) recordLimit(header http.Header) {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	limit, _ := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	reset, _ := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.remaining = remaining
+	u.limit = limit
+	if reset > 0 {
+		u.reset = time.Unix(reset, 0).UTC()
+	}
+}
+
+// startRepo attributes the following requests to repo; an empty name attributes them
+// to the crawl as a whole.
+func (u *apiUsage) startRepo(repo string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.repo = repo
+}
+
+// exhausted reports whether the budget has been used up.
+func (u *apiUsage) exhausted() bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.budget > 0 && u.total >= u.budget
+}
+
+// refusedRequests returns how many requests were refused because the budget was used up.
+func (u *apiUsage) refusedRequests() int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.refused
+}
+
+// RateLimitReport is how much of the API rate limit a crawl consumed
+type RateLimitReport struct {
+	Requests  int            `json:"requests"`
+	Limit     int            `json:"limit,omitempty"`
+	Remaining int            `json:"remaining"`
+	Reset     time.Time      `json:"reset,omitzero"`
+	Repos     []RepoRequests `json:"repos"`
+}
+
+// RepoRequests is how many API requests the crawl made for one repository
+type RepoRequests struct {
+	Name     string `json:"name"`
+	Requests int    `json:"requests"`
+}
+
+// report returns the usage so far, with the repositories that needed the most requests first.
+func (u *apiUsage) report() RateLimitReport {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	r := RateLimitReport{
+		Requests:  u.total,
+		Limit:     u.limit,
+		Remaining: u.remaining,
+		Reset:     u.reset,
+		Repos:     make([]RepoRequests, 0, len(u.perRepo)),
+	}
+	for name, n := range u.perRepo {
+		r.Repos = append(r.Repos, RepoRequests{Name: name, Requests: n})
+	}
+	slices.SortFunc(r.Repos, func(a, b RepoRequests) int {
+		if c := cmp.Compare(b.Requests, a.Requests); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return r
+}
+
+// reportAPIUsage prints the crawl's API usage and writes it to dir.
+func reportAPIUsage(dir string, usage *apiUsage) RateLimitReport {
+	report := usage.report()
+	printRateLimitReport(report)
+	if err := writeJSON(filepath.Join(dir, rateLimitReportFile), report); err != nil {
+		fmt.Printf("⚠️  Failed to write API usage report: %v\n", err)
+	}
+	return report
+}
+
+// printRateLimitReport summarizes the report with the repositories that used the most requests.
+func printRateLimitReport(r RateLimitReport) {
+	fmt.Printf("\n📊 API requests: %d", r.Requests)
+	if r.Remaining >= 0 {
+		fmt.Printf(", %d of %d remaining", r.Remaining, r.Limit)
+		if !r.Reset.IsZero() {
+			fmt.Printf(" (resets %s)", r.Reset.Local().Format("15:04"))
+		}
+	}
+	fmt.Println()
+
+	for i, repo := range r.Repos {
+		if i == 5 {
+			fmt.Printf("   ... %d more in %s\n", len(r.Repos)-i, rateLimitReportFile)
+			break
+		}
+		fmt.Printf("   %-30s %d\n", repo.Name, repo.Requests)
+	}
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// Package registries checked by -registries
+const (
+	RegistryNPM    = "npm"
+	RegistryPyPI   = "pypi"
+	RegistryCrates = "crates"
+)
+
+// registryClient is used for all package registry requests
+var registryClient = sync.OnceValue(registryHTTPClient)
+
+// PackageInfo is a package the repository publishes, with the latest version on its
+// registry and how far that version trails the default branch
+type PackageInfo struct {
+	Registry      string    `json:"registry"`
+	Name          string    `json:"name"`
+	Version       string    `json:"version,omitempty"`
+	PublishedAt   time.Time `json:"published_at,omitzero"`
+	Tag           string    `json:"tag,omitempty"`
+	CommitsBehind int       `json:"commits_behind"`
+	// UnpublishedTag is a release tag newer than the published version, e.g. because
+	// publishing failed after tagging
+	UnpublishedTag string `json:"unpublished_tag,omitempty"`
+}
+
+// packageManifest is a manifest file naming a package and the registry it is published to
+type packageManifest struct {
+	path     string
+	registry string
+	name     func(content string) string
+}
+
+// packageManifests are read from the root of the default branch
+var packageManifests = []packageManifest{
+	{"package.json", RegistryNPM, npmPackageName},
+	{"pyproject.toml", RegistryPyPI, func(s string) string {
+		return cmp.Or(sectionValue(s, "project", "name"), sectionValue(s, "tool.poetry", "name"))
+	}},
+	{"setup.cfg", RegistryPyPI, func(s string) string { return sectionValue(s, "metadata", "name") }},
+	{"Cargo.toml", RegistryCrates, func(s string) string { return sectionValue(s, "package", "name") }},
+}
+
+// registryName returns the display name of a registry.
+func registryName(registry string) string {
+	switch registry {
+	case RegistryPyPI:
+		return "PyPI"
+	case RegistryCrates:
+		return "crates.io"
+	}
+	return registry
+}
+
+// PackageURL links to the package's page on its registry.
+func (p PackageInfo) PackageURL() string {
+	switch p.Registry {
+	case RegistryNPM:
+		return "https://www.npmjs.com/package/" + p.Name
+	case RegistryPyPI:
+		return "https://pypi.org/project/" + p.Name + "/"
+	case RegistryCrates:
+		return "https://crates.io/crates/" + p.Name
+	}
+	return ""
+}
+
+// checkPackages finds the packages published from the repository and compares the
+// latest version on each registry with branch. baselineTag is the latest release, which
+// is reported when it is newer than the published version.
+func checkPackages(ctx context.Context, client *github.Client, owner, repo, branch, baselineTag string) ([]PackageInfo, error) {
+	var packages []PackageInfo
+	seen := make(map[string]bool)
+	for _, manifest := range packageManifests {
+		name, err := readManifestName(ctx, client, owner, repo, branch, manifest)
+		if err != nil {
+			return packages, err
+		}
+		if name == "" || seen[manifest.registry+"/"+name] {
+			continue
+		}
+		seen[manifest.registry+"/"+name] = true
+
+		info := PackageInfo{Registry: manifest.registry, Name: name}
+		info.Version, info.PublishedAt, err = fetchRegistryLatest(ctx, manifest.registry, name)
+		if err != nil {
+			return packages, err
+		}
+		if info.Version == "" {
+			// Not published (yet), e.g. an internal package
+			continue
+		}
+
+		tag, _, err := findVersionTag(ctx, client, owner, repo, info.Version)
+		if err != nil {
+			return packages, err
+		}
+		if tag != "" {
+			info.Tag = tag
+			comp, _, err := client.Repositories.CompareCommits(ctx, owner, repo, tag, branch, &github.ListOptions{PerPage: 1})
+			if err != nil {
+				return packages, fmt.Errorf("failed to compare %s...%s: %w", tag, branch, err)
+			}
+			info.CommitsBehind = comp.GetAheadBy()
+		}
+
+		published, ok1 := parseSemver(info.Version)
+		released, ok2 := parseSemver(baselineTag)
+		if ok1 && ok2 && released.Compare(published) > 0 {
+			info.UnpublishedTag = baselineTag
+		}
+		packages = append(packages, info)
+	}
+	return packages, nil
+}
+
+// readManifestName returns the package name in the manifest, or "" when the repository
+// has no such manifest.
+func readManifestName(ctx context.Context, client *github.Client, owner, repo, branch string, manifest packageManifest) (string, error) {
+	var opts *github.RepositoryContentGetOptions
+	if branch != "" {
+		opts = &github.RepositoryContentGetOptions{Ref: branch}
+	}
+	file, _, resp, err := client.Repositories.GetContents(ctx, owner, repo, manifest.path, opts)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return "", nil
+		}
+		return "", err
+	}
+	if file == nil {
+		return "", nil
+	}
+	content, err := file.GetContent()
+	if err != nil {
+		return "", err
+	}
+	return manifest.name(content), nil
+}
+
+// findVersionTag returns the tag for a version, named with or without a "v" prefix, and
+// its commit, or "" when there is no such tag.
+func findVersionTag(ctx context.Context, client *github.Client, owner, repo, version string) (string, *github.RepositoryCommit, error) {
+	candidates := []string{"v" + version, version}
+	if trimmed, ok := strings.CutPrefix(version, "v"); ok {
+		candidates = []string{version, trimmed}
+	}
+	for _, tag := range candidates {
+		commit, resp, err := client.Repositories.GetCommit(ctx, owner, repo, "refs/tags/"+tag, nil)
+		if err != nil {
+			if resp != nil && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnprocessableEntity) {
+				continue
+			}
+			return "", nil, err
+		}
+		return tag, commit, nil
+	}
+	return "", nil, nil
+}
+
+// npmPackageName returns the name in a package.json, or "" for a private package.
+func npmPackageName(content string) string {
+	var pkg struct {
+		Name    string `json:"name"`
+		Private bool   `json:"private"`
+	}
+	if json.Unmarshal([]byte(content), &pkg) != nil || pkg.Private {
+		return ""
+	}
+	return pkg.Name
+}
+
+// sectionValue returns a key of a TOML table or INI section, such as Cargo.toml's
+// [package] name, which is enough to read a package name without a full parser.
+func sectionValue(content, section, key string) string {
+	current := ""
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			current = strings.TrimSpace(strings.Trim(line, "[]"))
+			continue
+		}
+		if current != section {
+			continue
+		}
+		k, v, ok := strings.Cut(line, "=")
+		if ok && strings.TrimSpace(k) == key {
+			return strings.Trim(strings.TrimSpace(v), `"'`)
+		}
+	}
+	return ""
+}
+
+// fetchRegistryLatest returns the latest stable version of a package and when it was
+// published, or an empty version when the registry does not know the package.
+func fetchRegistryLatest(ctx context.Context, registry, name string) (string, time.Time, error) {
+	switch registry {
+	case RegistryNPM:
+		var doc struct {
+			DistTags map[string]string    `json:"dist-tags"`
+			Time     map[string]time.Time `json:"time"`
+		}
+		found, err := getRegistryJSON(ctx, "https://registry.npmjs.org/"+strings.Replace(name, "/", "%2F", 1), &doc)
+		if !found || err != nil {
+			return "", time.Time{}, err
+		}
+		version := doc.DistTags["latest"]
+		return version, doc.Time[version], nil
+
+	case RegistryPyPI:
+		var doc struct {
+			Info struct {
+				Version string `json:"version"`
+			} `json:"info"`
+			URLs []struct {
+				UploadTime time.Time `json:"upload_time_iso_8601"`
+			} `json:"urls"`
+		}
+		found, err := getRegistryJSON(ctx, "https://pypi.org/pypi/"+url.PathEscape(name)+"/json", &doc)
+		if !found || err != nil {
+			return "", time.Time{}, err
+		}
+		var published time.Time
+		if len(doc.URLs) > 0 {
+			published = doc.URLs[0].UploadTime
+		}
+		return doc.Info.Version, published, nil
+
+	case RegistryCrates:
+		var doc struct {
+			Crate struct {
+				MaxStableVersion string `json:"max_stable_version"`
+				MaxVersion       string `json:"max_version"`
+			} `json:"crate"`
+			Versions []struct {
+				Num       string    `json:"num"`
+				CreatedAt time.Time `json:"created_at"`
+			} `json:"versions"`
+		}
+		found, err := getRegistryJSON(ctx, "https://crates.io/api/v1/crates/"+url.PathEscape(name), &doc)
+		if !found || err != nil {
+			return "", time.Time{}, err
+		}
+		version := cmp.Or(doc.Crate.MaxStableVersion, doc.Crate.MaxVersion)
+		for _, v := range doc.Versions {
+			if v.Num == version {
+				return version, v.CreatedAt, nil
+			}
+		}
+		return version, time.Time{}, nil
+	}
+	return "", time.Time{}, fmt.Errorf("unknown registry %q", registry)
+}
+
+// getRegistryJSON decodes the JSON document at url into v. It reports false when the
+// registry has no such package.
+func getRegistryJSON(ctx context.Context, url string, v any) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, err
+	}
+	// crates.io rejects requests without a descriptive User-Agent
+	req.Header.Set("User-Agent", "unreleasedcommits/"+version+" (https://github.com/UnitVectorY-Labs/unreleasedcommits)")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := registryClient().Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("%s returned %s", req.URL.Host, resp.Status)
+	}
+	return true, json.NewDecoder(resp.Body).Decode(v)
+}
+
+// unpublishedTag returns a release tag that one of the packages has not been published
+// for, or "" when every package is up to date.
+func unpublishedTag(packages []PackageInfo) string {
+	for _, p := range packages {
+		if p.UnpublishedTag != "" {
+			return p.UnpublishedTag
+		}
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// migrateRenamedData finds data files left under a repository's old name after it was
+// renamed and moves them to the new name, updating the stored owner, name and URL so
+// the repository does not show up twice. Files recording the repository ID are matched
+// directly or looked up by ID; older files are resolved by following GitHub's redirect
+// from the old name. Files of repositories transferred to another owner are reported,
+// and deleted only when prune is set, as with other stale data files.
+func migrateRenamedData(ctx context.Context, client *github.Client, dir, owner string, repos []*github.Repository, prune bool) {
+	stale, err := staleDataFiles(dir, repos)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to check for renamed repositories: %v\n", err)
+		return
+	}
+
+	byID := make(map[int64]*github.Repository, len(repos))
+	byName := make(map[string]*github.Repository, len(repos))
+	for _, repo := range repos {
+		byID[repo.GetID()] = repo
+		byName[repo.GetName()] = repo
+	}
+
+	for _, file := range stale {
+		data, err := loadRepositoryData(file)
+		if err != nil {
+			continue
+		}
+		oldName := strings.TrimSuffix(filepath.Base(file), ".json")
+
+		current := byID[data.RepoID]
+		if current == nil {
+			// The API redirects requests for a renamed or transferred repository to its new location
+			var moved *github.Repository
+			if data.RepoID != 0 {
+				moved, _, err = client.Repositories.GetByID(ctx, data.RepoID)
+			} else {
+				moved, _, err = client.Repositories.Get(ctx, owner, oldName)
+			}
+			if err != nil {
+				continue
+			}
+			if !strings.EqualFold(moved.GetOwner().GetLogin(), owner) {
+				if !prune {
+					fmt.Printf("  ↪️  %s was transferred to %s (use -prune to remove %s)\n", oldName, moved.GetFullName(), file)
+					continue
+				}
+				if err := os.Remove(file); err != nil {
+					fmt.Printf("  ❌ Error removing %s: %v\n", file, err)
+					continue
+				}
+				fmt.Printf("  ↪️  %s was transferred to %s, removed %s\n", oldName, moved.GetFullName(), file)
+				continue
+			}
+			current = byName[moved.GetName()]
+		}
+		if current == nil || current.GetName() == oldName {
+			continue
+		}
+
+		newFile := filepath.Join(dir, current.GetName()+".json")
+		if _, err := os.Stat(newFile); err == nil {
+			// The new name was already crawled, so the old file is only a duplicate
+			if err := os.Remove(file); err != nil {
+				fmt.Printf("  ❌ Error removing %s: %v\n", file, err)
+				continue
+			}
+			fmt.Printf("  ↪️  %s was renamed to %s, removed duplicate %s\n", oldName, current.GetName(), file)
+			continue
+		}
+
+		data.RepoID = current.GetID()
+		data.Owner = current.GetOwner().GetLogin()
+		data.Name = current.GetName()
+		data.RepositoryURL = current.GetHTMLURL()
+		if err := writeJSON(newFile, data); err != nil {
+			fmt.Printf("  ❌ Error writing %s: %v\n", newFile, err)
+			continue
+		}
+		if err := os.Remove(file); err != nil {
+			fmt.Printf("  ❌ Error removing %s: %v\n", file, err)
+			continue
+		}
+		fmt.Printf("  ↪️  %s was renamed to %s, moved %s to %s\n", oldName, current.GetName(), file, newFile)
+	}
+}
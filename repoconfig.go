@@ -0,0 +1,286 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// repoConfigPath is where a repository can configure how it is measured, read with
+// -repo-config
+const repoConfigPath = ".github/unreleasedcommits.yml"
+
+// fetchRepoConfig reads repoConfigPath from the repository's default branch. It returns
+// nil when the repository has no such file.
+func fetchRepoConfig(ctx context.Context, client *github.Client, owner, repo string) (*RepoConfig, error) {
+	file, _, resp, err := client.Repositories.GetContents(ctx, owner, repo, repoConfigPath, nil)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if file == nil {
+		return nil, nil
+	}
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := parseRepoConfig(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", repoConfigPath, err)
+	}
+	if err := validateRepoConfig(cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", repoConfigPath, err)
+	}
+	return &cfg, nil
+}
+
+// parseRepoConfig parses the small subset of YAML a repository config needs: top-level
+// "key: value" pairs whose values are scalars, flow lists ([a, b]) or block lists of
+// "- item" lines, with optional quoting and # comments.
+func parseRepoConfig(content string) (RepoConfig, error) {
+	var cfg RepoConfig
+	var listKey string
+	var list *[]string
+
+	for i, raw := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		line := strings.TrimRight(stripYAMLComment(raw), " \t")
+		if strings.TrimSpace(line) == "" || line == "---" {
+			continue
+		}
+
+		if item, ok := strings.CutPrefix(strings.TrimSpace(line), "-"); ok {
+			if list == nil {
+				return cfg, fmt.Errorf("line %d: list item outside of a list", i+1)
+			}
+			value, err := yamlScalar(item)
+			if err != nil {
+				return cfg, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			*list = append(*list, value)
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			return cfg, fmt.Errorf("line %d: unexpected indentation", i+1)
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return cfg, fmt.Errorf("line %d: expected key: value", i+1)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		list, listKey = nil, ""
+
+		var target *string
+		switch key {
+		case "branch":
+			target = &cfg.Branch
+		case "compare_branch":
+			target = &cfg.CompareBranch
+		case "tag_pattern":
+			target = &cfg.TagPattern
+		case "release":
+			target = &cfg.Release
+		case "marker_file":
+			target = &cfg.MarkerFile
+		case "image":
+			target = &cfg.Image
+		case "formula":
+			target = &cfg.Formula
+		case "ignore":
+			list, listKey = &cfg.Ignore, key
+		case "paths":
+			list, listKey = &cfg.Paths, key
+		default:
+			return cfg, fmt.Errorf("line %d: unknown setting %q", i+1, key)
+		}
+
+		if target != nil {
+			s, err := yamlScalar(value)
+			if err != nil {
+				return cfg, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			*target = s
+			continue
+		}
+		if value == "" {
+			continue
+		}
+		if !strings.HasPrefix(value, "[") || !strings.HasSuffix(value, "]") {
+			return cfg, fmt.Errorf("line %d: %s must be a list", i+1, listKey)
+		}
+		for _, item := range strings.Split(strings.TrimSuffix(strings.TrimPrefix(value, "["), "]"), ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			s, err := yamlScalar(item)
+			if err != nil {
+				return cfg, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			*list = append(*list, s)
+		}
+		list = nil
+	}
+	return cfg, nil
+}
+
+// stripYAMLComment removes a # comment that starts a line or follows whitespace, unless
+// it is inside quotes.
+func stripYAMLComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// yamlScalar unquotes a single- or double-quoted YAML scalar; plain scalars are returned
+// as they are.
+func yamlScalar(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'':
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	case len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"':
+		unquoted, err := strconv.Unquote(s)
+		if err != nil {
+			return "", fmt.Errorf("invalid quoted string %s", s)
+		}
+		return unquoted, nil
+	}
+	return s, nil
+}
+
+// validateRepoConfig checks the patterns of a repository's settings.
+func validateRepoConfig(cfg RepoConfig) error {
+	if cfg.Release != "" && !validBaselineMode(cfg.Release) {
+		return fmt.Errorf("release: unknown strategy %q", cfg.Release)
+	}
+	if _, err := regexp.Compile(cfg.TagPattern); err != nil {
+		return fmt.Errorf("tag_pattern: %w", err)
+	}
+	for _, pattern := range cfg.Ignore {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("ignore: %w", err)
+		}
+	}
+	if err := validateChannels(cfg.Channels); err != nil {
+		return fmt.Errorf("channels: %w", err)
+	}
+	return nil
+}
+
+// mergeRepoConfig adds the settings from a repository's own file to the central config.
+// Settings in the central config's repos section take precedence, so operators can still
+// override what a repository asks for.
+func (c *Config) mergeRepoConfig(name string, file RepoConfig) {
+	repo := c.Repos[name]
+	if repo.Branch == "" {
+		repo.Branch = file.Branch
+	}
+	if repo.CompareBranch == "" {
+		repo.CompareBranch = file.CompareBranch
+	}
+	if repo.TagPattern == "" {
+		repo.TagPattern = file.TagPattern
+	}
+	if repo.Release == "" {
+		repo.Release = file.Release
+	}
+	if repo.MarkerFile == "" {
+		repo.MarkerFile = file.MarkerFile
+	}
+	if repo.Image == "" {
+		repo.Image = file.Image
+	}
+	if repo.Formula == "" {
+		repo.Formula = file.Formula
+	}
+	if repo.Ignore == nil {
+		repo.Ignore = file.Ignore
+	}
+	if repo.Paths == nil {
+		repo.Paths = file.Paths
+	}
+	if c.Repos == nil {
+		c.Repos = make(map[string]RepoConfig)
+	}
+	c.Repos[name] = repo
+}
+
+// dropIgnoredCommits removes the commits whose message matches one of the ignore
+// patterns, returning the rest and how many were dropped.
+func dropIgnoredCommits(commits []CommitInfo, patterns []string) ([]CommitInfo, int) {
+	if len(patterns) == 0 {
+		return commits, 0
+	}
+	compiled := make([]*regexp.Regexp, len(patterns))
+	for i, p := range patterns {
+		// Patterns are checked when the config is loaded
+		compiled[i] = regexp.MustCompile(p)
+	}
+	kept := slices.DeleteFunc(slices.Clone(commits), func(c CommitInfo) bool {
+		return slices.ContainsFunc(compiled, func(re *regexp.Regexp) bool { return re.MatchString(c.Message) })
+	})
+	return kept, len(commits) - len(kept)
+}
+
+// commitsInPaths keeps the commits that touch one of the paths, for repositories that
+// only want part of a monorepo measured. It lists the commits on branch touching each
+// path, back to the oldest of the commits.
+func commitsInPaths(ctx context.Context, client *github.Client, owner, repo, branch string, paths []string, commits []CommitInfo) ([]CommitInfo, error) {
+	if len(commits) == 0 {
+		return commits, nil
+	}
+	since := commits[0].Timestamp
+	for _, c := range commits {
+		if c.Timestamp.Before(since) {
+			since = c.Timestamp
+		}
+	}
+
+	touching := make(map[string]bool)
+	for _, path := range paths {
+		opt := &github.CommitsListOptions{
+			SHA:         branch,
+			Path:        path,
+			Since:       since,
+			ListOptions: github.ListOptions{PerPage: 100},
+		}
+		for {
+			page, resp, err := client.Repositories.ListCommits(ctx, owner, repo, opt)
+			if err != nil {
+				return nil, err
+			}
+			for _, c := range page {
+				touching[c.GetSHA()] = true
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			opt.Page = resp.NextPage
+		}
+	}
+
+	return slices.DeleteFunc(slices.Clone(commits), func(c CommitInfo) bool {
+		return !touching[c.SHA]
+	}), nil
+}
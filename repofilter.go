@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+// Files in the working directory that exclude repositories from every crawl, or limit
+// it to the listed ones
+const (
+	ignoreFile = ".unreleasedignore"
+	allowFile  = ".unreleasedallow"
+)
+
+// repoFilter holds the patterns of ignoreFile and allowFile. A pattern is a glob
+// matched against the repository name, or against owner/name when it contains a slash.
+type repoFilter struct {
+	ignore []string
+	allow  []string
+}
+
+// loadRepoFilter reads ignoreFile and allowFile from the working directory. It returns
+// nil when neither exists.
+func loadRepoFilter() (*repoFilter, error) {
+	ignore, err := readPatternFile(ignoreFile)
+	if err != nil {
+		return nil, err
+	}
+	allow, err := readPatternFile(allowFile)
+	if err != nil {
+		return nil, err
+	}
+	if ignore == nil && allow == nil {
+		return nil, nil
+	}
+	return &repoFilter{ignore: ignore, allow: allow}, nil
+}
+
+// readPatternFile returns the patterns of a filter file, skipping blank lines and
+// comments, or nil when the file does not exist.
+func readPatternFile(name string) ([]string, error) {
+	f, err := os.Open(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	patterns := []string{}
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if _, err := path.Match(text, ""); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", name, line, err)
+		}
+		patterns = append(patterns, strings.ToLower(text))
+	}
+	return patterns, scanner.Err()
+}
+
+// skips reports why owner/name is not crawled, or "" when it is. Repositories must
+// match the allow list, when there is one, and must not match the ignore list.
+func (f *repoFilter) skips(owner, name string) string {
+	if f == nil {
+		return ""
+	}
+	if f.allow != nil && !matchesAny(f.allow, owner, name) {
+		return "not in " + allowFile
+	}
+	if matchesAny(f.ignore, owner, name) {
+		return "listed in " + ignoreFile
+	}
+	return ""
+}
+
+// matchesAny reports whether owner/name matches any of patterns, ignoring case.
+func matchesAny(patterns []string, owner, name string) bool {
+	name = strings.ToLower(name)
+	full := strings.ToLower(owner) + "/" + name
+	for _, p := range patterns {
+		p = strings.ToLower(p)
+		target := name
+		if strings.Contains(p, "/") {
+			target = full
+		}
+		if ok, _ := path.Match(p, target); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func TestMatchesAny(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		name     string
+		want     bool
+	}{
+		{[]string{"MyRepo"}, "MyRepo", true},
+		{[]string{"MyRepo"}, "myrepo", true},
+		{[]string{"myrepo"}, "MyRepo", true},
+		{[]string{"My*"}, "myRepo", true},
+		{[]string{"Acme/MyRepo"}, "MyRepo", true},
+		{[]string{"ACME/*"}, "anything", true},
+		{[]string{"other/MyRepo"}, "MyRepo", false},
+		{[]string{"MyRepo"}, "MyRepo2", false},
+		{[]string{"docs", "My?epo"}, "MyRepo", true},
+		{nil, "MyRepo", false},
+	}
+	for _, tt := range tests {
+		if got := matchesAny(tt.patterns, "acme", tt.name); got != tt.want {
+			t.Errorf("matchesAny(%q, acme, %q) = %v, want %v", tt.patterns, tt.name, got, tt.want)
+		}
+	}
+}
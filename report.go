@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// reportFile is the PDF report written by -generate -format pdf
+const reportFile = "report.pdf"
+
+// generatePDFReport writes a paginated report of the unreleased commits for release
+// planning: a table of the released repositories with the most unreleased commits
+// first, followed by the commits waiting in each. maxCommits limits the commits listed
+// per repository, as on the repository pages.
+func generatePDFReport(outputDir string, repos []RepositoryData, lastUpdated string, maxCommits int) error {
+	var released []RepositoryData
+	totalCommits := 0
+	reposWithCommits := 0
+	for _, repo := range repos {
+		if repo.NeverReleased {
+			continue
+		}
+		released = append(released, repo)
+		totalCommits += len(repo.UnreleasedCommits)
+		if len(repo.UnreleasedCommits) > 0 {
+			reposWithCommits++
+		}
+	}
+	sort.SliceStable(released, func(i, j int) bool {
+		return len(released[i].UnreleasedCommits) > len(released[j].UnreleasedCommits)
+	})
+
+	doc := newPDFDocument()
+	doc.title(fmt.Sprintf("Unreleased Commits: %s", repos[0].Owner))
+	if lastUpdated != "" {
+		doc.line(fmt.Sprintf("Data as of %s", lastUpdated))
+	}
+	doc.line(fmt.Sprintf("%s across %d of %d repositories", pluralize(totalCommits, "unreleased commit"), reposWithCommits, len(released)))
+	if never := len(repos) - len(released); never > 0 {
+		doc.line(fmt.Sprintf("%d never released", never))
+	}
+
+	doc.heading("Repositories")
+	row := "%-30s %7s %7s %7s  %-14s %-10s"
+	doc.line(fmt.Sprintf(row, "Repository", "Commits", "Behind", "Since", "Release", "Next"))
+	for _, repo := range released {
+		daysSinceRelease, _ := repoAges(repo)
+		doc.line(fmt.Sprintf(row,
+			truncateRunes(repo.Name, 30),
+			fmt.Sprint(len(repo.UnreleasedCommits)),
+			fmt.Sprintf("%dd", repoDaysBehind(repo)),
+			fmt.Sprintf("%dd", daysSinceRelease),
+			truncateRunes(repo.LatestReleaseTag, 14),
+			truncateRunes(suggestNextVersion(repo.LatestReleaseTag, repo.UnreleasedCommits), 10)))
+	}
+
+	for _, repo := range released {
+		commits := repo.UnreleasedCommits
+		if len(commits) == 0 {
+			continue
+		}
+		doc.heading(fmt.Sprintf("%s (%s)", repo.Name, pluralize(len(commits), "unreleased commit")))
+		doc.line(fmt.Sprintf("Since %s on %s", repo.LatestReleaseTag, repo.LatestReleaseTime.Format(time.DateOnly)))
+		if maxCommits > 0 && len(commits) > maxCommits {
+			commits = commits[:maxCommits]
+		}
+		for _, commit := range commits {
+			sha := commit.SHA
+			if len(sha) > 7 {
+				sha = sha[:7]
+			}
+			doc.line(fmt.Sprintf("%s %s %-16s %s", sha, commit.Timestamp.Format(time.DateOnly), truncateRunes(commit.Author, 16), commitSubject(commit.Message)))
+		}
+		if hidden := len(repo.UnreleasedCommits) - len(commits); hidden > 0 {
+			doc.line(fmt.Sprintf("... and %d more, see %s", hidden, repo.RepositoryURL))
+		}
+	}
+
+	return writeFileAtomic(filepath.Join(outputDir, reportFile), doc.bytes())
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// readRepoList reads the owner/name lines of a -repos-file, or of stdin when path is
+// "-". Blank lines and lines starting with # are ignored. Every repository must belong
+// to the same owner, since a data directory holds a single owner's repositories.
+func readRepoList(path string) (owner string, names []string, err error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return "", nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		o, name, ok := strings.Cut(text, "/")
+		if !ok || o == "" || name == "" || strings.Contains(name, "/") {
+			return "", nil, fmt.Errorf("line %d: %q is not owner/name", line, text)
+		}
+		switch {
+		case owner == "":
+			owner = o
+		case !strings.EqualFold(o, owner):
+			return "", nil, fmt.Errorf("line %d: %s belongs to %s, not %s; crawl each owner into its own data directory (see -compare)", line, text, o, owner)
+		}
+		if !seen[strings.ToLower(name)] {
+			seen[strings.ToLower(name)] = true
+			names = append(names, name)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", nil, err
+	}
+	if len(names) == 0 {
+		return "", nil, errors.New("no repositories listed")
+	}
+	return owner, names, nil
+}
+
+// getListedRepos fetches the listed repositories of owner, skipping those that do not
+// exist or are not accessible, those excluded by filter and, unless includeArchived,
+// archived ones.
+func getListedRepos(ctx context.Context, client *github.Client, owner string, names []string, limit int, includeArchived bool, filter *repoFilter) ([]*github.Repository, error) {
+	var repos []*github.Repository
+	for _, name := range names {
+		if reason := filter.skips(owner, name); reason != "" {
+			fmt.Printf("⏭️  Skipping %s/%s (%s)\n", owner, name, reason)
+			continue
+		}
+		repo, resp, err := client.Repositories.Get(ctx, owner, name)
+		if err != nil {
+			if resp != nil && resp.StatusCode == http.StatusNotFound {
+				fmt.Printf("⚠️  Skipping %s/%s (not found)\n", owner, name)
+				continue
+			}
+			return nil, fmt.Errorf("%s/%s: %w", owner, name, err)
+		}
+		if repo.GetArchived() && !includeArchived {
+			fmt.Printf("⏭️  Skipping %s/%s (archived)\n", owner, name)
+			continue
+		}
+		repos = append(repos, repo)
+		if limit > 0 && len(repos) >= limit {
+			break
+		}
+	}
+	return repos, nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// HistoryRetention controls how long the points of a repository's unreleased commit
+// history are kept: every point for DailyDays, then one point per week until
+// WeeklyDays, after which points are dropped. A period of 0 uses the default and a
+// period of -1 is unlimited
+type HistoryRetention struct {
+	DailyDays  int `json:"daily_days,omitempty"`
+	WeeklyDays int `json:"weekly_days,omitempty"`
+}
+
+// unlimitedRetention is the period that keeps points forever
+const unlimitedRetention = -1
+
+// defaultHistoryRetention keeps daily points for 90 days and weekly points for 2 years
+var defaultHistoryRetention = HistoryRetention{DailyDays: 90, WeeklyDays: 730}
+
+// validateHistoryRetention checks that the retention periods are in order.
+func validateHistoryRetention(r HistoryRetention) error {
+	if r.DailyDays < unlimitedRetention || r.WeeklyDays < unlimitedRetention {
+		return fmt.Errorf("retention periods must be positive, or -1 for unlimited")
+	}
+	r = r.withDefaults()
+	switch {
+	case r.WeeklyDays == unlimitedRetention:
+		return nil
+	case r.DailyDays == unlimitedRetention:
+		return fmt.Errorf("weekly_days must be -1 when daily_days is -1")
+	case r.WeeklyDays < r.DailyDays:
+		return fmt.Errorf("weekly_days (%d) must not be shorter than daily_days (%d)", r.WeeklyDays, r.DailyDays)
+	}
+	return nil
+}
+
+// withDefaults fills in the periods that are not configured.
+func (r HistoryRetention) withDefaults() HistoryRetention {
+	if r.DailyDays == 0 {
+		r.DailyDays = defaultHistoryRetention.DailyDays
+	}
+	if r.WeeklyDays == 0 {
+		r.WeeklyDays = defaultHistoryRetention.WeeklyDays
+	}
+	return r
+}
+
+// compactHistory applies the retention policy to a history, oldest first. Of the
+// points older than DailyDays, the newest of each week is kept, and points older than
+// WeeklyDays are dropped.
+func compactHistory(history []HistoryPoint, r HistoryRetention, now time.Time) []HistoryPoint {
+	r = r.withDefaults()
+	if r.DailyDays == unlimitedRetention {
+		return history
+	}
+	today := truncateToDay(now)
+
+	compacted := make([]HistoryPoint, 0, len(history))
+	var lastWeek time.Time
+	for _, p := range history {
+		age := today.Sub(truncateToDay(p.Date))
+		switch {
+		case r.WeeklyDays != unlimitedRetention && age > time.Duration(r.WeeklyDays)*24*time.Hour:
+			continue
+		case age > time.Duration(r.DailyDays)*24*time.Hour:
+			week := isoWeekStart(p.Date)
+			if n := len(compacted); n > 0 && week.Equal(lastWeek) {
+				// A later point in the same week replaces the earlier one
+				compacted[n-1] = p
+				continue
+			}
+			lastWeek = week
+		}
+		compacted = append(compacted, p)
+	}
+	return compacted
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCompactHistory(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	// A point a day for three years
+	var history []HistoryPoint
+	for i := 3 * 365; i >= 0; i-- {
+		history = append(history, HistoryPoint{Date: now.AddDate(0, 0, -i), UnreleasedCommits: i})
+	}
+	oldest := func(h []HistoryPoint) int { return int(now.Sub(h[0].Date).Hours() / 24) }
+
+	tests := []struct {
+		name       string
+		retention  HistoryRetention
+		wantOldest int
+		wantAll    bool
+	}{
+		{name: "default", retention: HistoryRetention{}, wantOldest: 730},
+		{name: "configured", retention: HistoryRetention{DailyDays: 30, WeeklyDays: 365}, wantOldest: 365},
+		{name: "unlimited weekly", retention: HistoryRetention{WeeklyDays: -1}, wantOldest: 3 * 365},
+		{name: "unlimited", retention: HistoryRetention{DailyDays: -1, WeeklyDays: -1}, wantAll: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := compactHistory(history, tt.retention, now)
+			if tt.wantAll {
+				if len(got) != len(history) {
+					t.Errorf("kept %d of %d points, want all", len(got), len(history))
+				}
+				return
+			}
+			if len(got) >= len(history) {
+				t.Errorf("kept all %d points, want older ones thinned to one a week", len(history))
+			}
+			if o := oldest(got); o > tt.wantOldest || o < tt.wantOldest-7 {
+				t.Errorf("oldest point is %d days old, want about %d", o, tt.wantOldest)
+			}
+		})
+	}
+}
+
+func TestValidateHistoryRetention(t *testing.T) {
+	valid := []HistoryRetention{
+		{},
+		{DailyDays: 30},
+		{DailyDays: 30, WeeklyDays: 30},
+		{WeeklyDays: -1},
+		{DailyDays: -1, WeeklyDays: -1},
+	}
+	for _, r := range valid {
+		if err := validateHistoryRetention(r); err != nil {
+			t.Errorf("validateHistoryRetention(%+v) returned error: %v", r, err)
+		}
+	}
+
+	invalid := []HistoryRetention{
+		{DailyDays: -2},
+		{WeeklyDays: 30},
+		{DailyDays: 60, WeeklyDays: 30},
+		{DailyDays: -1},
+		{DailyDays: -1, WeeklyDays: 365},
+	}
+	for _, r := range invalid {
+		if err := validateHistoryRetention(r); err == nil {
+			t.Errorf("validateHistoryRetention(%+v) returned no error", r)
+		}
+	}
+}
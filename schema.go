@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// currentSchemaVersion is the data file format written by -crawl and expected by -generate.
+// Files written before schema_version existed are treated as version 1.
+const currentSchemaVersion = 2
+
+// migration upgrades a decoded data file from one schema version to the next
+type migration func(data map[string]any) error
+
+// migrations holds the upgrade from version N to N+1, keyed by N
+var migrations = map[int]migration{
+	// Version 1 files predate -baseline, so every one of them was compared against a release
+	1: func(data map[string]any) error {
+		if _, ok := data["baseline_type"]; !ok && data["never_released"] != true {
+			data["baseline_type"] = BaselineRelease
+		}
+		return nil
+	},
+}
+
+// SchemaError reports a data file whose schema version does not match this build
+type SchemaError struct {
+	File    string
+	Version int
+}
+
+func (e *SchemaError) Error() string {
+	if e.Version > currentSchemaVersion {
+		return fmt.Sprintf("%s uses schema version %d, which is newer than this build supports (%d); upgrade unreleasedcommits", e.File, e.Version, currentSchemaVersion)
+	}
+	return fmt.Sprintf("%s uses schema version %d but this build expects version %d; run with -migrate to upgrade the data directory", e.File, e.Version, currentSchemaVersion)
+}
+
+// fileSchemaVersion returns the schema version recorded in a decoded data file.
+func fileSchemaVersion(data map[string]any) int {
+	if v, ok := data["schema_version"].(float64); ok {
+		return int(v)
+	}
+	return 1
+}
+
+// loadRepositoryData reads a repository data file, returning a *SchemaError when it
+// was written with a different schema version.
+func loadRepositoryData(file string) (RepositoryData, error) {
+	var repo RepositoryData
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return repo, err
+	}
+
+	var probe struct {
+		SchemaVersion *int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(raw, &probe); err != nil {
+		return repo, err
+	}
+	version := 1
+	if probe.SchemaVersion != nil {
+		version = *probe.SchemaVersion
+	}
+	if version != currentSchemaVersion {
+		return repo, &SchemaError{File: file, Version: version}
+	}
+
+	err = json.Unmarshal(raw, &repo)
+	return repo, err
+}
+
+// migrateFile upgrades a single data file in place to the current schema version.
+// It reports whether the file needed any change.
+func migrateFile(file string) (bool, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return false, err
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return false, err
+	}
+
+	version := fileSchemaVersion(data)
+	if version == currentSchemaVersion {
+		return false, nil
+	}
+	if version > currentSchemaVersion {
+		return false, &SchemaError{File: file, Version: version}
+	}
+
+	for ; version < currentSchemaVersion; version++ {
+		migrate, ok := migrations[version]
+		if !ok {
+			return false, fmt.Errorf("no migration from schema version %d", version)
+		}
+		if err := migrate(data); err != nil {
+			return false, fmt.Errorf("migrating from schema version %d: %w", version, err)
+		}
+	}
+	data["schema_version"] = currentSchemaVersion
+
+	// Round-trip through RepositoryData so the file keeps the same layout as a fresh crawl
+	migrated, err := json.Marshal(data)
+	if err != nil {
+		return false, err
+	}
+	var repo RepositoryData
+	if err := json.Unmarshal(migrated, &repo); err != nil {
+		return false, err
+	}
+	return true, writeJSON(file, repo)
+}
+
+// crawlMetadataFiles are the files in the data directory that describe the crawl
+// rather than a repository
+var crawlMetadataFiles = map[string]bool{
+	"timestamp.json":    true,
+	rateLimitReportFile: true,
+	checkpointFile:      true,
+	escalationsFile:     true,
+	changesFile:         true,
+}
+
+// repositoryDataFiles lists the repository data files in dir, skipping crawlMetadataFiles.
+func repositoryDataFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var repoFiles []string
+	for _, file := range files {
+		if !crawlMetadataFiles[filepath.Base(file)] {
+			repoFiles = append(repoFiles, file)
+		}
+	}
+	return repoFiles, nil
+}
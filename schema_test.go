@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMigrateFile(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		changed      bool
+		baselineType string
+	}{
+		{
+			name:         "version 1 release",
+			content:      `{"owner": "acme", "name": "tool", "latest_release_tag": "v1.0.0"}`,
+			changed:      true,
+			baselineType: BaselineRelease,
+		},
+		{
+			name:    "version 1 never released",
+			content: `{"owner": "acme", "name": "tool", "never_released": true}`,
+			changed: true,
+		},
+		{
+			name:         "version 1 with a baseline",
+			content:      `{"owner": "acme", "name": "tool", "baseline_type": "tag"}`,
+			changed:      true,
+			baselineType: BaselineTag,
+		},
+		{
+			name:         "current version",
+			content:      `{"schema_version": 2, "owner": "acme", "name": "tool", "baseline_type": "release"}`,
+			changed:      false,
+			baselineType: BaselineRelease,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "tool.json")
+			if err := os.WriteFile(file, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			changed, err := migrateFile(file)
+			if err != nil {
+				t.Fatalf("migrateFile returned error: %v", err)
+			}
+			if changed != tt.changed {
+				t.Errorf("migrateFile changed = %v, want %v", changed, tt.changed)
+			}
+			if !changed {
+				raw, _ := os.ReadFile(file)
+				if string(raw) != tt.content {
+					t.Errorf("migrateFile rewrote an up to date file: %s", raw)
+				}
+			}
+
+			repo, err := loadRepositoryData(file)
+			if err != nil {
+				t.Fatalf("loadRepositoryData after migration returned error: %v", err)
+			}
+			if repo.SchemaVersion != currentSchemaVersion || repo.Owner != "acme" || repo.Name != "tool" || repo.BaselineType != tt.baselineType {
+				t.Errorf("migrated data = %+v", repo)
+			}
+
+			// A second run has nothing left to do
+			if changed, err := migrateFile(file); changed || err != nil {
+				t.Errorf("second migrateFile = %v, %v, want false, nil", changed, err)
+			}
+		})
+	}
+}
+
+func TestMigrateFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return file
+	}
+
+	newer := write("newer.json", `{"schema_version": 99, "name": "tool"}`)
+	var schemaErr *SchemaError
+	if _, err := migrateFile(newer); !errors.As(err, &schemaErr) || schemaErr.Version != 99 {
+		t.Errorf("migrateFile of a newer file = %v, want a SchemaError for version 99", err)
+	}
+	if raw, _ := os.ReadFile(newer); string(raw) != `{"schema_version": 99, "name": "tool"}` {
+		t.Errorf("migrateFile modified a newer file: %s", raw)
+	}
+
+	if _, err := migrateFile(write("unknown.json", `{"schema_version": 0}`)); err == nil {
+		t.Error("migrateFile accepted a version without a migration")
+	}
+	if _, err := migrateFile(write("invalid.json", `{"name": `)); err == nil {
+		t.Error("migrateFile accepted invalid JSON")
+	}
+	if _, err := migrateFile(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("migrateFile accepted a missing file")
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// semverPattern matches tags such as v1.2.3, 1.2.3 and v1.2.3-rc.1
+var semverPattern = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+// SemVersion is a parsed semantic version tag
+type SemVersion struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+}
+
+// parseSemver parses a tag name as a semantic version, returning false if it does not match.
+func parseSemver(tag string) (SemVersion, bool) {
+	m := semverPattern.FindStringSubmatch(tag)
+	if m == nil {
+		return SemVersion{}, false
+	}
+	major, _ := strconv.Atoi(m[1])
+	minor, _ := strconv.Atoi(m[2])
+	patch, _ := strconv.Atoi(m[3])
+	return SemVersion{Major: major, Minor: minor, Patch: patch, Prerelease: m[4]}, true
+}
+
+// Compare returns -1, 0 or 1 depending on whether v sorts before, equal to or after o.
+// Prerelease versions sort before the corresponding stable version.
+func (v SemVersion) Compare(o SemVersion) int {
+	for _, d := range []int{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+
+	switch {
+	case v.Prerelease == o.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case o.Prerelease == "":
+		return -1
+	}
+	return comparePrerelease(v.Prerelease, o.Prerelease)
+}
+
+// String formats v without a "v" prefix.
+func (v SemVersion) String() string {
+	s := strconv.Itoa(v.Major) + "." + strconv.Itoa(v.Minor) + "." + strconv.Itoa(v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	return s
+}
+
+// suggestNextVersion proposes the version that releasing commits on top of tag would
+// get under semantic versioning: a major bump for breaking changes (minor before 1.0.0),
+// a minor bump for features and a patch bump otherwise. The tag's "v" prefix is kept.
+// It returns "" when there are no commits or tag is not a semantic version.
+func suggestNextVersion(tag string, commits []CommitInfo) string {
+	current, ok := parseSemver(tag)
+	if !ok || len(commits) == 0 {
+		return ""
+	}
+
+	breaking, feature := false, false
+	for _, c := range commits {
+		breaking = breaking || isBreakingChange(c.Message)
+		feature = feature || commitType(c.Message) == "feat"
+	}
+
+	next := SemVersion{Major: current.Major, Minor: current.Minor, Patch: current.Patch}
+	switch {
+	case current.Prerelease != "":
+		// The stable release of a pre-release tag comes next
+	case breaking && current.Major > 0:
+		next = SemVersion{Major: current.Major + 1}
+	case breaking || feature:
+		next = SemVersion{Major: current.Major, Minor: current.Minor + 1}
+	default:
+		next.Patch++
+	}
+
+	if strings.HasPrefix(tag, "v") {
+		return "v" + next.String()
+	}
+	return next.String()
+}
+
+// comparePrerelease compares dot-separated prerelease identifiers per the semver spec.
+func comparePrerelease(a, b string) int {
+	as := strings.Split(a, ".")
+	bs := strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				if an < bn {
+					return -1
+				}
+				return 1
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	switch {
+	case len(as) < len(bs):
+		return -1
+	case len(as) > len(bs):
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,132 @@
+package main
+
+import "testing"
+
+func TestParseSemver(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want SemVersion
+		ok   bool
+	}{
+		{"1.2.3", SemVersion{Major: 1, Minor: 2, Patch: 3}, true},
+		{"v1.2.3", SemVersion{Major: 1, Minor: 2, Patch: 3}, true},
+		{"v0.0.0", SemVersion{}, true},
+		{"v1.2.3-rc.1", SemVersion{Major: 1, Minor: 2, Patch: 3, Prerelease: "rc.1"}, true},
+		{"1.2.3-alpha-2", SemVersion{Major: 1, Minor: 2, Patch: 3, Prerelease: "alpha-2"}, true},
+		{"1.2.3+build.5", SemVersion{Major: 1, Minor: 2, Patch: 3}, true},
+		{"v1.2.3-beta+exp.sha.5114f85", SemVersion{Major: 1, Minor: 2, Patch: 3, Prerelease: "beta"}, true},
+
+		{"", SemVersion{}, false},
+		{"latest", SemVersion{}, false},
+		{"v1", SemVersion{}, false},
+		{"1.2", SemVersion{}, false},
+		{"1.2.3.4", SemVersion{}, false},
+		{"V1.2.3", SemVersion{}, false},
+		{"vv1.2.3", SemVersion{}, false},
+		{"release-1.2.3", SemVersion{}, false},
+		{"01.2.3", SemVersion{}, false},
+		{"1.02.3", SemVersion{}, false},
+		{"1.2.3-", SemVersion{}, false},
+		{"1.2.3-rc_1", SemVersion{}, false},
+		{"2024-01-15", SemVersion{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseSemver(tt.tag)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseSemver(%q) = %+v, %v, want %+v, %v", tt.tag, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestSemVersionCompare(t *testing.T) {
+	// In ascending order, following the precedence example of the semver spec
+	ordered := []string{
+		"0.9.9",
+		"v0.10.0",
+		"1.0.0-0.3.7",
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"v1.0.1",
+		"1.1.0-rc.1",
+		"1.1.0",
+		"1.10.0",
+		"2.0.0",
+		"v10.0.0",
+	}
+	for i, a := range ordered {
+		va, ok := parseSemver(a)
+		if !ok {
+			t.Fatalf("parseSemver(%q) failed", a)
+		}
+		for j, b := range ordered {
+			vb, _ := parseSemver(b)
+			want := 0
+			switch {
+			case i < j:
+				want = -1
+			case i > j:
+				want = 1
+			}
+			if got := va.Compare(vb); got != want {
+				t.Errorf("%s.Compare(%s) = %d, want %d", a, b, got, want)
+			}
+		}
+	}
+
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v1.2.3", "1.2.3", 0},
+		{"1.2.3+build.1", "1.2.3+build.2", 0},
+		{"v1.2.3-rc.1", "1.2.3-rc.1+build", 0},
+	}
+	for _, tt := range tests {
+		va, _ := parseSemver(tt.a)
+		vb, _ := parseSemver(tt.b)
+		if got := va.Compare(vb); got != tt.want {
+			t.Errorf("%s.Compare(%s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSuggestNextVersion(t *testing.T) {
+	commits := func(messages ...string) []CommitInfo {
+		var result []CommitInfo
+		for _, m := range messages {
+			result = append(result, CommitInfo{Message: m})
+		}
+		return result
+	}
+	tests := []struct {
+		name    string
+		tag     string
+		commits []CommitInfo
+		want    string
+	}{
+		{"fix", "v1.2.3", commits("fix: handle empty tags"), "v1.2.4"},
+		{"no conventional type", "1.2.3", commits("Update README"), "1.2.4"},
+		{"feature", "v1.2.3", commits("fix: typo", "feat: add a flag"), "v1.3.0"},
+		{"feature with scope", "v1.2.3", commits("feat(api): add an endpoint"), "v1.3.0"},
+		{"breaking marker", "v1.2.3", commits("feat!: drop the old flag", "fix: typo"), "v2.0.0"},
+		{"breaking footer", "1.2.3", commits("refactor: rename config\n\nBREAKING CHANGE: config.yaml is now config.yml"), "2.0.0"},
+		{"breaking before 1.0.0", "v0.4.2", commits("fix!: change the output format"), "v0.5.0"},
+		{"feature before 1.0.0", "v0.4.2", commits("feat: add a flag"), "v0.5.0"},
+		{"prerelease", "v2.0.0-rc.1", commits("feat!: one more change"), "v2.0.0"},
+		{"build metadata", "1.2.3+build.7", commits("fix: typo"), "1.2.4"},
+		{"no commits", "v1.2.3", nil, ""},
+		{"not semver", "release-2024", commits("feat: add a flag"), ""},
+		{"no tag", "", commits("fix: typo"), ""},
+	}
+	for _, tt := range tests {
+		if got := suggestNextVersion(tt.tag, tt.commits); got != tt.want {
+			t.Errorf("%s: suggestNextVersion(%q) = %q, want %q", tt.name, tt.tag, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// runServe generates the site and serves output/ on addr, regenerating whenever data/
+// changes, e.g. after a crawl in another container. /healthz and /readyz let it run
+// behind Kubernetes probes and load balancers, and /metrics can be scraped by Prometheus.
+func runServe(opts GenerateOptions, addr string, maxCrawlAge time.Duration) error {
+	health := &siteHealth{dataDir: "data", maxCrawlAge: maxCrawlAge}
+	mux := http.NewServeMux()
+	health.register(mux)
+	registerMetrics(mux, "data")
+	mux.Handle("/", http.FileServer(http.Dir("output")))
+
+	fmt.Printf("🌐 Serving output/ at http://%s, regenerating when data/ changes\n", addr)
+	return serveAndRegenerate(opts, addr, []string{"data"}, mux, health, nil)
+}
+
+// serveAndRegenerate generates the site, serves handler on addr, and regenerates whenever
+// a file in watched changes, calling onChange after each successful regeneration.
+func serveAndRegenerate(opts GenerateOptions, addr string, watched []string, handler http.Handler, health *siteHealth, onChange func()) error {
+	err := generateSite(opts)
+	health.record(err)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- http.ListenAndServe(addr, handler)
+	}()
+
+	last := snapshotFiles(watched)
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-serveErr:
+			return err
+		case <-ticker.C:
+		}
+
+		current := snapshotFiles(watched)
+		if current == last {
+			continue
+		}
+		last = current
+
+		fmt.Printf("\n🔁 Change detected at %s, regenerating...\n", time.Now().Format("15:04:05"))
+		err := generateSite(opts)
+		health.record(err)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			continue
+		}
+		if onChange != nil {
+			onChange()
+		}
+	}
+}
+
+// siteHealth tracks whether the served site has been generated, for /readyz
+type siteHealth struct {
+	dataDir     string
+	maxCrawlAge time.Duration
+
+	mu        sync.Mutex
+	generated bool
+	lastErr   error
+}
+
+// record notes the outcome of a generation. Once the site has been generated it stays
+// ready when a later regeneration fails, since the previous pages are still served.
+func (h *siteHealth) record(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastErr = err
+	if err == nil {
+		h.generated = true
+	}
+}
+
+// register adds /healthz, which succeeds while the server is running, and /readyz,
+// which succeeds once the site has been generated from data whose crawl is recent enough.
+func (h *siteHealth) register(mux *http.ServeMux) {
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if reason := h.notReady(); reason != "" {
+			http.Error(w, reason, http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+}
+
+// notReady returns why the site should not receive traffic, or "" when it is ready.
+func (h *siteHealth) notReady() string {
+	h.mu.Lock()
+	generated, lastErr := h.generated, h.lastErr
+	h.mu.Unlock()
+
+	if !generated {
+		if lastErr != nil {
+			return fmt.Sprintf("site not generated: %v", lastErr)
+		}
+		return "site not generated yet"
+	}
+
+	if h.maxCrawlAge > 0 {
+		crawl, err := loadLastCrawlTimestamp(filepath.Join(h.dataDir, "timestamp.json"))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return "no crawl timestamp in " + h.dataDir
+			}
+			return fmt.Sprintf("could not read crawl timestamp: %v", err)
+		}
+		if age := time.Since(crawl.LastCrawled); age > h.maxCrawlAge {
+			return fmt.Sprintf("last crawl was %s ago, more than %s", age.Round(time.Minute), h.maxCrawlAge)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// Release-detection strategies beyond the GitHub-based -baseline modes
+const (
+	// BaselineRegistry takes the version published to the package registry as released
+	BaselineRegistry = "registry"
+	// BaselineMarker takes the version named in a file on the default branch as released
+	BaselineMarker = "marker"
+)
+
+// defaultMarkerFile is the file read by the marker strategy unless marker_file is set
+const defaultMarkerFile = "VERSION"
+
+// ReleaseStrategy decides what counts as a repository's latest release, since projects
+// define "released" very differently
+type ReleaseStrategy interface {
+	// Latest returns the baseline the default branch is compared against, or nil when
+	// the repository has not been released
+	Latest(ctx context.Context, client *github.Client, owner, repo string) (*Baseline, error)
+	// Missing describes what an unreleased repository lacks, e.g. "releases"
+	Missing() string
+}
+
+// releaseStrategyFor returns the strategy for the named repository: its compare_branch
+// when set, otherwise its release setting, falling back to mode (the -baseline flag).
+func releaseStrategyFor(cfg *Config, name, mode string) ReleaseStrategy {
+	repo := cfg.Repo(name)
+	if repo.CompareBranch != "" {
+		return branchStrategy{branch: repo.CompareBranch}
+	}
+	if repo.Release != "" {
+		mode = repo.Release
+	}
+	pattern := cfg.TagPatternFor(name)
+
+	switch mode {
+	case BaselineRegistry:
+		return registryStrategy{}
+	case BaselineMarker:
+		return markerStrategy{file: cmp.Or(repo.MarkerFile, defaultMarkerFile)}
+	}
+	return githubStrategy{mode: mode, pattern: pattern}
+}
+
+// githubStrategy uses the latest GitHub Release, the newest tag, or whichever is newer
+type githubStrategy struct {
+	mode    string
+	pattern *regexp.Regexp
+}
+
+func (s githubStrategy) Latest(ctx context.Context, client *github.Client, owner, repo string) (*Baseline, error) {
+	return resolveBaseline(ctx, client, owner, repo, s.mode, s.pattern)
+}
+
+func (s githubStrategy) Missing() string {
+	return baselineDescription(s.mode)
+}
+
+// branchStrategy uses the head of a release or maintenance branch
+type branchStrategy struct {
+	branch string
+}
+
+func (s branchStrategy) Latest(ctx context.Context, client *github.Client, owner, repo string) (*Baseline, error) {
+	return branchBaseline(ctx, client, owner, repo, s.branch)
+}
+
+func (s branchStrategy) Missing() string {
+	return "branch " + s.branch
+}
+
+// registryStrategy uses the version published to the Go module proxy, or else to npm,
+// PyPI or crates.io, for projects that count as released once a version is available
+// to their users
+type registryStrategy struct{}
+
+func (registryStrategy) Latest(ctx context.Context, client *github.Client, owner, repo string) (*Baseline, error) {
+	file, _, resp, err := client.Repositories.GetContents(ctx, owner, repo, "go.mod", nil)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return latestPublishedPackage(ctx, client, owner, repo)
+		}
+		return nil, err
+	}
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, err
+	}
+	modulePath := parseModulePath(content)
+	if modulePath == "" {
+		return nil, fmt.Errorf("go.mod has no module directive")
+	}
+
+	version, versionTime, err := fetchProxyLatest(ctx, modulePath)
+	if err != nil {
+		return nil, err
+	}
+	if version == "" || pseudoVersionPattern.MatchString(version) {
+		// A pseudo-version is an untagged commit, not a published release
+		return nil, nil
+	}
+	tag, _, err := findVersionTag(ctx, client, owner, repo, version)
+	if err != nil {
+		return nil, err
+	}
+	if tag == "" {
+		return nil, fmt.Errorf("module proxy version %s has no matching tag", version)
+	}
+	return &Baseline{Type: BaselineRegistry, TagName: tag, Time: versionTime}, nil
+}
+
+func (registryStrategy) Missing() string {
+	return "published version"
+}
+
+// latestPublishedPackage returns the tag of the version published from the first
+// package manifest in the repository.
+func latestPublishedPackage(ctx context.Context, client *github.Client, owner, repo string) (*Baseline, error) {
+	for _, manifest := range packageManifests {
+		name, err := readManifestName(ctx, client, owner, repo, "", manifest)
+		if err != nil {
+			return nil, err
+		}
+		if name == "" {
+			continue
+		}
+		version, published, err := fetchRegistryLatest(ctx, manifest.registry, name)
+		if err != nil || version == "" {
+			return nil, err
+		}
+		tag, _, err := findVersionTag(ctx, client, owner, repo, version)
+		if err != nil {
+			return nil, err
+		}
+		if tag == "" {
+			return nil, fmt.Errorf("%s %s@%s has no matching tag", manifest.registry, name, version)
+		}
+		return &Baseline{Type: BaselineRegistry, TagName: tag, Time: published}, nil
+	}
+	return nil, nil
+}
+
+// markerStrategy uses the tag named in a file on the default branch, such as a VERSION
+// file that is bumped as part of each release
+type markerStrategy struct {
+	file string
+}
+
+func (s markerStrategy) Latest(ctx context.Context, client *github.Client, owner, repo string) (*Baseline, error) {
+	file, _, resp, err := client.Repositories.GetContents(ctx, owner, repo, s.file, nil)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, err
+	}
+	version := strings.TrimSpace(content)
+	if version == "" {
+		return nil, nil
+	}
+
+	// The file may name the version with or without the tag's "v" prefix
+	tag, commit, err := findVersionTag(ctx, client, owner, repo, version)
+	if err != nil {
+		return nil, err
+	}
+	if tag == "" {
+		return nil, fmt.Errorf("%s names version %s, but there is no tag for it", s.file, version)
+	}
+	return &Baseline{
+		Type:    BaselineMarker,
+		TagName: tag,
+		Time:    commit.GetCommit().GetCommitter().GetDate().Time,
+	}, nil
+}
+
+func (s markerStrategy) Missing() string {
+	return s.file + " file"
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// maxTagWarnings caps how many individual malformed tags are reported per repository
+const maxTagWarnings = 20
+
+// checkTagHygiene validates a repository's tags and releases against semantic
+// versioning, returning human readable warnings for tags that do not parse and for
+// releases published out of version order.
+func checkTagHygiene(ctx context.Context, client *github.Client, owner, repo string) ([]string, error) {
+	tags, err := listAllTags(ctx, client, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	releases, err := listAllReleases(ctx, client, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, tag := range tags {
+		names = append(names, tag.GetName())
+	}
+
+	var published []ReleaseInfo
+	for _, rel := range releases {
+		if rel.GetDraft() {
+			continue
+		}
+		published = append(published, ReleaseInfo{
+			TagName:     rel.GetTagName(),
+			PublishedAt: rel.GetPublishedAt().Time,
+		})
+	}
+
+	return append(malformedTagWarnings(names), releaseOrderWarnings(published)...), nil
+}
+
+// malformedTagWarnings reports tags that are not semantic versions.
+func malformedTagWarnings(tags []string) []string {
+	var unparsable []string
+	for _, tag := range tags {
+		if _, ok := parseSemver(tag); !ok {
+			unparsable = append(unparsable, tag)
+		}
+	}
+
+	if len(unparsable) == 0 {
+		return nil
+	}
+	if len(unparsable) == len(tags) {
+		return []string{fmt.Sprintf("none of the %d tags follow semantic versioning", len(tags))}
+	}
+
+	sort.Strings(unparsable)
+	var warnings []string
+	for i, tag := range unparsable {
+		if i == maxTagWarnings {
+			warnings = append(warnings, fmt.Sprintf("and %d more unparsable tags", len(unparsable)-maxTagWarnings))
+			break
+		}
+		warnings = append(warnings, fmt.Sprintf("tag `%s` unparsable", tag))
+	}
+	return warnings
+}
+
+// releaseOrderWarnings reports releases published after a release with a higher version.
+func releaseOrderWarnings(releases []ReleaseInfo) []string {
+	sorted := append([]ReleaseInfo(nil), releases...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].PublishedAt.Before(sorted[j].PublishedAt)
+	})
+
+	var warnings []string
+	var highestTag string
+	var highest SemVersion
+	for _, rel := range sorted {
+		v, ok := parseSemver(rel.TagName)
+		if !ok {
+			continue
+		}
+		if highestTag != "" && v.Compare(highest) < 0 {
+			warnings = append(warnings, fmt.Sprintf("%s published before %s", highestTag, rel.TagName))
+			continue
+		}
+		highestTag = rel.TagName
+		highest = v
+	}
+	return warnings
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+)
+
+// teamsMaxRepos caps the repositories listed in a Teams card; the rest are summarized
+// in a closing line
+const teamsMaxRepos = 20
+
+// TeamsConfig posts a digest of the repositories over threshold to a Microsoft Teams
+// channel after each crawl
+type TeamsConfig struct {
+	WebhookURLEnv string `json:"webhook_url_env"`
+	MinCommits    int    `json:"min_commits,omitempty"`
+	MinDaysBehind int    `json:"min_days_behind,omitempty"`
+	SiteURL       string `json:"site_url,omitempty"`
+}
+
+// validateTeams checks that the Teams settings name the webhook's environment variable
+// and have sensible thresholds.
+func validateTeams(t *TeamsConfig) error {
+	if t == nil {
+		return nil
+	}
+	if t.WebhookURLEnv == "" {
+		return fmt.Errorf("webhook_url_env is required")
+	}
+	if t.MinCommits < 0 || t.MinDaysBehind < 0 {
+		return fmt.Errorf("thresholds cannot be negative")
+	}
+	return nil
+}
+
+// overThreshold reports whether a repository is listed in the Teams digest: it has at
+// least one unreleased commit and meets every configured threshold.
+func (t *TeamsConfig) overThreshold(r RepoDelta) bool {
+	return r.UnreleasedCommits > 0 && r.UnreleasedCommits >= t.MinCommits && r.DaysBehind >= t.MinDaysBehind
+}
+
+// reposOverThreshold returns the repositories listed in the Teams digest, most
+// unreleased commits first.
+func (t *TeamsConfig) reposOverThreshold(n CrawlNotification) []RepoDelta {
+	var over []RepoDelta
+	for _, r := range n.Repos {
+		if t.overThreshold(r) {
+			over = append(over, r)
+		}
+	}
+	slices.SortStableFunc(over, func(a, b RepoDelta) int {
+		return b.UnreleasedCommits - a.UnreleasedCommits
+	})
+	return over
+}
+
+// teamsCard builds an Adaptive Card message listing the repositories over threshold.
+func teamsCard(t *TeamsConfig, n CrawlNotification, over []RepoDelta) map[string]any {
+	body := []map[string]any{
+		{
+			"type":   "TextBlock",
+			"text":   "Unreleased Commits - " + n.Owner,
+			"size":   "Large",
+			"weight": "Bolder",
+			"wrap":   true,
+		},
+		{
+			"type":     "TextBlock",
+			"text":     fmt.Sprintf("%d of %d repositories are over threshold, with %d unreleased commits in total.", len(over), n.Summary.Repos, n.Summary.UnreleasedCommits),
+			"wrap":     true,
+			"isSubtle": true,
+		},
+	}
+	for i, r := range over {
+		if i == teamsMaxRepos {
+			body = append(body, map[string]any{
+				"type": "TextBlock",
+				"text": fmt.Sprintf("…and %d more", len(over)-teamsMaxRepos),
+				"wrap": true,
+			})
+			break
+		}
+		body = append(body, map[string]any{
+			"type":      "TextBlock",
+			"text":      teamsRepoLine(r),
+			"wrap":      true,
+			"separator": i == 0,
+		})
+	}
+
+	card := map[string]any{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body":    body,
+	}
+	if t.SiteURL != "" {
+		card["actions"] = []map[string]any{
+			{"type": "Action.OpenUrl", "title": "Open dashboard", "url": t.SiteURL},
+		}
+	}
+
+	return map[string]any{
+		"type": "message",
+		"attachments": []map[string]any{
+			{"contentType": "application/vnd.microsoft.card.adaptive", "content": card},
+		},
+	}
+}
+
+// teamsRepoLine describes one repository of the digest in Adaptive Card markdown.
+func teamsRepoLine(r RepoDelta) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "**[%s](%s)**: %d unreleased commits", r.Name, r.RepositoryURL, r.UnreleasedCommits)
+	if r.Delta > 0 {
+		fmt.Fprintf(&b, " (+%d)", r.Delta)
+	} else if r.Delta < 0 {
+		fmt.Fprintf(&b, " (%d)", r.Delta)
+	}
+	if r.LatestRelease != "" {
+		fmt.Fprintf(&b, " since %s, %d days behind", r.LatestRelease, r.DaysBehind)
+	}
+	return b.String()
+}
+
+// sendTeams posts the digest to the Teams webhook. Nothing is posted when no repository
+// is over threshold.
+func sendTeams(ctx context.Context, t *TeamsConfig, n CrawlNotification) {
+	url := os.Getenv(t.WebhookURLEnv)
+	if url == "" {
+		fmt.Printf("⚠️  Teams webhook environment variable %s is not set\n", t.WebhookURLEnv)
+		return
+	}
+
+	over := t.reposOverThreshold(n)
+	if len(over) == 0 {
+		fmt.Println("📣 No repositories over threshold, skipping Teams notification")
+		return
+	}
+	body, err := json.Marshal(teamsCard(t, n, over))
+	if err != nil {
+		fmt.Printf("⚠️  Failed to encode Teams card: %v\n", err)
+		return
+	}
+
+	client := &http.Client{Timeout: webhookTimeout}
+	if err := postJSON(ctx, client, url, body, nil); err != nil {
+		fmt.Printf("⚠️  Teams notification failed: %v\n", err)
+		return
+	}
+	fmt.Println("📣 Posted digest to Microsoft Teams")
+}
@@ -0,0 +1,334 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OTLP span kinds and status codes
+const (
+	otlpSpanInternal = 1
+	otlpSpanClient   = 3
+	otlpStatusError  = 2
+)
+
+// otlpCumulative is the aggregation temporality of the exported sums and histograms
+const otlpCumulative = 2
+
+// requestDurationBounds are the histogram buckets of GitHub API request durations, in seconds
+var requestDurationBounds = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// crawlTelemetry is an http.RoundTripper that traces a crawl for OpenTelemetry: one span
+// for the crawl, one per repository and one per GitHub API request, plus request metrics.
+// Everything is kept in memory and exported over OTLP/HTTP once the crawl finishes, which
+// suits a batch job and needs no collector-side configuration beyond an OTLP receiver.
+type crawlTelemetry struct {
+	base     http.RoundTripper
+	endpoint string
+	header   http.Header
+	service  string
+
+	mu        sync.Mutex
+	started   time.Time
+	traceID   string
+	crawl     otlpSpan
+	repo      *otlpSpan
+	spans     []otlpSpan
+	requests  map[int]int
+	durations []int
+	sum       float64
+}
+
+// otlpSpan is a span in the OTLP/JSON encoding
+type otlpSpan struct {
+	TraceID      string          `json:"traceId"`
+	SpanID       string          `json:"spanId"`
+	ParentSpanID string          `json:"parentSpanId,omitempty"`
+	Name         string          `json:"name"`
+	Kind         int             `json:"kind"`
+	Start        string          `json:"startTimeUnixNano"`
+	End          string          `json:"endTimeUnixNano"`
+	Attributes   []otlpAttribute `json:"attributes,omitempty"`
+	Status       *otlpStatus     `json:"status,omitempty"`
+}
+
+// otlpStatus is the status of a span that failed
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// otlpAttribute is a key-value attribute in the OTLP/JSON encoding
+type otlpAttribute struct {
+	Key   string         `json:"key"`
+	Value map[string]any `json:"value"`
+}
+
+// newCrawlTelemetry wraps base when OTEL_EXPORTER_OTLP_ENDPOINT is set, configured by the
+// standard OTEL_EXPORTER_OTLP_HEADERS and OTEL_SERVICE_NAME variables. It returns nil
+// when telemetry is off; every method is a no-op on nil.
+func newCrawlTelemetry(base http.RoundTripper, owner string) *crawlTelemetry {
+	endpoint := strings.TrimSpace(os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"))
+	if endpoint == "" {
+		return nil
+	}
+	t := &crawlTelemetry{
+		base:     base,
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		header:   parseOTLPHeaders(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS")),
+		service:  envOr("OTEL_SERVICE_NAME", "unreleasedcommits"),
+		traceID:  randomHex(16),
+		requests: make(map[int]int),
+	}
+	t.durations = make([]int, len(requestDurationBounds)+1)
+	t.started = time.Now()
+	t.crawl = t.newSpan("crawl", otlpSpanInternal, "", stringAttribute("github.owner", owner))
+	return t
+}
+
+// parseOTLPHeaders parses the comma-separated key=value pairs of OTEL_EXPORTER_OTLP_HEADERS,
+// whose values may be URL-encoded.
+func parseOTLPHeaders(s string) http.Header {
+	header := make(http.Header)
+	for _, pair := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		if decoded, err := url.QueryUnescape(value); err == nil {
+			value = decoded
+		}
+		header.Set(strings.TrimSpace(key), strings.TrimSpace(value))
+	}
+	return header
+}
+
+// envOr returns the environment variable name, or fallback when it is unset.
+func envOr(name, fallback string) string {
+	if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+func stringAttribute(key, value string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: map[string]any{"stringValue": value}}
+}
+
+func intAttribute(key string, value int) otlpAttribute {
+	return otlpAttribute{Key: key, Value: map[string]any{"intValue": strconv.Itoa(value)}}
+}
+
+// newSpan starts a span of the crawl's trace.
+func (t *crawlTelemetry) newSpan(name string, kind int, parent string, attrs ...otlpAttribute) otlpSpan {
+	return otlpSpan{
+		TraceID:      t.traceID,
+		SpanID:       randomHex(8),
+		ParentSpanID: parent,
+		Name:         name,
+		Kind:         kind,
+		Start:        unixNano(time.Now()),
+		Attributes:   attrs,
+	}
+}
+
+// startRepo ends the span of the previous repository and, unless repo is empty, starts
+// one for repo that the following requests belong to.
+func (t *crawlTelemetry) startRepo(repo string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.repo != nil {
+		t.repo.End = unixNano(time.Now())
+		t.spans = append(t.spans, *t.repo)
+		t.repo = nil
+	}
+	if repo != "" {
+		span := t.newSpan("crawl "+repo, otlpSpanInternal, t.crawl.SpanID, stringAttribute("github.repository", repo))
+		t.repo = &span
+	}
+}
+
+func (t *crawlTelemetry) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	parent := t.crawl.SpanID
+	if t.repo != nil {
+		parent = t.repo.SpanID
+	}
+	span := t.newSpan(req.Method+" "+githubRoute(req.URL.Path), otlpSpanClient, parent,
+		stringAttribute("http.request.method", req.Method),
+		stringAttribute("url.full", req.URL.String()))
+	t.mu.Unlock()
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	elapsed := time.Since(start)
+
+	status := 0
+	switch {
+	case err != nil:
+		span.Status = &otlpStatus{Code: otlpStatusError, Message: err.Error()}
+		span.Attributes = append(span.Attributes, stringAttribute("error.type", fmt.Sprintf("%T", err)))
+	default:
+		status = resp.StatusCode
+		span.Attributes = append(span.Attributes, intAttribute("http.response.status_code", status))
+		if status >= 400 {
+			span.Status = &otlpStatus{Code: otlpStatusError, Message: resp.Status}
+			span.Attributes = append(span.Attributes, stringAttribute("error.type", strconv.Itoa(status)))
+		}
+	}
+	span.End = unixNano(start.Add(elapsed))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.spans = append(t.spans, span)
+	if (err != nil || status >= 500) && t.repo != nil {
+		// A repository hit by server or network errors is marked failed too, so it stands out
+		// in a trace; 404s are expected, e.g. for repositories without a release
+		t.repo.Status = &otlpStatus{Code: otlpStatusError, Message: "GitHub API request failed"}
+	}
+	t.requests[status]++
+	seconds := elapsed.Seconds()
+	t.durations[bucketIndex(requestDurationBounds, seconds)]++
+	t.sum += seconds
+	return resp, err
+}
+
+// githubRoute replaces the owner and repository in an API path with placeholders, so
+// request spans of different repositories share a name.
+func githubRoute(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) >= 3 && parts[0] == "repos" {
+		parts[1], parts[2] = "{owner}", "{repo}"
+	}
+	if len(parts) >= 3 && (parts[0] == "orgs" || parts[0] == "users") {
+		parts[1] = "{owner}"
+	}
+	return "/" + strings.Join(parts, "/")
+}
+
+func bucketIndex(bounds []float64, v float64) int {
+	i, _ := slices.BinarySearch(bounds, v)
+	return i
+}
+
+// export ends the crawl span and sends the spans and metrics to the OTLP endpoint. A
+// failed export is reported but does not fail the crawl.
+func (t *crawlTelemetry) export(ctx context.Context) {
+	if t == nil {
+		return
+	}
+	t.startRepo("")
+
+	t.mu.Lock()
+	now := time.Now()
+	t.crawl.End = unixNano(now)
+	spans := append(t.spans, t.crawl)
+	metrics := t.metrics(now)
+	t.mu.Unlock()
+
+	resource := map[string]any{"attributes": []otlpAttribute{
+		stringAttribute("service.name", t.service),
+		stringAttribute("service.version", version),
+	}}
+	scope := map[string]any{"name": "unreleasedcommits", "version": version}
+
+	traces := map[string]any{"resourceSpans": []any{map[string]any{
+		"resource":   resource,
+		"scopeSpans": []any{map[string]any{"scope": scope, "spans": spans}},
+	}}}
+	metricData := map[string]any{"resourceMetrics": []any{map[string]any{
+		"resource":     resource,
+		"scopeMetrics": []any{map[string]any{"scope": scope, "metrics": metrics}},
+	}}}
+
+	client := &http.Client{Timeout: webhookTimeout}
+	for path, payload := range map[string]any{"/v1/traces": traces, "/v1/metrics": metricData} {
+		body, err := json.Marshal(payload)
+		if err == nil {
+			err = postJSON(ctx, client, t.endpoint+path, body, t.header)
+		}
+		if err != nil {
+			fmt.Printf("⚠️  Failed to export telemetry to %s: %v\n", t.endpoint+path, err)
+		}
+	}
+	fmt.Printf("🔭 Exported %d spans to %s\n", len(spans), t.endpoint)
+}
+
+// metrics returns the crawl's metrics in the OTLP/JSON encoding.
+func (t *crawlTelemetry) metrics(now time.Time) []any {
+	start, end := t.crawl.Start, unixNano(now)
+
+	var requests []any
+	total := 0
+	for _, status := range slices.Sorted(maps.Keys(t.requests)) {
+		n := t.requests[status]
+		total += n
+		point := map[string]any{"startTimeUnixNano": start, "timeUnixNano": end, "asInt": strconv.Itoa(n)}
+		if status != 0 {
+			point["attributes"] = []otlpAttribute{intAttribute("http.response.status_code", status)}
+		} else {
+			point["attributes"] = []otlpAttribute{stringAttribute("error.type", "transport")}
+		}
+		requests = append(requests, point)
+	}
+	buckets := make([]string, len(t.durations))
+	for i, n := range t.durations {
+		buckets[i] = strconv.Itoa(n)
+	}
+
+	return []any{
+		map[string]any{
+			"name":        "unreleasedcommits.github.requests",
+			"description": "GitHub API requests made by the crawl",
+			"unit":        "{request}",
+			"sum":         map[string]any{"dataPoints": requests, "aggregationTemporality": otlpCumulative, "isMonotonic": true},
+		},
+		map[string]any{
+			"name":        "unreleasedcommits.github.request.duration",
+			"description": "Duration of GitHub API requests",
+			"unit":        "s",
+			"histogram": map[string]any{"aggregationTemporality": otlpCumulative, "dataPoints": []any{map[string]any{
+				"startTimeUnixNano": start,
+				"timeUnixNano":      end,
+				"count":             strconv.Itoa(total),
+				"sum":               t.sum,
+				"bucketCounts":      buckets,
+				"explicitBounds":    requestDurationBounds,
+			}}},
+		},
+		map[string]any{
+			"name":        "unreleasedcommits.crawl.duration",
+			"description": "Duration of the crawl",
+			"unit":        "s",
+			"gauge": map[string]any{"dataPoints": []any{map[string]any{
+				"timeUnixNano": end,
+				"asDouble":     now.Sub(t.started).Seconds(),
+			}}},
+		},
+	}
+}
@@ -1,12 +1,15 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
 	"html/template"
 	"io/fs"
 	"math"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -44,13 +47,16 @@ func getTextColor(normalizedValue float64) string {
 	return "#000000"
 }
 
-func generateIndexPage(outputDir string, repos []RepositoryData, lastUpdated string) error {
-	tmpl, err := loadTemplates()
+func generateIndexPage(outputDir string, repos []RepositoryData, lastUpdated string, hasChanges bool, opts GenerateOptions) error {
+	tmpl, err := loadTemplates(opts)
 	if err != nil {
 		return fmt.Errorf("failed to parse index template: %w", err)
 	}
 
 	var summaries []SummaryData
+	var allCommits []CommitInfo
+	// The same fields as api/index.json, embedded for the export buttons
+	var exportData []APIRepoSummary
 	totalCommits := 0
 	reposWithCommits := 0
 
@@ -63,24 +69,20 @@ func generateIndexPage(outputDir string, repos []RepositoryData, lastUpdated str
 	maxDaysSinceRelease := 0
 
 	for _, repo := range repos {
+		if repo.NeverReleased {
+			continue
+		}
+
 		commitCount := len(repo.UnreleasedCommits)
 		totalCommits += commitCount
+		allCommits = append(allCommits, repo.UnreleasedCommits...)
 		if commitCount > 0 {
 			reposWithCommits++
 		}
 
-		daysBehind := 0
-		// Calculate days between the last release and the most recent commit
-		if commitCount > 0 && len(repo.UnreleasedCommits) > 0 && !repo.LatestReleaseTime.IsZero() {
-			// Since commits are ordered with newest first (reversed in main.go)
-			latestCommitTime := repo.UnreleasedCommits[0].Timestamp
-			daysBehind = int(latestCommitTime.Sub(repo.LatestReleaseTime).Hours() / 24)
-		}
-
-		daysSinceRelease := 0
-		if !repo.LatestReleaseTime.IsZero() {
-			daysSinceRelease = int(time.Since(repo.LatestReleaseTime).Hours() / 24)
-		}
+		daysBehind := repoDaysBehind(repo)
+		daysSinceRelease, _ := repoAges(repo)
+		nextVersion := suggestNextVersion(repo.LatestReleaseTag, repo.UnreleasedCommits)
 
 		// Update min/max values
 		if minCommits == -1 || commitCount < minCommits {
@@ -104,15 +106,49 @@ func generateIndexPage(outputDir string, repos []RepositoryData, lastUpdated str
 			maxDaysSinceRelease = daysSinceRelease
 		}
 
+		exportData = append(exportData, apiRepoSummary(repo))
 		summaries = append(summaries, SummaryData{
-			Name:             repo.Name,
-			CommitCount:      commitCount,
-			DaysBehind:       daysBehind,
-			DaysSinceRelease: daysSinceRelease,
-			LatestRelease:    repo.LatestReleaseTag,
-			URL:              fmt.Sprintf("%s.html", repo.Name),
-			RepositoryURL:    repo.RepositoryURL,
-			DefaultBranch:    repo.DefaultBranch,
+			Name:                 repo.Name,
+			Pinned:               matchesAny(opts.Config.Pinned, repo.Owner, repo.Name),
+			CommitCount:          commitCount,
+			DependencyCount:      countDependencyUpdates(repo.UnreleasedCommits),
+			BreakingCount:        countBreakingChanges(repo.UnreleasedCommits),
+			SecurityCount:        countSecurityFixes(repo.UnreleasedCommits),
+			SecurityFixDays:      securityFixDays(repo.UnreleasedCommits),
+			OpenAlerts:           countAlerts(repo.DependabotAlerts, AlertOpen),
+			UnreleasedAlertFixes: countAlerts(repo.DependabotAlerts, AlertFixed),
+			DaysBehind:           daysBehind,
+			DaysSinceRelease:     daysSinceRelease,
+			TypicalReleaseDays:   typicalReleaseDays(repo.ReleaseHistory),
+			LatestRelease:        repo.LatestReleaseTag,
+			LatestReleaseTime:    repo.LatestReleaseTime,
+			BehindBy:             repo.BehindBy,
+			Channels:             repo.Channels,
+			URL:                  fmt.Sprintf("%s.html", repo.Name),
+			RepositoryURL:        repo.RepositoryURL,
+			DefaultBranch:        repo.DefaultBranch,
+			BaselineType:         repo.BaselineType,
+			Archived:             repo.Archived,
+			Deprecated:           repo.Deprecated,
+			Description:          repo.Description,
+			Language:             repo.Language,
+			Topics:               repo.Topics,
+			Stars:                repo.Stars,
+			Forks:                repo.Forks,
+			OpenIssues:           repo.OpenIssues,
+			Impact:               impactScore(commitCount, repo.Stars, repo.Forks),
+			License:              repo.License,
+			GoProxyLagging:       repo.GoModule != nil && repo.GoModule.Lagging,
+			UnpublishedTag:       unpublishedTag(repo.Packages),
+			ImageNotPushed:       repo.ContainerImage != nil && !repo.ContainerImage.ReleasePushed,
+			BrewReleasesBehind:   brewReleasesBehind(repo.Homebrew),
+			PublishLag:           worstPublishLag(repo, time.Now()),
+			Prerelease:           repo.Prerelease,
+			TagWarnings:          len(repo.TagWarnings),
+			CIStatus:             repo.CIStatus,
+			OpenPullRequests:     repo.OpenPullRequests,
+			NextVersion:          nextVersion,
+			PlannedRelease:       plannedRelease(repo.Milestones, nextVersion),
 		})
 	}
 
@@ -129,45 +165,16 @@ func generateIndexPage(outputDir string, repos []RepositoryData, lastUpdated str
 
 	// Compute colors for each summary
 	for i := range summaries {
-		// Compute commit count color
-		commitRange := maxCommits - minCommits
-		if commitRange > 0 {
-			normalized := float64(summaries[i].CommitCount-minCommits) / float64(commitRange)
-			summaries[i].CommitCountBgColor = getColorForValue(normalized)
-			summaries[i].CommitCountTextColor = getTextColor(normalized)
-		} else {
-			summaries[i].CommitCountBgColor = getColorForValue(0)
-			summaries[i].CommitCountTextColor = getTextColor(0)
-		}
-
-		// Compute days behind color
-		daysBehindRange := maxDaysBehind - minDaysBehind
-		if daysBehindRange > 0 {
-			normalized := float64(summaries[i].DaysBehind-minDaysBehind) / float64(daysBehindRange)
-			summaries[i].DaysBehindBgColor = getColorForValue(normalized)
-			summaries[i].DaysBehindTextColor = getTextColor(normalized)
-		} else {
-			summaries[i].DaysBehindBgColor = getColorForValue(0)
-			summaries[i].DaysBehindTextColor = getTextColor(0)
-		}
-
-		// Compute days since release color
-		daysSinceRange := maxDaysSinceRelease - minDaysSinceRelease
-		if daysSinceRange > 0 {
-			normalized := float64(summaries[i].DaysSinceRelease-minDaysSinceRelease) / float64(daysSinceRange)
-			summaries[i].DaysSinceBgColor = getColorForValue(normalized)
-			summaries[i].DaysSinceTextColor = getTextColor(normalized)
-		} else {
-			summaries[i].DaysSinceBgColor = getColorForValue(0)
-			summaries[i].DaysSinceTextColor = getTextColor(0)
-		}
+		s := &summaries[i]
+		s.CommitCountBgColor, s.CommitCountTextColor = metricColors(opts.Config, "commits", s.CommitCount, minCommits, maxCommits)
+		s.DaysBehindBgColor, s.DaysBehindTextColor = metricColors(opts.Config, "days_behind", s.DaysBehind, minDaysBehind, maxDaysBehind)
+		s.DaysSinceBgColor, s.DaysSinceTextColor = metricColors(opts.Config, "days_since_release", s.DaysSinceRelease, minDaysSinceRelease, maxDaysSinceRelease)
 	}
 
-	file, err := os.Create(filepath.Join(outputDir, "index.html"))
-	if err != nil {
-		return err
-	}
-	defer file.Close()
+	// Pinned repositories lead the table, otherwise keeping the existing order
+	sort.SliceStable(summaries, func(i, j int) bool {
+		return summaries[i].Pinned && !summaries[j].Pinned
+	})
 
 	// Extract owner from the first repository (all repos have the same owner)
 	owner := ""
@@ -178,96 +185,428 @@ func generateIndexPage(outputDir string, repos []RepositoryData, lastUpdated str
 	data := struct {
 		Owner               string
 		TotalRepos          int
+		NeverReleased       []NeverReleasedSummary
 		TotalCommits        int
 		ReposWithCommits    int
 		Repos               []SummaryData
+		AllTopics           []string
+		AllLicenses         []string
+		Unlicensed          int
+		SecurityAlerts      []SummaryData
 		MinCommits          int
 		MaxCommits          int
 		MinDaysBehind       int
 		MaxDaysBehind       int
 		MinDaysSinceRelease int
 		MaxDaysSinceRelease int
+		HasMetrics          bool
+		HasLeaderboard      bool
+		HasChanges          bool
+		HideZero            bool
+		ExportData          []APIRepoSummary
+		Groups              []RepoGroup
+		CompositionChart    template.HTML
 		LastUpdated         string
 	}{
 		Owner:               owner,
-		TotalRepos:          len(repos),
+		TotalRepos:          len(summaries),
+		NeverReleased:       summarizeNeverReleased(repos),
 		TotalCommits:        totalCommits,
 		ReposWithCommits:    reposWithCommits,
 		Repos:               summaries,
+		AllTopics:           collectTopics(summaries),
+		AllLicenses:         collectLicenses(summaries),
+		Unlicensed:          countUnlicensed(repos),
+		SecurityAlerts:      securityAlerts(summaries),
 		MinCommits:          minCommits,
 		MaxCommits:          maxCommits,
 		MinDaysBehind:       minDaysBehind,
 		MaxDaysBehind:       maxDaysBehind,
 		MinDaysSinceRelease: minDaysSinceRelease,
 		MaxDaysSinceRelease: maxDaysSinceRelease,
+		HasMetrics:          hasReleaseHistory(repos),
+		HasLeaderboard:      len(opts.CompareDirs) > 0,
+		HasChanges:          hasChanges,
+		HideZero:            opts.HideZero,
+		ExportData:          exportData,
+		Groups:              groupRepos(summaries, opts.Config.Groups, owner),
+		CompositionChart:    compositionSVG(categoryCounts(allCommits)),
 		LastUpdated:         lastUpdated,
 	}
 
-	return tmpl.ExecuteTemplate(file, "index.html", data)
+	return executePage(tmpl, "index.html", filepath.Join(outputDir, "index.html"), data)
 }
 
-func generateRepoPage(outputDir string, repo RepositoryData, lastUpdated string) error {
-	tmpl, err := loadTemplates()
-	if err != nil {
-		return fmt.Errorf("failed to parse repo template: %w", err)
+// securityAlerts returns the repositories with unreleased security fixes or fixes for
+// Dependabot alerts, longest waiting first.
+func securityAlerts(summaries []SummaryData) []SummaryData {
+	var alerts []SummaryData
+	for _, s := range summaries {
+		if s.SecurityCount > 0 || s.UnreleasedAlertFixes > 0 {
+			alerts = append(alerts, s)
+		}
 	}
+	sort.SliceStable(alerts, func(i, j int) bool {
+		return alerts[i].SecurityFixDays > alerts[j].SecurityFixDays
+	})
+	return alerts
+}
+
+// collectTopics returns the sorted set of topics used across all repositories.
+func collectTopics(summaries []SummaryData) []string {
+	seen := make(map[string]bool)
+	var topics []string
+	for _, s := range summaries {
+		for _, topic := range s.Topics {
+			if !seen[topic] {
+				seen[topic] = true
+				topics = append(topics, topic)
+			}
+		}
+	}
+	sort.Strings(topics)
+	return topics
+}
 
-	filename := filepath.Join(outputDir, fmt.Sprintf("%s.html", repo.Name))
-	file, err := os.Create(filename)
+// commitBatchSize is how many commits are shown before the "show more" button on repo pages
+const commitBatchSize = 50
+
+// visibleCommits returns the newest commits up to limit, or all of them when limit is 0.
+func visibleCommits(commits []CommitInfo, limit int) []CommitInfo {
+	if limit > 0 && len(commits) > limit {
+		return commits[:limit]
+	}
+	return commits
+}
+
+func generateRepoPage(outputDir string, repo RepositoryData, lastUpdated string, opts GenerateOptions) error {
+	tmpl, err := loadTemplates(opts)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to parse repo template: %w", err)
 	}
-	defer file.Close()
 
 	// Calculate DaysBehind and DaysSinceRelease
-	daysBehind := 0
-	commitCount := len(repo.UnreleasedCommits)
-	if commitCount > 0 && !repo.LatestReleaseTime.IsZero() {
-		// Since commits are ordered with newest first (reversed in main.go)
-		latestCommitTime := repo.UnreleasedCommits[0].Timestamp
-		daysBehind = int(latestCommitTime.Sub(repo.LatestReleaseTime).Hours() / 24)
+	daysBehind := repoDaysBehind(repo)
+	daysSinceRelease, oldestCommitDays := repoAges(repo)
+	nextVersion := suggestNextVersion(repo.LatestReleaseTag, repo.UnreleasedCommits)
+
+	visible := visibleCommits(repo.UnreleasedCommits, opts.MaxCommits)
+	chunks := commitChunks(visible)
+	inline := CommitCards{
+		CollapseMerges: opts.Merges == MergesCollapse,
+		RepositoryURL:  repo.RepositoryURL,
 	}
+	if len(chunks) > 0 {
+		inline.Commits = chunks[0]
+	}
+	if err := writeCommitChunks(tmpl, outputDir, repo, chunks, inline.CollapseMerges); err != nil {
+		return fmt.Errorf("failed to write commit chunks: %w", err)
+	}
+
+	// Create a data struct with the calculated fields
+	data := struct {
+		RepositoryData
+		DaysBehind         int
+		DaysSinceRelease   int
+		OldestCommitDays   int
+		DependencyCount    int
+		BreakingCount      int
+		SecurityCount      int
+		TypicalReleaseDays int
+		CommitCalendar     template.HTML
+		WeeklyChart        template.HTML
+		TrendChart         template.HTML
+		CompositionChart   template.HTML
+		CollapseMerges     bool
+		VisibleCommits     []CommitInfo
+		InlineCommits      CommitCards
+		CommitChunks       int
+		CommitBatchSize    int
+		PendingPulls       []PendingPullRequest
+		NewCommits         int
+		PublishLags        []PublishLag
+		NextVersion        string
+		PlannedRelease     *PlannedRelease
+		LabelCounts        []LabelCount
+		AuthorCounts       []LabelCount
+		TypeCounts         []LabelCount
+		LastUpdated        string
+	}{
+		RepositoryData:     repo,
+		DaysBehind:         daysBehind,
+		DaysSinceRelease:   daysSinceRelease,
+		OldestCommitDays:   oldestCommitDays,
+		DependencyCount:    countDependencyUpdates(repo.UnreleasedCommits),
+		BreakingCount:      countBreakingChanges(repo.UnreleasedCommits),
+		SecurityCount:      countSecurityFixes(repo.UnreleasedCommits),
+		TypicalReleaseDays: typicalReleaseDays(repo.ReleaseHistory),
+		CommitCalendar:     commitCalendarSVG(repo.UnreleasedCommits),
+		WeeklyChart:        weeklyCommitsSVG(repo.UnreleasedCommits),
+		TrendChart:         unreleasedTrendSVG(repo.UnreleasedHistory),
+		CompositionChart:   compositionSVG(categoryCounts(repo.UnreleasedCommits)),
+		CollapseMerges:     inline.CollapseMerges,
+		VisibleCommits:     visible,
+		InlineCommits:      inline,
+		CommitChunks:       max(len(chunks)-1, 0),
+		CommitBatchSize:    commitBatchSize,
+		PendingPulls:       pendingPullRequests(repo),
+		NewCommits:         countNewCommits(repo.UnreleasedCommits),
+		PublishLags:        publishLags(repo, time.Now()),
+		NextVersion:        nextVersion,
+		PlannedRelease:     plannedRelease(repo.Milestones, nextVersion),
+		LabelCounts:        labelCounts(repo.UnreleasedCommits),
+		AuthorCounts:       authorCounts(repo.UnreleasedCommits),
+		TypeCounts:         categoryCounts(repo.UnreleasedCommits),
+		LastUpdated:        lastUpdated,
+	}
+
+	return executePage(tmpl, "repo.html", filepath.Join(outputDir, fmt.Sprintf("%s.html", repo.Name)), data)
+}
+
+// repoDaysBehind returns the days between the latest release and the most recent unreleased commit.
+func repoDaysBehind(repo RepositoryData) int {
+	if len(repo.UnreleasedCommits) == 0 || repo.LatestReleaseTime.IsZero() {
+		return 0
+	}
+	// Since commits are ordered with newest first (reversed in main.go)
+	latestCommitTime := repo.UnreleasedCommits[0].Timestamp
+	return int(latestCommitTime.Sub(repo.LatestReleaseTime).Hours() / 24)
+}
 
-	daysSinceRelease := 0
+// repoAges returns the days since the latest release and the age in days of the oldest
+// unreleased commit, the only parts of a repository page that change as time passes.
+func repoAges(repo RepositoryData) (daysSinceRelease, oldestCommitDays int) {
 	if !repo.LatestReleaseTime.IsZero() {
 		daysSinceRelease = int(time.Since(repo.LatestReleaseTime).Hours() / 24)
 	}
+	// Age of the oldest pending commit, comparable with the typical time to release
+	if n := len(repo.UnreleasedCommits); n > 0 {
+		oldestCommitDays = int(time.Since(repo.UnreleasedCommits[n-1].Timestamp).Hours() / 24)
+	}
+	return daysSinceRelease, oldestCommitDays
+}
+
+// ReleaseTimelineEntry is a single release row on the release history page
+type ReleaseTimelineEntry struct {
+	ReleaseInfo
+	DaysSincePrevious int
+	CompareURL        string
+	PageURL           string
+}
+
+// releaseHistoryPath returns the path of a repository's release history page relative to the output directory.
+func releaseHistoryPath(repoName string) string {
+	return filepath.Join(repoName, "releases", "index.html")
+}
+
+// releasePagePath returns the path of the page listing the commits of one release,
+// relative to the output directory. Characters that are not safe in a file name, such as
+// the slash in "server/v1.2.0", are replaced with dashes.
+func releasePagePath(repoName, tag string) string {
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_' {
+			return r
+		}
+		return '-'
+	}, tag)
+	if name == "index" {
+		// Keep the release timeline at index.html
+		name = "release-index"
+	}
+	return filepath.Join(repoName, "releases", name+".html")
+}
+
+// releasePages returns the paths of every page generated from a repository's release
+// history, relative to the output directory.
+func releasePages(repo RepositoryData) []string {
+	pages := []string{releaseHistoryPath(repo.Name)}
+	for _, rel := range repo.ReleaseHistory {
+		pages = append(pages, releasePagePath(repo.Name, rel.TagName))
+	}
+	return pages
+}
+
+func generateReleaseHistoryPage(outputDir string, repo RepositoryData, lastUpdated string, opts GenerateOptions) error {
+	tmpl, err := loadTemplates(opts)
+	if err != nil {
+		return fmt.Errorf("failed to parse releases template: %w", err)
+	}
+
+	filename := filepath.Join(outputDir, releaseHistoryPath(repo.Name))
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	// Build the timeline newest first, keeping the delta to the preceding release
+	var entries []ReleaseTimelineEntry
+	for i := len(repo.ReleaseHistory) - 1; i >= 0; i-- {
+		rel := repo.ReleaseHistory[i]
+		entry := ReleaseTimelineEntry{ReleaseInfo: rel, PageURL: filepath.ToSlash(releasePagePath(repo.Name, rel.TagName))}
+		if i > 0 {
+			prev := repo.ReleaseHistory[i-1]
+			entry.DaysSincePrevious = int(rel.PublishedAt.Sub(prev.PublishedAt).Hours() / 24)
+			entry.CompareURL = fmt.Sprintf("%s/compare/%s...%s", repo.RepositoryURL, prev.TagName, rel.TagName)
+		}
+		entries = append(entries, entry)
+	}
 
-	// Create a data struct with the calculated fields
 	data := struct {
 		RepositoryData
-		DaysBehind       int
-		DaysSinceRelease int
-		LastUpdated      string
+		Releases    []ReleaseTimelineEntry
+		LastUpdated string
+	}{
+		RepositoryData: repo,
+		Releases:       entries,
+		LastUpdated:    lastUpdated,
+	}
+
+	if err := executePage(tmpl, "releases.html", filename, data); err != nil {
+		return err
+	}
+
+	// One page per release; entries are newest first, so the newer release comes before
+	for i, entry := range entries {
+		page := struct {
+			RepositoryData
+			Release     ReleaseTimelineEntry
+			Newer       *ReleaseTimelineEntry
+			Older       *ReleaseTimelineEntry
+			LastUpdated string
+		}{
+			RepositoryData: repo,
+			Release:        entry,
+			LastUpdated:    lastUpdated,
+		}
+		if i > 0 {
+			page.Newer = &entries[i-1]
+		}
+		if i < len(entries)-1 {
+			page.Older = &entries[i+1]
+		}
+		if err := executePage(tmpl, "release.html", filepath.Join(outputDir, releasePagePath(repo.Name, entry.TagName)), page); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func generateMetricsPage(outputDir string, repos []RepositoryData, lastUpdated string, opts GenerateOptions) error {
+	tmpl, err := loadTemplates(opts)
+	if err != nil {
+		return fmt.Errorf("failed to parse metrics template: %w", err)
+	}
+
+	org, perRepo := computeLeadTimeMetrics(repos, time.Now())
+
+	owner := ""
+	if len(repos) > 0 {
+		owner = repos[0].Owner
+	}
+
+	data := struct {
+		Owner       string
+		Org         LeadTimeStats
+		Repos       []RepoLeadTime
+		WindowDays  int
+		LastUpdated string
 	}{
-		RepositoryData:   repo,
-		DaysBehind:       daysBehind,
-		DaysSinceRelease: daysSinceRelease,
-		LastUpdated:      lastUpdated,
+		Owner:       owner,
+		Org:         org,
+		Repos:       perRepo,
+		WindowDays:  int(releaseFrequencyWindow.Hours() / 24),
+		LastUpdated: lastUpdated,
 	}
 
-	return tmpl.ExecuteTemplate(file, "repo.html", data)
+	return executePage(tmpl, "metrics.html", filepath.Join(outputDir, "metrics.html"), data)
 }
 
 func generateCSS(outputDir string) error {
-	return copyEmbeddedFile(templateFS, "templates/style.css", filepath.Join(outputDir, "style.css"))
+	return writeFingerprintedAsset(outputDir, "style.css")
+}
+
+func generateJS(outputDir string) error {
+	return writeFingerprintedAsset(outputDir, "script.js")
+}
+
+// templateFuncs are the helper functions available to all templates
+var templateFuncs = template.FuncMap{
+	"asset":          assetPath,
+	"build":          currentBuild,
+	"columns":        func() []IndexColumn { return indexColumns(nil) },
+	"commitBody":     commitBody,
+	"commitSubject":  commitSubject,
+	"dateGroup":      currentDateGroup,
+	"emojify":        emojify,
+	"formatBytes":    formatBytes,
+	"formatCount":    formatCount,
+	"formatDuration": formatDuration,
+	"inlineCSS":      inlineCSS,
+	"inlineJS":       inlineJS,
+	"isBreaking":     isBreakingChange,
+	"isDependency":   isDependencyUpdate,
+	"commitCategory": commitCategory,
+	"isSecurity":     isSecurityFix,
+	"join":           strings.Join,
+	"markdown":       renderMarkdown,
+	"registryName":   registryName,
+	"sub":            func(a, b int) int { return a - b },
+	"theme":          currentTheme,
+	"totalDownloads": totalDownloads,
+}
+
+// formatBytes renders a byte count using binary units, e.g. 1.5 MiB.
+func formatBytes(n int) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := unit, 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// totalDownloads sums the download counts of all assets.
+func totalDownloads(assets []AssetInfo) int {
+	total := 0
+	for _, a := range assets {
+		total += a.DownloadCount
+	}
+	return total
 }
 
 // loadTemplates loads templates from the embedded filesystem,
 // or from disk if TEMPLATE_PATH environment variable is set (for development).
-func loadTemplates() (*template.Template, error) {
+// Templates in opts.TemplatesDir are then layered on top, so a user can replace
+// individual pages or partials while everything else falls back to the built-in versions.
+func loadTemplates(opts GenerateOptions) (*template.Template, error) {
+	tmpl, err := loadBaseTemplates()
+	if err != nil {
+		return nil, err
+	}
+	tmpl.Funcs(template.FuncMap{
+		"columns": func() []IndexColumn { return indexColumns(opts.Config.IndexColumns) },
+	})
+	if opts.TemplatesDir == "" {
+		return tmpl, nil
+	}
+	return tmpl.ParseGlob(filepath.Join(opts.TemplatesDir, "*.html"))
+}
+
+func loadBaseTemplates() (*template.Template, error) {
 	// Dev-time override: load from disk if TEMPLATE_PATH is set
 	if dir := os.Getenv("TEMPLATE_PATH"); dir != "" {
 		fmt.Printf("Loading templates from disk: %s\n", dir)
-		return template.ParseGlob(filepath.Join(dir, "*.html"))
+		return template.New("").Funcs(templateFuncs).ParseGlob(filepath.Join(dir, "*.html"))
 	}
 	// Production: load from embedded filesystem
-	return template.ParseFS(templateFS, "templates/*.html")
+	return template.New("").Funcs(templateFuncs).ParseFS(templateFS, "templates/*.html")
 }
 
-// copyEmbeddedFile copies a file from the embedded filesystem to the destination path.
-func copyEmbeddedFile(fsys fs.FS, src, dst string) error {
-	// Dev-time override: copy from disk if TEMPLATE_PATH is set
+// readEmbeddedFile reads a file from the embedded filesystem.
+func readEmbeddedFile(fsys fs.FS, src string) ([]byte, error) {
+	// Dev-time override: read from disk if TEMPLATE_PATH is set
 	if dir := os.Getenv("TEMPLATE_PATH"); dir != "" {
 		// Extract filename from src path
 		filename := filepath.Base(src)
@@ -275,14 +614,24 @@ func copyEmbeddedFile(fsys fs.FS, src, dst string) error {
 		fmt.Printf("Copying file from disk: %s\n", srcPath)
 		content, err := os.ReadFile(srcPath)
 		if err != nil {
-			return fmt.Errorf("failed to read file from disk: %w", err)
+			return nil, fmt.Errorf("failed to read file from disk: %w", err)
 		}
-		return os.WriteFile(dst, content, 0644)
+		return content, nil
 	}
 	// Production: read from embedded filesystem
 	content, err := fs.ReadFile(fsys, src)
 	if err != nil {
-		return fmt.Errorf("failed to read embedded file: %w", err)
+		return nil, fmt.Errorf("failed to read embedded file: %w", err)
+	}
+	return content, nil
+}
+
+// executePage renders the named template and writes it to filename, leaving the file
+// untouched when the rendered page is identical to what is already there.
+func executePage(tmpl *template.Template, name, filename string, data any) error {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return err
 	}
-	return os.WriteFile(dst, content, 0644)
+	return writeFileIfChanged(filename, buf.Bytes())
 }
@@ -0,0 +1,774 @@
+// Client-side enhancements for the generated unreleasedcommits pages.
+// Each feature checks for the elements it needs so the same script can be
+// included on every page.
+(function () {
+    'use strict';
+
+    // Filters and column sorting for the repository table on the index page.
+    // The current state is mirrored into the query string so a filtered view
+    // can be shared as a link, for example
+    // index.html?min_commits=20&sort=days_behind&dir=desc
+    function initRepoTable() {
+        var table = document.getElementById('repo-table');
+        if (!table) {
+            return;
+        }
+
+        // With groups configured, each group is its own tbody led by a header row
+        var rows = Array.prototype.slice.call(table.querySelectorAll('tbody tr:not(.group-header)'));
+        var groupHeaders = table.querySelectorAll('tr.group-header');
+        var headers = table.querySelectorAll('th[data-sort]');
+        var clear = document.getElementById('topic-clear');
+        var numberFilters = document.querySelectorAll('input[data-filter]');
+        var licenseFilter = document.getElementById('license-filter');
+        var search = document.getElementById('repo-search');
+        var count = document.getElementById('repo-count');
+        // Generating with -hide-zero checks the toggle by default; the query string
+        // only records a choice that differs from the default
+        var hideZero = document.getElementById('hide-zero');
+        var hideZeroDefault = !!hideZero && hideZero.dataset.default === 'on';
+
+        var state = readState();
+
+        // Repositories pinned in the config, and those pinned with their star in this
+        // browser, stay at the top of the table (or of their group) whatever the sort
+        var pinKey = 'unreleasedcommits-pinned:' + location.pathname.replace(/[^/]*$/, '');
+        var localPins = readPins();
+
+        function readPins() {
+            try {
+                return JSON.parse(localStorage.getItem(pinKey)) || [];
+            } catch (e) {
+                return [];
+            }
+        }
+
+        function isPinned(row) {
+            return row.dataset.pinned === 'config' || localPins.indexOf(row.dataset.name) !== -1;
+        }
+
+        function readState() {
+            var params = new URLSearchParams(location.search);
+            var topics = {};
+            (params.get('topics') || '').split(',').forEach(function (topic) {
+                if (topic) {
+                    topics[topic] = true;
+                }
+            });
+            var mins = {};
+            numberFilters.forEach(function (input) {
+                var value = parseInt(params.get(input.dataset.filter), 10);
+                if (!isNaN(value)) {
+                    mins[input.dataset.filter] = value;
+                }
+            });
+            return {
+                topics: topics,
+                mins: mins,
+                license: params.get('license') || '',
+                query: params.get('q') || '',
+                hideZero: params.has('hide_zero') ? params.get('hide_zero') === '1' : hideZeroDefault,
+                sort: params.get('sort') || '',
+                dir: params.get('dir') === 'desc' ? 'desc' : 'asc'
+            };
+        }
+
+        function writeState() {
+            var params = new URLSearchParams(location.search);
+            var topics = Object.keys(state.topics);
+            setParam(params, 'topics', topics.join(','));
+            numberFilters.forEach(function (input) {
+                var key = input.dataset.filter;
+                setParam(params, key, key in state.mins ? String(state.mins[key]) : '');
+            });
+            setParam(params, 'license', state.license);
+            setParam(params, 'q', state.query);
+            setParam(params, 'hide_zero', state.hideZero === hideZeroDefault ? '' : (state.hideZero ? '1' : '0'));
+            setParam(params, 'sort', state.sort);
+            setParam(params, 'dir', state.sort && state.dir === 'desc' ? 'desc' : '');
+            var query = params.toString();
+            history.replaceState(null, '', location.pathname + (query ? '?' + query : '') + location.hash);
+        }
+
+        function setParam(params, key, value) {
+            if (value) {
+                params.set(key, value);
+            } else {
+                params.delete(key);
+            }
+        }
+
+        function matches(row) {
+            var topics = (row.dataset.topics || '').split(' ');
+            var hasTopics = Object.keys(state.topics).every(function (topic) {
+                return topics.indexOf(topic) !== -1;
+            });
+            // "none" matches repositories without a license
+            var license = row.dataset.license || 'none';
+            var hasLicense = !state.license || license === state.license;
+            var hasCommits = !state.hideZero || Number(row.dataset.commits) > 0;
+            var hasName = row.dataset.name.toLowerCase().indexOf(state.query.toLowerCase()) !== -1;
+            return hasTopics && hasLicense && hasCommits && hasName && Array.prototype.every.call(numberFilters, function (input) {
+                var key = input.dataset.filter;
+                return !(key in state.mins) || Number(row.dataset[toDatasetKey(input.dataset.column)]) >= state.mins[key];
+            });
+        }
+
+        function sortRows() {
+            var key = toDatasetKey(state.sort);
+            var sorted = rows.slice();
+            if (state.sort) {
+                sorted.sort(function (a, b) {
+                    var x = a.dataset[key];
+                    var y = b.dataset[key];
+                    var cmp = isNaN(Number(x)) || isNaN(Number(y)) ? x.localeCompare(y) : Number(x) - Number(y);
+                    return state.dir === 'desc' ? -cmp : cmp;
+                });
+            }
+            sorted = sorted.filter(isPinned).concat(sorted.filter(function (row) {
+                return !isPinned(row);
+            }));
+            // Rows are sorted within their group
+            sorted.forEach(function (row) {
+                row.parentNode.appendChild(row);
+            });
+        }
+
+        function apply() {
+            document.querySelectorAll('.topic-chip[data-topic]').forEach(function (chip) {
+                chip.classList.toggle('active', !!state.topics[chip.dataset.topic]);
+                chip.setAttribute('aria-pressed', !!state.topics[chip.dataset.topic]);
+            });
+            if (clear) {
+                clear.hidden = Object.keys(state.topics).length === 0;
+            }
+            numberFilters.forEach(function (input) {
+                var key = input.dataset.filter;
+                input.value = key in state.mins ? state.mins[key] : '';
+            });
+            if (licenseFilter) {
+                licenseFilter.value = state.license;
+            }
+            if (hideZero) {
+                hideZero.checked = state.hideZero;
+            }
+            if (search && search.value !== state.query) {
+                search.value = state.query;
+            }
+            headers.forEach(function (th) {
+                var sorted = th.dataset.sort === state.sort;
+                th.classList.toggle('sorted-asc', sorted && state.dir === 'asc');
+                th.classList.toggle('sorted-desc', sorted && state.dir === 'desc');
+                th.setAttribute('aria-sort', sorted ? (state.dir === 'desc' ? 'descending' : 'ascending') : 'none');
+            });
+            rows.forEach(function (row) {
+                row.classList.toggle('filtered-out', !matches(row));
+                row.classList.toggle('pinned-repo', isPinned(row));
+                var pin = row.querySelector('.pin-toggle:not([disabled])');
+                if (pin) {
+                    pin.hidden = false;
+                    pin.classList.toggle('pinned', isPinned(row));
+                    pin.setAttribute('aria-pressed', isPinned(row));
+                }
+            });
+            groupHeaders.forEach(function (header) {
+                header.classList.toggle('filtered-out', !header.parentNode.querySelector('tr:not(.group-header):not(.filtered-out)'));
+            });
+            if (count) {
+                var shown = rows.filter(function (row) {
+                    return !row.classList.contains('filtered-out');
+                }).length;
+                count.textContent = 'Showing ' + shown + ' of ' + rows.length + ' repositories.';
+            }
+            sortRows();
+        }
+
+        function update() {
+            apply();
+            writeState();
+        }
+
+        document.addEventListener('click', function (event) {
+            var chip = event.target.closest('button.topic-chip[data-topic]');
+            if (!chip) {
+                return;
+            }
+            var topic = chip.dataset.topic;
+            if (state.topics[topic]) {
+                delete state.topics[topic];
+            } else {
+                state.topics[topic] = true;
+            }
+            update();
+        });
+
+        if (clear) {
+            clear.addEventListener('click', function () {
+                state.topics = {};
+                update();
+            });
+        }
+
+        table.addEventListener('click', function (event) {
+            var pin = event.target.closest('button.pin-toggle');
+            if (!pin) {
+                return;
+            }
+            var name = pin.closest('tr').dataset.name;
+            var i = localPins.indexOf(name);
+            if (i === -1) {
+                localPins.push(name);
+            } else {
+                localPins.splice(i, 1);
+            }
+            try {
+                localStorage.setItem(pinKey, JSON.stringify(localPins));
+            } catch (e) {
+                // Pins last for this page view when storage is unavailable
+            }
+            apply();
+        });
+
+        numberFilters.forEach(function (input) {
+            input.addEventListener('input', function () {
+                var value = parseInt(input.value, 10);
+                if (isNaN(value)) {
+                    delete state.mins[input.dataset.filter];
+                } else {
+                    state.mins[input.dataset.filter] = value;
+                }
+                update();
+            });
+        });
+
+        if (licenseFilter) {
+            licenseFilter.addEventListener('change', function () {
+                state.license = licenseFilter.value;
+                update();
+            });
+        }
+
+        if (search) {
+            search.addEventListener('input', function () {
+                state.query = search.value.trim();
+                update();
+            });
+        }
+
+        if (hideZero) {
+            hideZero.addEventListener('change', function () {
+                state.hideZero = hideZero.checked;
+                update();
+            });
+        }
+
+        // Clicking a header sorts ascending, then descending, then restores the original order
+        headers.forEach(function (th) {
+            th.addEventListener('click', function () {
+                if (state.sort !== th.dataset.sort) {
+                    state.sort = th.dataset.sort;
+                    state.dir = 'asc';
+                } else if (state.dir === 'asc') {
+                    state.dir = 'desc';
+                } else {
+                    state.sort = '';
+                    state.dir = 'asc';
+                }
+                update();
+            });
+            th.addEventListener('keydown', function (event) {
+                if (event.key === 'Enter' || event.key === ' ') {
+                    event.preventDefault();
+                    th.click();
+                }
+            });
+        });
+
+        apply();
+    }
+
+    // Keyboard shortcuts on the index: "/" focuses the search box, j and k move
+    // between the repositories shown, and Enter opens the selected one.
+    function initKeyboardNavigation() {
+        var table = document.getElementById('repo-table');
+        if (!table) {
+            return;
+        }
+        var search = document.getElementById('repo-search');
+        var selected = null;
+
+        function shownRows() {
+            return Array.prototype.filter.call(table.querySelectorAll('tbody tr:not(.group-header)'), function (row) {
+                return !row.classList.contains('filtered-out');
+            });
+        }
+
+        function select(row) {
+            if (selected) {
+                selected.classList.remove('keyboard-selected');
+            }
+            selected = row;
+            row.classList.add('keyboard-selected');
+            row.tabIndex = -1;
+            row.focus();
+            row.scrollIntoView({ block: 'nearest' });
+        }
+
+        document.addEventListener('keydown', function (event) {
+            if (event.ctrlKey || event.metaKey || event.altKey || event.defaultPrevented) {
+                return;
+            }
+            var target = event.target;
+            if (target.closest('input, select, textarea, [contenteditable]')) {
+                if (event.key === 'Escape' && target === search) {
+                    search.blur();
+                }
+                return;
+            }
+            if (event.key === '/' && search) {
+                event.preventDefault();
+                search.focus();
+                search.select();
+            } else if (event.key === 'j' || event.key === 'k') {
+                var rows = shownRows();
+                if (rows.length === 0) {
+                    return;
+                }
+                var step = event.key === 'j' ? 1 : -1;
+                var index = rows.indexOf(selected);
+                if (index === -1) {
+                    index = step > 0 ? 0 : rows.length - 1;
+                } else {
+                    index = Math.max(0, Math.min(rows.length - 1, index + step));
+                }
+                event.preventDefault();
+                select(rows[index]);
+            } else if (event.key === 'Enter' && selected && (target === selected || target === document.body)) {
+                var link = selected.querySelector('a.repo-link');
+                if (link) {
+                    event.preventDefault();
+                    link.click();
+                }
+            }
+        });
+    }
+
+    // Filter chips on repository pages show only the matching commits, with each
+    // filter's selection kept in its query parameter ("types", "authors", "labels").
+    // A commit must pass every filter: with data-match="all" it needs every selected
+    // value (pull request labels), otherwise any of them (types, authors). The count
+    // on each chip is how many commits it would show given the other filters.
+    function initCommitFilters() {
+        var filters = Array.prototype.slice.call(document.querySelectorAll('.commit-filter'));
+        if (filters.length === 0) {
+            return;
+        }
+
+        var list = document.querySelector('.commits-list[data-batch]');
+        var cards = Array.prototype.slice.call(list.querySelectorAll('.commit-card'));
+        var params = new URLSearchParams(location.search);
+        var states = filters.map(function (filter) {
+            var selected = {};
+            (params.get(filter.dataset.param) || '').split(',').forEach(function (value) {
+                if (value) {
+                    selected[value] = true;
+                }
+            });
+            return { filter: filter, selected: selected };
+        });
+
+        function cardValues(card, state) {
+            return (card.dataset[state.filter.dataset.attr] || '').split(',');
+        }
+
+        function matches(card, state) {
+            var values = Object.keys(state.selected);
+            if (values.length === 0) {
+                return true;
+            }
+            var own = cardValues(card, state);
+            var has = function (value) {
+                return own.indexOf(value) !== -1;
+            };
+            return state.filter.dataset.match === 'all' ? values.every(has) : values.some(has);
+        }
+
+        function updateCounts() {
+            states.forEach(function (state) {
+                var others = cards.filter(function (card) {
+                    return states.every(function (other) {
+                        return other === state || matches(card, other);
+                    });
+                });
+                state.filter.querySelectorAll('.filter-chip').forEach(function (chip) {
+                    var count = others.filter(function (card) {
+                        return cardValues(card, state).indexOf(chip.dataset.value) !== -1;
+                    }).length;
+                    chip.querySelector('.label-count').textContent = count;
+                });
+            });
+        }
+
+        function apply() {
+            var active = false;
+            var params = new URLSearchParams(location.search);
+            states.forEach(function (state) {
+                var values = Object.keys(state.selected);
+                state.filter.querySelectorAll('.filter-chip').forEach(function (chip) {
+                    chip.classList.toggle('active', !!state.selected[chip.dataset.value]);
+                    chip.setAttribute('aria-pressed', !!state.selected[chip.dataset.value]);
+                });
+                state.filter.querySelector('.topic-clear').hidden = values.length === 0;
+                if (values.length > 0) {
+                    active = true;
+                    params.set(state.filter.dataset.param, values.join(','));
+                } else {
+                    params.delete(state.filter.dataset.param);
+                }
+            });
+            if (active) {
+                // Filtering applies to every commit, not just the batches revealed so far
+                loadAllChunks(list).catch(function () {});
+                cards.forEach(function (card) {
+                    card.hidden = false;
+                });
+                list.parentNode.querySelectorAll('.show-more').forEach(function (button) {
+                    button.remove();
+                });
+            }
+            cards.forEach(function (card) {
+                card.classList.toggle('filtered-out', !states.every(function (state) {
+                    return matches(card, state);
+                }));
+            });
+            updateDateGroups(list);
+            // Until every chunk is loaded the counts from the page cover more commits
+            if (!hasMoreChunks(list)) {
+                updateCounts();
+            }
+
+            var query = params.toString();
+            history.replaceState(null, '', location.pathname + (query ? '?' + query : '') + location.hash);
+        }
+
+        states.forEach(function (state) {
+            state.filter.querySelectorAll('.filter-chip').forEach(function (chip) {
+                chip.addEventListener('click', function () {
+                    var value = chip.dataset.value;
+                    if (state.selected[value]) {
+                        delete state.selected[value];
+                    } else {
+                        state.selected[value] = true;
+                    }
+                    apply();
+                });
+            });
+
+            state.filter.querySelector('.topic-clear').addEventListener('click', function () {
+                state.selected = {};
+                apply();
+            });
+        });
+
+        list.addEventListener('commits-loaded', function () {
+            cards = Array.prototype.slice.call(list.querySelectorAll('.commit-card'));
+            apply();
+        });
+
+        apply();
+    }
+
+    // toDatasetKey converts a snake_case query parameter to its camelCase dataset key.
+    function toDatasetKey(name) {
+        return name.replace(/_([a-z])/g, function (match, letter) {
+            return letter.toUpperCase();
+        });
+    }
+
+    // Long commit lists on repository pages start with a single batch of
+    // commits visible and a button that reveals the next batch. Chunks that
+    // are not part of the page are fetched as the button scrolls into view.
+    function initShowMore() {
+        document.querySelectorAll('.commits-list[data-batch]').forEach(function (list) {
+            var batch = parseInt(list.dataset.batch, 10);
+            var total = parseInt(list.dataset.total, 10);
+            var cards = Array.prototype.slice.call(list.querySelectorAll('.commit-card'));
+            if (!batch || total <= batch) {
+                return;
+            }
+
+            var shown = batch;
+            cards.slice(shown).forEach(function (card) {
+                card.hidden = true;
+            });
+            updateDateGroups(list);
+
+            var button = document.createElement('button');
+            button.type = 'button';
+            button.className = 'show-more';
+            list.insertAdjacentElement('afterend', button);
+
+            function update() {
+                var remaining = (hasMoreChunks(list) ? total : cards.length) - shown;
+                button.textContent = 'Show ' + Math.min(batch, remaining) + ' more (' + remaining + ' remaining)';
+                button.hidden = remaining <= 0;
+            }
+
+            function showUpTo(count) {
+                if (count > cards.length && hasMoreChunks(list)) {
+                    loadNextChunk(list).then(function () {
+                        showUpTo(count);
+                    }, update);
+                    return;
+                }
+                cards.slice(shown, count).forEach(function (card) {
+                    card.hidden = false;
+                });
+                shown = Math.max(shown, count);
+                updateDateGroups(list);
+                update();
+            }
+
+            // Reveal enough batches to show a commit linked to by its #sha anchor
+            function revealTarget() {
+                var id = decodeURIComponent(location.hash.slice(1));
+                var target = id && document.getElementById(id);
+                if (id && !target && hasMoreChunks(list)) {
+                    loadAllChunks(list).then(revealTarget, function () {});
+                    return;
+                }
+                var index = cards.indexOf(target);
+                if (index < shown) {
+                    return;
+                }
+                showUpTo(Math.ceil((index + 1) / batch) * batch);
+                target.scrollIntoView();
+            }
+
+            button.addEventListener('click', function () {
+                showUpTo(shown + batch);
+            });
+            window.addEventListener('hashchange', revealTarget);
+
+            list.addEventListener('commits-loaded', function () {
+                cards = Array.prototype.slice.call(list.querySelectorAll('.commit-card'));
+                // Once a filter is in use every commit is shown and the button is gone
+                if (button.isConnected) {
+                    cards.slice(shown).forEach(function (card) {
+                        card.hidden = true;
+                    });
+                }
+                updateDateGroups(list);
+                update();
+            });
+
+            // Fetch the next chunk before the reader reaches the end of the loaded commits
+            if (hasMoreChunks(list) && 'IntersectionObserver' in window) {
+                new IntersectionObserver(function (entries) {
+                    if (entries[0].isIntersecting && shown + batch > cards.length && hasMoreChunks(list)) {
+                        loadNextChunk(list).catch(function () {});
+                    }
+                }, { rootMargin: '600px' }).observe(button);
+            }
+            // A printout has no "show more" button, so it lists every commit
+            window.addEventListener('beforeprint', function () {
+                showUpTo(cards.length);
+            });
+
+            update();
+            revealTarget();
+        });
+    }
+
+    // Commits after the first chunk of a long list are in <repo>/commits/<n>.json,
+    // fetched one chunk at a time when needed. The list dispatches "commits-loaded"
+    // after each chunk is added.
+    function hasMoreChunks(list) {
+        return !list.dataset.chunkError && (list.loadedChunks || 0) < Number(list.dataset.chunks || 0);
+    }
+
+    function loadNextChunk(list) {
+        if (!list.chunkRequest) {
+            var n = (list.loadedChunks || 0) + 1;
+            list.chunkRequest = fetch(list.dataset.chunkUrl + n + '.json').then(function (response) {
+                if (!response.ok) {
+                    throw new Error('HTTP ' + response.status);
+                }
+                return response.json();
+            }).then(function (chunk) {
+                list.chunkRequest = null;
+                list.insertAdjacentHTML('beforeend', chunk.html);
+                list.loadedChunks = n;
+                list.dispatchEvent(new CustomEvent('commits-loaded'));
+            }, function (error) {
+                list.chunkRequest = null;
+                chunkFailed(list);
+                throw error;
+            });
+        }
+        return list.chunkRequest;
+    }
+
+    function loadAllChunks(list) {
+        if (!hasMoreChunks(list)) {
+            return Promise.resolve();
+        }
+        return loadNextChunk(list).then(function () {
+            return loadAllChunks(list);
+        });
+    }
+
+    // Browsers refuse to fetch files for a page opened from disk, so the rest of
+    // the list is linked on GitHub instead
+    function chunkFailed(list) {
+        list.dataset.chunkError = 'true';
+        var note = document.createElement('p');
+        note.className = 'section-note truncation-note';
+        note.textContent = 'The remaining commits could not be loaded. ';
+        var link = document.createElement('a');
+        link.href = list.dataset.compareUrl;
+        link.target = '_blank';
+        link.className = 'github-link';
+        link.textContent = 'View the full comparison on GitHub';
+        note.appendChild(link);
+        list.insertAdjacentElement('afterend', note);
+    }
+
+    // Date group headings in a commit list are hidden while none of the commits
+    // under them are shown, whether not yet revealed or filtered out.
+    function updateDateGroups(list) {
+        var heading = null;
+        var visible = false;
+        Array.prototype.forEach.call(list.children, function (child) {
+            if (child.classList.contains('date-group')) {
+                if (heading) {
+                    heading.hidden = !visible;
+                }
+                heading = child;
+                visible = false;
+            } else if (!child.hidden && !child.classList.contains('filtered-out')) {
+                visible = true;
+            }
+        });
+        if (heading) {
+            heading.hidden = !visible;
+        }
+    }
+
+    // Copy buttons next to commit SHAs put the full SHA on the clipboard.
+    function initCopySha() {
+        document.querySelectorAll('.copy-sha').forEach(function (button) {
+            button.addEventListener('click', function (event) {
+                // Keep clicks inside a collapsed merge commit's summary from toggling it
+                event.preventDefault();
+                copyText(button.dataset.sha).then(function () {
+                    button.textContent = 'Copied';
+                    button.classList.add('copied');
+                    setTimeout(function () {
+                        button.textContent = 'Copy';
+                        button.classList.remove('copied');
+                    }, 1500);
+                });
+            });
+        });
+    }
+
+    // Export buttons on the index download the rows currently shown in the table,
+    // in their current order, from the summaries embedded in the page.
+    function initExport() {
+        var source = document.getElementById('export-data');
+        if (!source) {
+            return;
+        }
+        var byName = {};
+        (JSON.parse(source.textContent) || []).forEach(function (repo) {
+            byName[repo.name] = repo;
+        });
+
+        document.querySelectorAll('.export-button').forEach(function (button) {
+            button.addEventListener('click', function () {
+                var repos = [];
+                document.querySelectorAll('#repo-table tbody tr:not(.group-header):not(.filtered-out)').forEach(function (row) {
+                    if (byName[row.dataset.name]) {
+                        repos.push(byName[row.dataset.name]);
+                    }
+                });
+                if (button.dataset.export === 'csv') {
+                    download('repositories.csv', 'text/csv', toCSV(repos));
+                } else {
+                    download('repositories.json', 'application/json', JSON.stringify(repos, null, 2) + '\n');
+                }
+            });
+        });
+    }
+
+    // toCSV writes one column per field, in the order fields first appear. Lists of
+    // values such as topics are joined with spaces, nested objects kept as JSON.
+    function toCSV(repos) {
+        var columns = [];
+        repos.forEach(function (repo) {
+            Object.keys(repo).forEach(function (key) {
+                if (columns.indexOf(key) === -1) {
+                    columns.push(key);
+                }
+            });
+        });
+        var lines = [columns.map(csvField).join(',')];
+        repos.forEach(function (repo) {
+            lines.push(columns.map(function (key) {
+                var value = repo[key];
+                if (value === undefined || value === null) {
+                    value = '';
+                } else if (Array.isArray(value) && value.every(function (v) { return typeof v !== 'object'; })) {
+                    value = value.join(' ');
+                } else if (typeof value === 'object') {
+                    value = JSON.stringify(value);
+                }
+                return csvField(String(value));
+            }).join(','));
+        });
+        return lines.join('\r\n') + '\r\n';
+    }
+
+    function csvField(value) {
+        return /[",\r\n]/.test(value) ? '"' + value.replace(/"/g, '""') + '"' : value;
+    }
+
+    function download(filename, type, content) {
+        var url = URL.createObjectURL(new Blob([content], { type: type }));
+        var link = document.createElement('a');
+        link.href = url;
+        link.download = filename;
+        document.body.appendChild(link);
+        link.click();
+        document.body.removeChild(link);
+        setTimeout(function () {
+            URL.revokeObjectURL(url);
+        }, 0);
+    }
+
+    function copyText(text) {
+        if (navigator.clipboard && window.isSecureContext) {
+            return navigator.clipboard.writeText(text);
+        }
+        // Pages opened from disk are not a secure context, so fall back to a hidden textarea
+        var area = document.createElement('textarea');
+        area.value = text;
+        area.style.position = 'fixed';
+        area.style.opacity = '0';
+        document.body.appendChild(area);
+        area.select();
+        document.execCommand('copy');
+        document.body.removeChild(area);
+        return Promise.resolve();
+    }
+
+    document.addEventListener('DOMContentLoaded', function () {
+        initRepoTable();
+        initKeyboardNavigation();
+        initShowMore();
+        initCommitFilters();
+        initCopySha();
+        initExport();
+    });
+})();
@@ -0,0 +1,24 @@
+package main
+
+// Built-in themes for -theme, defined as CSS variable sets in style.css
+const (
+	ThemeDefault      = "default"
+	ThemeCompact      = "compact"
+	ThemeHighContrast = "high-contrast"
+)
+
+// siteTheme is the theme the pages being generated are rendered with
+var siteTheme = ThemeDefault
+
+func validTheme(theme string) bool {
+	switch theme {
+	case ThemeDefault, ThemeCompact, ThemeHighContrast:
+		return true
+	}
+	return false
+}
+
+// currentTheme returns the theme name for the data-theme attribute on each page.
+func currentTheme() string {
+	return siteTheme
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"time"
+)
+
+// HistoryPoint is how many unreleased commits a repository had on a day. Each crawl
+// records one; -backfill reconstructs weekly points from the release history
+type HistoryPoint struct {
+	Date              time.Time `json:"date"`
+	UnreleasedCommits int       `json:"unreleased_commits"`
+	Backfilled        bool      `json:"backfilled,omitempty"`
+}
+
+// recordHistory returns the history with the count for the day of now, replacing an
+// earlier point from the same day.
+func recordHistory(history []HistoryPoint, now time.Time, count int) []HistoryPoint {
+	return mergeHistory(history, []HistoryPoint{{Date: truncateToDay(now), UnreleasedCommits: count}})
+}
+
+// mergeHistory combines two histories, oldest first. On a day present in both, a
+// recorded point wins over a backfilled one and otherwise the point from added wins.
+func mergeHistory(history, added []HistoryPoint) []HistoryPoint {
+	byDay := make(map[time.Time]HistoryPoint, len(history)+len(added))
+	for _, p := range history {
+		byDay[truncateToDay(p.Date)] = p
+	}
+	for _, p := range added {
+		day := truncateToDay(p.Date)
+		if existing, ok := byDay[day]; ok && p.Backfilled && !existing.Backfilled {
+			continue
+		}
+		p.Date = day
+		byDay[day] = p
+	}
+
+	merged := make([]HistoryPoint, 0, len(byDay))
+	for _, p := range byDay {
+		merged = append(merged, p)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Date.Before(merged[j].Date)
+	})
+	return merged
+}
+
+// backfillHistory reconstructs the number of unreleased commits at the start of every
+// week since the oldest commit in the release history. A commit counts as unreleased
+// from its timestamp until the first stable release containing it was published;
+// commits in a pre-release wait for the next stable release, and the current unreleased
+// commits have not been released yet.
+func backfillHistory(releases []ReleaseInfo, unreleased []CommitInfo, now time.Time) []HistoryPoint {
+	type span struct {
+		from, until time.Time
+	}
+	var spans []span
+
+	for i, rel := range releases {
+		var releasedAt time.Time
+		for _, later := range releases[i:] {
+			if !later.Prerelease {
+				releasedAt = later.PublishedAt
+				break
+			}
+		}
+		for _, c := range rel.Commits {
+			spans = append(spans, span{c.Timestamp, releasedAt})
+		}
+	}
+	for _, c := range unreleased {
+		spans = append(spans, span{c.Timestamp, time.Time{}})
+	}
+	if len(spans) == 0 {
+		return nil
+	}
+
+	first := spans[0].from
+	for _, s := range spans {
+		if s.from.Before(first) {
+			first = s.from
+		}
+	}
+
+	var points []HistoryPoint
+	for week := isoWeekStart(first); !week.After(now); week = week.AddDate(0, 0, 7) {
+		count := 0
+		for _, s := range spans {
+			if !s.from.After(week) && (s.until.IsZero() || s.until.After(week)) {
+				count++
+			}
+		}
+		points = append(points, HistoryPoint{Date: week, UnreleasedCommits: count, Backfilled: true})
+	}
+	return points
+}
+
+// Trend line chart layout
+const (
+	trendChartWidth  = 640
+	trendChartLeft   = 28
+	trendPlotHeight  = 100
+	trendChartTop    = 8
+	trendAxisHeight  = 18
+	trendPointRadius = 2.5
+)
+
+// unreleasedTrendSVG renders the history as a line chart of unreleased commits over
+// time as inline SVG. It needs at least two points.
+func unreleasedTrendSVG(history []HistoryPoint) template.HTML {
+	if len(history) < 2 {
+		return ""
+	}
+
+	maxCount := 1
+	for _, p := range history {
+		if p.UnreleasedCommits > maxCount {
+			maxCount = p.UnreleasedCommits
+		}
+	}
+	first, last := history[0].Date, history[len(history)-1].Date
+	span := last.Sub(first).Seconds()
+
+	height := trendChartTop + trendPlotHeight + trendAxisHeight
+	baseline := trendChartTop + trendPlotHeight
+	plotWidth := float64(trendChartWidth - trendChartLeft - 4)
+	x := func(t time.Time) float64 {
+		return float64(trendChartLeft) + t.Sub(first).Seconds()/span*plotWidth
+	}
+	y := func(n int) float64 {
+		return float64(baseline) - float64(n*trendPlotHeight)/float64(maxCount)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg class="trend-chart" width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-label="Unreleased commits over time">`, trendChartWidth, height, trendChartWidth, height)
+	fmt.Fprintf(&b, `<text x="0" y="%d" class="calendar-label">%d</text>`, trendChartTop+8, maxCount)
+	fmt.Fprintf(&b, `<text x="0" y="%d" class="calendar-label">0</text>`, baseline)
+	fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%d" class="chart-axis"/>`, trendChartLeft-2, baseline, trendChartWidth, baseline)
+
+	coords := make([]string, len(history))
+	for i, p := range history {
+		coords[i] = fmt.Sprintf("%.1f,%.1f", x(p.Date), y(p.UnreleasedCommits))
+	}
+	fmt.Fprintf(&b, `<polyline points="%s" class="trend-line"/>`, strings.Join(coords, " "))
+
+	for _, p := range history {
+		class := "trend-point"
+		note := ""
+		if p.Backfilled {
+			class += " trend-backfilled"
+			note = " (reconstructed)"
+		}
+		fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="%.1f" class="%s"><title>%s: %s%s</title></circle>`,
+			x(p.Date), y(p.UnreleasedCommits), trendPointRadius, class, p.Date.Format("Jan 2, 2006"), pluralize(p.UnreleasedCommits, "unreleased commit"), note)
+	}
+
+	fmt.Fprintf(&b, `<text x="%d" y="%d" class="calendar-label">%s</text>`, trendChartLeft, baseline+trendAxisHeight-4, first.Format("Jan 2, 2006"))
+	fmt.Fprintf(&b, `<text x="%d" y="%d" class="calendar-label" text-anchor="end">%s</text>`, trendChartWidth, baseline+trendAxisHeight-4, last.Format("Jan 2, 2006"))
+
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
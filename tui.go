@@ -0,0 +1,293 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// tuiPageSize is how many commits are listed per page in the repository view
+const tuiPageSize = 20
+
+// tui is a prompt-driven terminal dashboard over the crawled data. It only needs line
+// input, so it works in any terminal or SSH session without switching to raw mode.
+type tui struct {
+	in  *bufio.Scanner
+	out io.Writer
+
+	repos     []RepositoryData
+	summaries []APIRepoSummary
+
+	sortField string
+	sortDesc  bool
+	filter    string
+	query     queryExpr
+	message   string
+
+	// rows holds the indexes into repos currently listed, in display order
+	rows []int
+}
+
+func runTUI() error {
+	repos, err := loadAllRepositoryData("data")
+	if err != nil {
+		return err
+	}
+
+	t := &tui{
+		in:        bufio.NewScanner(os.Stdin),
+		out:       os.Stdout,
+		repos:     repos,
+		sortField: "name",
+	}
+	for _, repo := range repos {
+		t.summaries = append(t.summaries, apiRepoSummary(repo))
+	}
+	return t.run()
+}
+
+func (t *tui) run() error {
+	for {
+		t.refreshRows()
+		t.drawList()
+
+		line, ok := t.prompt()
+		if !ok {
+			return t.in.Err()
+		}
+
+		cmd, arg, _ := strings.Cut(line, " ")
+		arg = strings.TrimSpace(arg)
+		switch cmd {
+		case "":
+		case "q", "quit":
+			return nil
+		case "s", "sort":
+			t.setSort(arg)
+		case "f", "filter":
+			t.setFilter(arg)
+		case "?", "h", "help":
+			t.message = "Commands: <number> open repository, s <field> sort (again to reverse), f <query> filter, f clear filter, q quit"
+		default:
+			n, err := strconv.Atoi(cmd)
+			if err != nil || n < 1 || n > len(t.rows) {
+				t.message = fmt.Sprintf("Unknown command %q, type ? for help", line)
+				continue
+			}
+			if quit := t.showRepo(t.repos[t.rows[n-1]]); quit {
+				return nil
+			}
+		}
+	}
+}
+
+// prompt reads the next command, reporting false at the end of input.
+func (t *tui) prompt() (string, bool) {
+	fmt.Fprint(t.out, "> ")
+	if !t.in.Scan() {
+		fmt.Fprintln(t.out)
+		return "", false
+	}
+	return strings.TrimSpace(t.in.Text()), true
+}
+
+func (t *tui) setSort(field string) {
+	field = strings.ToLower(field)
+	if _, ok := queryFields[field]; !ok {
+		t.message = fmt.Sprintf("Unknown sort field %q", field)
+		return
+	}
+	if field == t.sortField {
+		t.sortDesc = !t.sortDesc
+		return
+	}
+	t.sortField = field
+	// Numbers are most interesting largest first
+	switch queryFields[field](APIRepoSummary{}).(type) {
+	case int, *int:
+		t.sortDesc = true
+	default:
+		t.sortDesc = false
+	}
+}
+
+func (t *tui) setFilter(expr string) {
+	if expr == "" {
+		t.filter, t.query = "", nil
+		return
+	}
+	query, err := parseQuery(expr)
+	if err != nil {
+		t.message = fmt.Sprintf("Invalid filter: %v", err)
+		return
+	}
+	t.filter, t.query = expr, query
+}
+
+func (t *tui) refreshRows() {
+	t.rows = t.rows[:0]
+	for i, summary := range t.summaries {
+		if t.query != nil {
+			ok, err := t.query.eval(summary)
+			if err != nil {
+				t.message = fmt.Sprintf("Filter error: %v", err)
+				t.filter, t.query = "", nil
+				t.refreshRows()
+				return
+			}
+			if !ok {
+				continue
+			}
+		}
+		t.rows = append(t.rows, i)
+	}
+
+	value := queryFields[t.sortField]
+	slices.SortStableFunc(t.rows, func(a, b int) int {
+		c := compareValues(value(t.summaries[a]), value(t.summaries[b]))
+		if t.sortDesc {
+			return -c
+		}
+		return c
+	})
+}
+
+// compareValues orders two field values of the same kind.
+func compareValues(a, b any) int {
+	switch x := a.(type) {
+	case int:
+		return cmp.Compare(x, b.(int))
+	case *int:
+		// Values that were not collected sort before every number
+		y := b.(*int)
+		switch {
+		case x == nil && y == nil:
+			return 0
+		case x == nil:
+			return -1
+		case y == nil:
+			return 1
+		}
+		return cmp.Compare(*x, *y)
+	case string:
+		return strings.Compare(strings.ToLower(x), strings.ToLower(b.(string)))
+	case bool:
+		if x == b.(bool) {
+			return 0
+		}
+		if x {
+			return 1
+		}
+		return -1
+	case []string:
+		return strings.Compare(strings.Join(x, ","), strings.Join(b.([]string), ","))
+	}
+	return 0
+}
+
+func (t *tui) clear() {
+	if info, err := os.Stdout.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		fmt.Fprint(t.out, "\x1b[H\x1b[2J")
+	}
+}
+
+func (t *tui) drawList() {
+	t.clear()
+
+	direction := "ascending"
+	if t.sortDesc {
+		direction = "descending"
+	}
+	fmt.Fprintf(t.out, "Unreleased Commits - %d of %d repositories, sorted by %s (%s)\n", len(t.rows), len(t.repos), t.sortField, direction)
+	if t.filter != "" {
+		fmt.Fprintf(t.out, "Filter: %s\n", t.filter)
+	}
+	fmt.Fprintln(t.out)
+
+	w := tabwriter.NewWriter(t.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tREPOSITORY\tBASELINE\tCOMMITS\tDAYS BEHIND\tDAYS SINCE RELEASE\tSTATUS")
+	for n, i := range t.rows {
+		r := t.summaries[i]
+		baseline := r.BaselineTag
+		if r.NeverReleased {
+			baseline = "(never released)"
+		}
+		var status []string
+		if r.Archived {
+			status = append(status, "archived")
+		}
+		if r.Deprecated {
+			status = append(status, "deprecated")
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%d\t%d\t%s\n", n+1, r.Name, baseline, r.UnreleasedCommits, r.DaysBehind, r.DaysSinceRelease, strings.Join(status, ", "))
+	}
+	w.Flush()
+
+	fmt.Fprintln(t.out)
+	if t.message != "" {
+		fmt.Fprintln(t.out, t.message)
+		t.message = ""
+	} else {
+		fmt.Fprintln(t.out, "Type a number to open a repository, s <field> to sort, f <query> to filter, ? for help, q to quit")
+	}
+}
+
+// showRepo lists a repository's unreleased commits a page at a time. It reports true
+// when the user asked to quit.
+func (t *tui) showRepo(repo RepositoryData) bool {
+	page := 0
+	pages := max(1, (len(repo.UnreleasedCommits)+tuiPageSize-1)/tuiPageSize)
+	for {
+		t.clear()
+		fmt.Fprintf(t.out, "%s  %s\n", repo.Name, repo.RepositoryURL)
+		if repo.NeverReleased {
+			fmt.Fprintf(t.out, "Never released, %d commits on %s\n", repo.TotalCommits, repo.DefaultBranch)
+		} else {
+			fmt.Fprintf(t.out, "%d unreleased commits on %s since %s (%s)\n", len(repo.UnreleasedCommits), repo.DefaultBranch, repo.LatestReleaseTag, repo.LatestReleaseTime.Format("2006-01-02"))
+		}
+		if repo.Description != "" {
+			fmt.Fprintln(t.out, repo.Description)
+		}
+		fmt.Fprintln(t.out)
+
+		start := page * tuiPageSize
+		end := min(start+tuiPageSize, len(repo.UnreleasedCommits))
+		w := tabwriter.NewWriter(t.out, 0, 0, 2, ' ', 0)
+		for _, c := range repo.UnreleasedCommits[start:end] {
+			sha := c.SHA
+			if len(sha) > 7 {
+				sha = sha[:7]
+			}
+			subject := commitSubject(c.Message)
+			if isDependencyUpdate(c) {
+				subject = "[deps] " + subject
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", sha, c.Timestamp.Format("2006-01-02"), c.Author, subject)
+		}
+		w.Flush()
+
+		fmt.Fprintln(t.out)
+		fmt.Fprintf(t.out, "Page %d of %d. n next page, p previous page, b back, q quit\n", page+1, pages)
+
+		line, ok := t.prompt()
+		if !ok {
+			return true
+		}
+		switch line {
+		case "n":
+			page = min(page+1, pages-1)
+		case "p":
+			page = max(page-1, 0)
+		case "b", "":
+			return false
+		case "q", "quit":
+			return true
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// commitSHAPattern matches a full hexadecimal commit SHA
+var commitSHAPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// validateRepositoryData checks a decoded data file for missing or inconsistent fields
+// and returns a description of each problem found.
+func validateRepositoryData(repo RepositoryData) []string {
+	var problems []string
+	add := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	required := []struct {
+		field, value string
+	}{
+		{"owner", repo.Owner},
+		{"name", repo.Name},
+		{"default_branch", repo.DefaultBranch},
+		{"repository_url", repo.RepositoryURL},
+	}
+	for _, r := range required {
+		if r.value == "" {
+			add("missing %s", r.field)
+		}
+	}
+
+	if !repo.NeverReleased {
+		if repo.LatestReleaseTag == "" {
+			add("missing latest_release_tag")
+		}
+		if repo.LatestReleaseTime.IsZero() {
+			add("missing latest_release_time")
+		}
+		switch repo.BaselineType {
+		case BaselineRelease, BaselineTag, BaselineBranch:
+		default:
+			add("unknown baseline_type %q", repo.BaselineType)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i, commit := range repo.UnreleasedCommits {
+		switch {
+		case commit.SHA == "":
+			add("unreleased_commits[%d]: missing sha", i)
+		case !commitSHAPattern.MatchString(commit.SHA):
+			add("unreleased_commits[%d]: malformed sha %q", i, commit.SHA)
+		case seen[commit.SHA]:
+			add("unreleased_commits[%d]: duplicate sha %s", i, commit.SHA)
+		}
+		seen[commit.SHA] = true
+
+		if commit.Timestamp.IsZero() {
+			add("unreleased_commits[%d]: missing timestamp", i)
+		}
+		if commit.URL == "" {
+			add("unreleased_commits[%d]: missing url", i)
+		}
+	}
+
+	for i, rel := range repo.ReleaseHistory {
+		if rel.TagName == "" {
+			add("release_history[%d]: missing tag_name", i)
+		}
+		if rel.PublishedAt.IsZero() {
+			add("release_history[%d]: missing published_at", i)
+		}
+	}
+
+	return problems
+}
+
+func runValidate() {
+	dataDir := "data"
+
+	files, err := repositoryDataFiles(dataDir)
+	if err != nil {
+		log.Fatalf("Failed to read data directory: %v", err)
+	}
+	if len(files) == 0 {
+		log.Fatal("No repository JSON files found in data directory. Run with -crawl first.")
+	}
+
+	invalid := 0
+	for _, file := range files {
+		var problems []string
+		repo, err := loadRepositoryData(file)
+		var schemaErr *SchemaError
+		switch {
+		case errors.As(err, &schemaErr):
+			problems = []string{schemaErr.Error()}
+		case err != nil:
+			problems = []string{fmt.Sprintf("cannot be parsed: %v", err)}
+		default:
+			problems = validateRepositoryData(repo)
+			if want := filepath.Base(file); repo.Name != "" && want != repo.Name+".json" {
+				problems = append(problems, fmt.Sprintf("name %q does not match file name %s", repo.Name, want))
+			}
+		}
+
+		if len(problems) == 0 {
+			continue
+		}
+		invalid++
+		fmt.Printf("❌ %s\n", file)
+		for _, problem := range problems {
+			fmt.Printf("   - %s\n", problem)
+		}
+	}
+
+	timestampPath := filepath.Join(dataDir, "timestamp.json")
+	if _, err := loadLastCrawlTimestamp(timestampPath); err != nil && !os.IsNotExist(err) {
+		invalid++
+		fmt.Printf("❌ %s\n   - cannot be parsed: %v\n", timestampPath, err)
+	}
+
+	if invalid > 0 {
+		fmt.Printf("\n%d of %d data files have problems\n", invalid, len(files))
+		os.Exit(1)
+	}
+	fmt.Printf("✅ All %d data files are valid\n", len(files))
+}
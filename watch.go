@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// watchInterval is how often -watch checks the templates and data for changes
+const watchInterval = 500 * time.Millisecond
+
+// liveReloadPath is the server-sent events endpoint that tells open pages to reload
+const liveReloadPath = "/__livereload"
+
+// liveReloadScript is injected into pages served by -watch; it is never written to output/
+const liveReloadScript = `<script>new EventSource("` + liveReloadPath + `").onmessage = function () { location.reload(); };</script>`
+
+// runWatch generates the site, serves output/ on addr, and regenerates whenever a file
+// in TEMPLATE_PATH, the -templates directory, or data/ changes, telling open browser
+// tabs to reload.
+func runWatch(opts GenerateOptions, addr string) error {
+	templateDir := os.Getenv("TEMPLATE_PATH")
+	if templateDir == "" {
+		return errors.New("-watch requires TEMPLATE_PATH to point at the templates being edited")
+	}
+	watched := []string{templateDir, "data"}
+	if opts.TemplatesDir != "" {
+		watched = append(watched, opts.TemplatesDir)
+	}
+
+	health := &siteHealth{dataDir: "data"}
+	reload := &reloadBroker{clients: make(map[chan struct{}]bool)}
+	mux := http.NewServeMux()
+	health.register(mux)
+	mux.Handle(liveReloadPath, reload)
+	mux.Handle("/", liveReloadFileServer("output"))
+
+	fmt.Printf("👀 Serving output/ at http://%s with live reload, watching %s\n", addr, strings.Join(watched, ", "))
+	return serveAndRegenerate(opts, addr, watched, mux, health, reload.notify)
+}
+
+// snapshotFiles summarizes the names, sizes and modification times of the files in dirs,
+// so comparing two snapshots reveals any edit, addition or removal.
+func snapshotFiles(dirs []string) string {
+	var b strings.Builder
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil || info.IsDir() {
+				continue
+			}
+			fmt.Fprintf(&b, "%s|%d|%d\n", filepath.Join(dir, entry.Name()), info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return b.String()
+}
+
+// reloadBroker pushes a reload event to every connected page
+type reloadBroker struct {
+	mu      sync.Mutex
+	clients map[chan struct{}]bool
+}
+
+func (b *reloadBroker) notify() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.clients {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (b *reloadBroker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	ch := make(chan struct{}, 1)
+	b.mu.Lock()
+	b.clients[ch] = true
+	b.mu.Unlock()
+	defer func() {
+		b.mu.Lock()
+		delete(b.clients, ch)
+		b.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ch:
+			fmt.Fprint(w, "data: reload\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// liveReloadFileServer serves dir, adding the live reload script to HTML pages.
+func liveReloadFileServer(dir string) http.Handler {
+	root := http.Dir(dir)
+	files := http.FileServer(root)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := path.Clean("/" + r.URL.Path)
+		if strings.HasSuffix(name, "/") || !strings.Contains(path.Base(name), ".") {
+			name = path.Join(name, "index.html")
+		}
+		if !strings.HasSuffix(name, ".html") {
+			files.ServeHTTP(w, r)
+			return
+		}
+
+		f, err := root.Open(name)
+		if err != nil {
+			files.ServeHTTP(w, r)
+			return
+		}
+		defer f.Close()
+		page, err := io.ReadAll(f)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		page = bytes.Replace(page, []byte("</body>"), []byte(liveReloadScript+"\n</body>"), 1)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		w.Write(page)
+	})
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"time"
+)
+
+// webhookEvent names the event a crawl notification reports
+const webhookEvent = "crawl_completed"
+
+// webhookTimeout bounds each webhook delivery so an unresponsive endpoint cannot stall
+// the crawl
+const webhookTimeout = 10 * time.Second
+
+// WebhookConfig is an endpoint that receives a notification after each crawl
+type WebhookConfig struct {
+	URL       string `json:"url"`
+	SecretEnv string `json:"secret_env,omitempty"`
+}
+
+// CrawlNotification is the JSON payload posted to webhooks after a crawl
+type CrawlNotification struct {
+	Event           string              `json:"event"`
+	Owner           string              `json:"owner"`
+	CrawledAt       time.Time           `json:"crawled_at"`
+	DurationSeconds float64             `json:"duration_seconds"`
+	Summary         NotificationSummary `json:"summary"`
+	Repos           []RepoDelta         `json:"repos"`
+}
+
+// NotificationSummary totals the crawled data across all repositories
+type NotificationSummary struct {
+	Repos                     int `json:"repos"`
+	ReposWithUnreleased       int `json:"repos_with_unreleased"`
+	UnreleasedCommits         int `json:"unreleased_commits"`
+	PreviousUnreleasedCommits int `json:"previous_unreleased_commits"`
+}
+
+// RepoDelta is how a repository changed since the previous crawl
+type RepoDelta struct {
+	Name                      string `json:"name"`
+	RepositoryURL             string `json:"repository_url"`
+	LatestRelease             string `json:"latest_release,omitempty"`
+	PreviousRelease           string `json:"previous_release,omitempty"`
+	UnreleasedCommits         int    `json:"unreleased_commits"`
+	PreviousUnreleasedCommits int    `json:"previous_unreleased_commits"`
+	Delta                     int    `json:"delta"`
+	DaysBehind                int    `json:"days_behind"`
+	SecurityFixDays           int    `json:"security_fix_days"`
+	Released                  bool   `json:"released"`
+	New                       bool   `json:"new"`
+}
+
+// repoSnapshot is the state of a repository's data file before a crawl
+type repoSnapshot struct {
+	RepositoryURL     string
+	LatestRelease     string
+	UnreleasedCommits int
+}
+
+// validateWebhooks checks that every webhook has an http or https URL.
+func validateWebhooks(hooks []WebhookConfig) error {
+	for _, hook := range hooks {
+		u, err := url.Parse(hook.URL)
+		if err != nil {
+			return err
+		}
+		if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("%q is not an http or https URL", hook.URL)
+		}
+	}
+	return nil
+}
+
+// snapshotRepos records the release and unreleased commit count of every data file in
+// dir, to compare a crawl's results against.
+func snapshotRepos(dir string) map[string]repoSnapshot {
+	snapshot := make(map[string]repoSnapshot)
+	files, err := repositoryDataFiles(dir)
+	if err != nil {
+		return snapshot
+	}
+	for _, file := range files {
+		repo, err := loadRepositoryData(file)
+		if err != nil {
+			continue
+		}
+		snapshot[repo.Name] = repoSnapshot{RepositoryURL: repo.RepositoryURL, LatestRelease: repo.LatestReleaseTag, UnreleasedCommits: len(repo.UnreleasedCommits)}
+	}
+	return snapshot
+}
+
+// crawlNotification compares the data files in dir with the snapshot taken before the
+// crawl.
+func crawlNotification(dir, owner string, before map[string]repoSnapshot, crawledAt time.Time, duration time.Duration) CrawlNotification {
+	n := CrawlNotification{
+		Event:           webhookEvent,
+		Owner:           owner,
+		CrawledAt:       crawledAt,
+		DurationSeconds: duration.Seconds(),
+		Repos:           []RepoDelta{},
+	}
+
+	files, _ := repositoryDataFiles(dir)
+	for _, file := range files {
+		repo, err := loadRepositoryData(file)
+		if err != nil {
+			continue
+		}
+		prev, seen := before[repo.Name]
+		delta := RepoDelta{
+			Name:                      repo.Name,
+			RepositoryURL:             repo.RepositoryURL,
+			LatestRelease:             repo.LatestReleaseTag,
+			PreviousRelease:           prev.LatestRelease,
+			UnreleasedCommits:         len(repo.UnreleasedCommits),
+			PreviousUnreleasedCommits: prev.UnreleasedCommits,
+			DaysBehind:                repoDaysBehind(repo),
+			SecurityFixDays:           securityFixDays(repo.UnreleasedCommits),
+			Released:                  seen && repo.LatestReleaseTag != prev.LatestRelease,
+			New:                       !seen,
+		}
+		delta.Delta = delta.UnreleasedCommits - delta.PreviousUnreleasedCommits
+		n.Repos = append(n.Repos, delta)
+
+		n.Summary.Repos++
+		n.Summary.UnreleasedCommits += delta.UnreleasedCommits
+		n.Summary.PreviousUnreleasedCommits += delta.PreviousUnreleasedCommits
+		if delta.UnreleasedCommits > 0 {
+			n.Summary.ReposWithUnreleased++
+		}
+	}
+	slices.SortStableFunc(n.Repos, func(a, b RepoDelta) int {
+		return b.Delta - a.Delta
+	})
+	return n
+}
+
+// sendWebhooks posts the notification to every webhook. Deliveries are independent, so
+// a failing endpoint is reported and the others are still notified.
+func sendWebhooks(ctx context.Context, hooks []WebhookConfig, n CrawlNotification) {
+	body, err := json.Marshal(n)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to encode webhook payload: %v\n", err)
+		return
+	}
+
+	client := &http.Client{Timeout: webhookTimeout}
+	for _, hook := range hooks {
+		if err := postWebhook(ctx, client, hook, body); err != nil {
+			fmt.Printf("⚠️  Webhook %s failed: %v\n", hook.URL, err)
+			continue
+		}
+		fmt.Printf("📣 Notified webhook %s\n", hook.URL)
+	}
+}
+
+// postWebhook delivers body to a single webhook. When the webhook has a secret, the body
+// is signed with HMAC-SHA256 in the X-Unreleased-Commits-Signature-256 header, in the same
+// "sha256=<hex>" form GitHub uses for its own webhooks.
+func postWebhook(ctx context.Context, client *http.Client, hook WebhookConfig, body []byte) error {
+	header := http.Header{}
+	header.Set("X-Unreleased-Commits-Event", webhookEvent)
+	if hook.SecretEnv != "" {
+		secret := os.Getenv(hook.SecretEnv)
+		if secret == "" {
+			return fmt.Errorf("secret environment variable %s is not set", hook.SecretEnv)
+		}
+		header.Set("X-Unreleased-Commits-Signature-256", "sha256="+signPayload(secret, body))
+	}
+	return postJSON(ctx, client, hook.URL, body, header)
+}
+
+// postJSON posts a JSON body to url with the extra headers, failing on a non-2xx status.
+func postJSON(ctx context.Context, client *http.Client, url string, body []byte, header http.Header) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "unreleasedcommits/"+version)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// signPayload returns the hex-encoded HMAC-SHA256 of body keyed with secret.
+func signPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}